@@ -3,20 +3,52 @@ package config
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
 type (
 	Config struct {
-		HTTP  HTTP  `yaml:"http"`
-		Log   Log   `yaml:"logger"`
-		PG    PG    `yaml:"postgres"`
-		Admin Admin `yaml:"admin"`
+		App         App         `yaml:"app"`
+		HTTP        HTTP        `yaml:"http"`
+		Site        Site        `yaml:"site"`
+		Log         Log         `yaml:"logger"`
+		PG          PG          `yaml:"postgres"`
+		Admin       Admin       `yaml:"admin"`
+		Session     Session     `yaml:"session"`
+		Inquiry     Inquiry     `yaml:"inquiry"`
+		Reservation Reservation `yaml:"reservation"`
+		Payment     Payment     `yaml:"payment"`
+		Order       Order       `yaml:"order"`
+		Currency    Currency    `yaml:"currency"`
+		Account     Account     `yaml:"account"`
+		Mail        Mail        `yaml:"mail"`
+		Notify      Notify      `yaml:"notify"`
+		Webhook     Webhook     `yaml:"webhook"`
+		News        News        `yaml:"news"`
+		Search      Search      `yaml:"search"`
+		Pagination  Pagination  `yaml:"pagination"`
+	}
+
+	// App holds the deployment environment: "production" unless stated otherwise,
+	// so development-only features can't be turned on by accident.
+	App struct {
+		Env string `yaml:"env" env:"APP_ENV" env-default:"production"`
 	}
 
 	HTTP struct {
 		Port string `yaml:"port"`
+		// TrustedProxies lists the proxy addresses or CIDRs whose X-Forwarded-For is believed.
+		// Empty trusts no one, so the client IP is the address of the connection.
+		TrustedProxies []string `yaml:"trusted_proxies" env:"HTTP_TRUSTED_PROXIES" env-separator:","`
+	}
+
+	// Site describes the public site. BaseURL is used for absolute links in feeds,
+	// calendars and emails, so it must be the address visitors open.
+	Site struct {
+		BaseURL string `yaml:"base_url" env:"SITE_BASE_URL" env-default:"http://localhost:8080"`
+		Title   string `yaml:"title" env:"SITE_TITLE" env-default:"Beyond Limits"`
 	}
 
 	Log struct {
@@ -30,9 +62,116 @@ type (
 	}
 
 	Admin struct {
-		Login     string `env-required:"true" env:"ADMIN_LOGIN"`
-		Password  string `env-required:"true" env:"ADMIN_PASSWORD"`
-		JWTSecret string `env-required:"true" env:"JWT_SECRET"`
+		Login      string `env-required:"true" env:"ADMIN_LOGIN"`
+		Password   string `env-required:"true" env:"ADMIN_PASSWORD"`
+		JWTSecret  string `env-required:"true" env:"JWT_SECRET"`
+		TOTPIssuer string `env:"ADMIN_TOTP_ISSUER" env-default:"Beyond Limits"`
+		// LoginRateLimit caps login and 2FA attempts per IP within LoginRateWindow, for the API and the panel together.
+		LoginRateLimit  int           `env:"ADMIN_LOGIN_RATE_LIMIT" env-default:"10"`
+		LoginRateWindow time.Duration `env:"ADMIN_LOGIN_RATE_WINDOW" env-default:"15m"`
+	}
+
+	Session struct {
+		TTL          time.Duration `yaml:"ttl" env:"SESSION_TTL" env-default:"12h"`
+		SecureCookie bool          `yaml:"secure_cookie" env:"SESSION_SECURE_COOKIE" env-default:"true"`
+	}
+
+	Inquiry struct {
+		RateLimit  int           `yaml:"rate_limit" env:"INQUIRY_RATE_LIMIT" env-default:"5"`
+		RateWindow time.Duration `yaml:"rate_window" env:"INQUIRY_RATE_WINDOW" env-default:"1h"`
+	}
+
+	Payment struct {
+		// Provider is empty to run the gallery without checkout; WebhookSecret is required otherwise.
+		Provider      string `env:"PAYMENT_PROVIDER"`
+		WebhookSecret string `env:"PAYMENT_WEBHOOK_SECRET"`
+		// FakeEnabled allows the "fake" provider and its payment simulator; development and tests only.
+		FakeEnabled bool `env:"PAYMENT_FAKE_ENABLED"`
+		// PublicURL is prepended to the fake gateway confirmation links; Site.BaseURL when empty.
+		PublicURL string `yaml:"public_url" env:"PAYMENT_PUBLIC_URL"`
+	}
+
+	// Order limits anonymous checkout: a pending order keeps its picture reserved for
+	// PendingTTL at most, and each IP can place RateLimit orders within RateWindow.
+	Order struct {
+		PendingTTL     time.Duration `yaml:"pending_ttl" env:"ORDER_PENDING_TTL" env-default:"30m"`
+		ExpireInterval time.Duration `yaml:"expire_interval" env:"ORDER_EXPIRE_INTERVAL" env-default:"1m"`
+		RateLimit      int           `yaml:"rate_limit" env:"ORDER_RATE_LIMIT" env-default:"5"`
+		RateWindow     time.Duration `yaml:"rate_window" env:"ORDER_RATE_WINDOW" env-default:"1h"`
+	}
+
+	Currency struct {
+		Base string `yaml:"base" env:"CURRENCY_BASE" env-default:"RUB"`
+		// Rates holds the price of one unit of each currency in the base currency, e.g. EUR:100.
+		Rates map[string]float64 `yaml:"rates" env:"CURRENCY_RATES"`
+		// PriceBuckets are the bounds of price ranges offered as gallery filters, in the base currency.
+		PriceBuckets []int `yaml:"price_buckets" env:"CURRENCY_PRICE_BUCKETS" env-default:"10000,50000,100000,300000"`
+	}
+
+	Account struct {
+		SessionTTL time.Duration `yaml:"session_ttl" env:"ACCOUNT_SESSION_TTL" env-default:"720h"`
+		VerifyTTL  time.Duration `yaml:"verify_ttl" env:"ACCOUNT_VERIFY_TTL" env-default:"48h"`
+		ResetTTL   time.Duration `yaml:"reset_ttl" env:"ACCOUNT_RESET_TTL" env-default:"1h"`
+		// RateLimit caps sign-in, registration and email requests per IP within RateWindow.
+		RateLimit  int           `yaml:"rate_limit" env:"ACCOUNT_RATE_LIMIT" env-default:"10"`
+		RateWindow time.Duration `yaml:"rate_window" env:"ACCOUNT_RATE_WINDOW" env-default:"15m"`
+		// PublicURL is prepended to the links in verification and password reset emails;
+		// Site.BaseURL when empty.
+		PublicURL string `yaml:"public_url" env:"ACCOUNT_PUBLIC_URL"`
+	}
+
+	Mail struct {
+		// Provider is "file" to write messages into Dir or "smtp" to send them.
+		Provider     string `yaml:"provider" env:"MAIL_PROVIDER" env-default:"file"`
+		From         string `yaml:"from" env:"MAIL_FROM" env-default:"Beyond Limits <no-reply@localhost>"`
+		Dir          string `yaml:"dir" env:"MAIL_DIR" env-default:"./mail"`
+		SMTPHost     string `yaml:"smtp_host" env:"MAIL_SMTP_HOST"`
+		SMTPPort     int    `yaml:"smtp_port" env:"MAIL_SMTP_PORT" env-default:"587"`
+		SMTPUser     string `env:"MAIL_SMTP_USER"`
+		SMTPPassword string `env:"MAIL_SMTP_PASSWORD"`
+	}
+
+	// Notify configures staff notifications about new inquiries, orders and reservations.
+	// A channel is enabled when its destination is set: EmailTo (sent through the Mail
+	// SMTP server), TelegramChatID or WebhookURL.
+	Notify struct {
+		Attempts       int           `yaml:"attempts" env:"NOTIFY_ATTEMPTS" env-default:"5"`
+		Backoff        time.Duration `yaml:"backoff" env:"NOTIFY_BACKOFF" env-default:"2s"`
+		EmailTo        []string      `yaml:"email_to" env:"NOTIFY_EMAIL_TO" env-separator:","`
+		TelegramToken  string        `env:"NOTIFY_TELEGRAM_TOKEN"`
+		TelegramChatID string        `yaml:"telegram_chat_id" env:"NOTIFY_TELEGRAM_CHAT_ID"`
+		TelegramAPIURL string        `yaml:"telegram_api_url" env:"NOTIFY_TELEGRAM_API_URL" env-default:"https://api.telegram.org"`
+		WebhookURL     string        `yaml:"webhook_url" env:"NOTIFY_WEBHOOK_URL"`
+		WebhookSecret  string        `env:"NOTIFY_WEBHOOK_SECRET"`
+	}
+
+	// Webhook configures delivery of catalog webhooks. A failed delivery is retried
+	// after Backoff, doubling each time up to MaxBackoff, until MaxAttempts is reached.
+	Webhook struct {
+		DispatchInterval time.Duration `yaml:"dispatch_interval" env:"WEBHOOK_DISPATCH_INTERVAL" env-default:"5s"`
+		Timeout          time.Duration `yaml:"timeout" env:"WEBHOOK_TIMEOUT" env-default:"10s"`
+		MaxAttempts      int           `yaml:"max_attempts" env:"WEBHOOK_MAX_ATTEMPTS" env-default:"8"`
+		Backoff          time.Duration `yaml:"backoff" env:"WEBHOOK_BACKOFF" env-default:"30s"`
+		MaxBackoff       time.Duration `yaml:"max_backoff" env:"WEBHOOK_MAX_BACKOFF" env-default:"6h"`
+	}
+
+	News struct {
+		// PublishInterval is how often scheduled news are checked and marked published.
+		PublishInterval time.Duration `yaml:"publish_interval" env:"NEWS_PUBLISH_INTERVAL" env-default:"1m"`
+	}
+
+	Search struct {
+		// SuggestCacheTTL is how long suggestions for the same input are served from memory.
+		SuggestCacheTTL time.Duration `yaml:"suggest_cache_ttl" env:"SEARCH_SUGGEST_CACHE_TTL" env-default:"30s"`
+	}
+
+	Pagination struct {
+		// CursorSecret signs pagination cursors; the JWT secret is used when empty.
+		CursorSecret string `env:"PAGINATION_CURSOR_SECRET"`
+	}
+
+	Reservation struct {
+		ReleaseInterval time.Duration `yaml:"release_interval" env:"RESERVATION_RELEASE_INTERVAL" env-default:"1m"`
 	}
 )
 
@@ -53,6 +192,14 @@ func NewConfig() (*Config, error) {
 		return nil, err
 	}
 
+	cfg.Site.BaseURL = strings.TrimRight(cfg.Site.BaseURL, "/")
+	if cfg.Payment.PublicURL == "" {
+		cfg.Payment.PublicURL = cfg.Site.BaseURL
+	}
+	if cfg.Account.PublicURL == "" {
+		cfg.Account.PublicURL = cfg.Site.BaseURL
+	}
+
 	return cfg, nil
 }
 
@@ -1,23 +1,33 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/alexKudryavtsev-web/beyond-limits-app/config"
 	v1 "github.com/alexKudryavtsev-web/beyond-limits-app/internal/controller/http/v1"
 	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase"
 	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase/repo"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase/webapi"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/cursor"
 	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/httpserver"
 	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/markdown"
 	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/postgres"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/scheduler"
 	"github.com/gin-gonic/gin"
 )
 
 func Run(cfg *config.Config) {
+	if cfg.Payment.FakeEnabled && cfg.App.Env == "production" {
+		log.Fatal("PAYMENT_FAKE_ENABLED can't be set in production")
+	}
+
 	logger, err := logger.New(cfg.Log.Level, cfg.Log.Destination)
 	if err != nil {
 		log.Fatalf("can't init logger: %s", err)
@@ -30,22 +40,165 @@ func Run(cfg *config.Config) {
 	}
 	defer pg.Close()
 
-	adminUseCase := usecase.NewAuthUseCase(cfg.Admin)
+	var mailer usecase.Mailer
+	switch cfg.Mail.Provider {
+	case "file":
+		mailer = webapi.NewFileMailer(cfg.Mail.From, cfg.Mail.Dir)
+	case "smtp":
+		mailer, err = webapi.NewSMTPMailer(cfg.Mail.From, cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUser, cfg.Mail.SMTPPassword)
+		if err != nil {
+			log.Fatalf("can't create smtp mailer: %s", err)
+		}
+	default:
+		log.Fatalf("unknown mail provider: %s", cfg.Mail.Provider)
+	}
+
+	notifier, err := newNotifier(cfg, mailer, logger)
+	if err != nil {
+		log.Fatalf("can't init notifications: %s", err)
+	}
+	defer notifier.Stop()
+
+	authRepo := repo.NewAuthRepo(pg)
+	adminUseCase := usecase.NewAuthUseCase(cfg.Admin, authRepo)
+
+	sessionsRepo := repo.NewSessionsRepo(pg)
+	sessionsUseCase := usecase.NewSessionsUseCase(sessionsRepo, cfg.Session.TTL)
+
+	auditRepo := repo.NewAuditRepo(pg)
+	auditUseCase := usecase.NewAuditUseCase(auditRepo)
+
+	apiKeysRepo := repo.NewAPIKeysRepo(pg)
+	apiKeysUseCase := usecase.NewAuditedAPIKeysUseCase(usecase.NewAPIKeysUseCase(apiKeysRepo), auditUseCase, logger)
 
 	referencesRepo := repo.NewReferencesRepo(pg)
-	referencesUseCase := usecase.NewReferencesUseCase(referencesRepo)
+	referencesUseCase := usecase.NewAuditedReferencesUseCase(usecase.NewReferencesUseCase(referencesRepo), auditUseCase, logger)
+
+	webhooksRepo := repo.NewWebhooksRepo(pg)
+	webhooksUseCase := usecase.NewAuditedWebhooksUseCase(
+		usecase.NewWebhooksUseCase(cfg.Webhook, webhooksRepo, webapi.NewHTTPWebhookSender(cfg.Webhook.Timeout)), auditUseCase, logger)
+
+	cursorSecret := cfg.Pagination.CursorSecret
+	if cursorSecret == "" {
+		cursorSecret = cfg.Admin.JWTSecret
+	}
+	cursors := cursor.New(cursorSecret)
 
 	picturesRepo := repo.NewPicturesRepo(pg)
-	picturesUseCase := usecase.NewPicturesUseCase(picturesRepo)
+	exchangeRates := usecase.NewExchangeRates(cfg.Currency.Base, cfg.Currency.Rates)
+	discountsRepo := repo.NewDiscountsRepo(pg)
+	discountsUseCase := usecase.NewAuditedDiscountsUseCase(usecase.NewDiscountsUseCase(discountsRepo, exchangeRates), auditUseCase, logger)
+	pricingUseCase := usecase.NewPricingUseCase(exchangeRates, discountsRepo)
+	picturesUseCase := usecase.NewAuditedPicturesUseCase(usecase.NewPicturesUseCase(picturesRepo, pricingUseCase, cfg.Currency.PriceBuckets, cursors), auditUseCase, logger)
+
+	exhibitionsRepo := repo.NewExhibitionsRepo(pg)
+	exhibitionsUseCase := usecase.NewAuditedExhibitionsUseCase(usecase.NewExhibitionsUseCase(exhibitionsRepo, picturesUseCase), auditUseCase, logger)
+
+	ordersRepo := repo.NewOrdersRepo(pg)
+	reservationsRepo := repo.NewReservationsRepo(pg)
+	reservationsUseCase := usecase.NewAuditedReservationsUseCase(
+		usecase.NewReservationsUseCase(reservationsRepo, picturesUseCase, ordersRepo, notifier), auditUseCase, logger)
+
+	var paymentProvider usecase.PaymentProvider
+	switch cfg.Payment.Provider {
+	case "":
+		logger.Info("payment provider is not configured, checkout is disabled")
+	case "fake":
+		if !cfg.Payment.FakeEnabled {
+			log.Fatal("fake payment provider requires PAYMENT_FAKE_ENABLED")
+		}
+		paymentProvider = webapi.NewFakePaymentProvider(cfg.Payment.WebhookSecret, cfg.Payment.PublicURL)
+	default:
+		log.Fatalf("unknown payment provider: %s", cfg.Payment.Provider)
+	}
+	if paymentProvider != nil && cfg.Payment.WebhookSecret == "" {
+		log.Fatal("PAYMENT_WEBHOOK_SECRET is required with a payment provider")
+	}
+
+	ordersUseCase := usecase.NewAuditedOrdersUseCase(
+		usecase.NewOrdersUseCase(ordersRepo, picturesUseCase, reservationsRepo, paymentProvider, notifier, cfg.Order.PendingTTL), auditUseCase, logger)
+
+	wishlistsRepo := repo.NewWishlistsRepo(pg)
+	wishlistsUseCase := usecase.NewWishlistsUseCase(wishlistsRepo, picturesUseCase)
+
+	customersRepo := repo.NewCustomersRepo(pg)
+	customersUseCase := usecase.NewCustomersUseCase(cfg.Account, customersRepo, mailer, wishlistsUseCase)
+
+	inquiriesRepo := repo.NewInquiriesRepo(pg)
+	inquiriesUseCase := usecase.NewAuditedInquiriesUseCase(usecase.NewInquiriesUseCase(inquiriesRepo, picturesUseCase, notifier), auditUseCase, logger)
 
 	newsRepo := repo.NewNewsRepo(pg)
-	newsUseCase := usecase.NewNewsUseCase(newsRepo)
+	newsUseCase := usecase.NewAuditedNewsUseCase(usecase.NewNewsUseCase(newsRepo, markdown.New(), picturesUseCase, referencesUseCase, cursors), auditUseCase, logger)
+
+	searchRepo := repo.NewSearchRepo(pg)
+	searchUseCase := usecase.NewSearchUseCase(searchRepo, cfg.Search.SuggestCacheTTL)
 
 	handler := gin.New()
-	v1.NewRouter(handler, logger, cfg.Admin, adminUseCase, referencesUseCase, picturesUseCase, newsUseCase)
+	// rate limits, audit records and sessions all rely on the client IP
+	if err := handler.SetTrustedProxies(cfg.HTTP.TrustedProxies); err != nil {
+		log.Fatalf("can't set trusted proxies: %s", err)
+	}
+	v1.NewRouter(
+		handler,
+		logger,
+		cfg,
+		adminUseCase,
+		sessionsUseCase,
+		apiKeysUseCase,
+		auditUseCase,
+		referencesUseCase,
+		picturesUseCase,
+		newsUseCase,
+		inquiriesUseCase,
+		reservationsUseCase,
+		ordersUseCase,
+		paymentProvider,
+		discountsUseCase,
+		wishlistsUseCase,
+		customersUseCase,
+		webhooksUseCase,
+		exhibitionsUseCase,
+		searchUseCase,
+	)
 
 	httpServer := httpserver.New(handler, httpserver.Port(cfg.HTTP.Port))
 
+	reservationsReleaser := scheduler.New(cfg.Reservation.ReleaseInterval, func(ctx context.Context) {
+		released, err := reservationsUseCase.ReleaseExpiredReservations(ctx)
+		for _, r := range released {
+			logger.Info("reservation %d released: picture %d hold expired at %s", r.ID, r.PictureID, r.HoldUntil.Format(time.RFC3339))
+		}
+		if err != nil {
+			logger.Error(fmt.Errorf("app - Run - ReleaseExpiredReservations: %w", err))
+		}
+	})
+
+	ordersExpirer := scheduler.New(cfg.Order.ExpireInterval, func(ctx context.Context) {
+		cancelled, err := ordersUseCase.CancelExpiredOrders(ctx)
+		for _, o := range cancelled {
+			logger.Info("order %d cancelled: not paid since %s", o.ID, o.CreatedAt.Format(time.RFC3339))
+		}
+		if err != nil {
+			logger.Error(fmt.Errorf("app - Run - CancelExpiredOrders: %w", err))
+		}
+	})
+
+	newsPublisher := scheduler.New(cfg.News.PublishInterval, func(ctx context.Context) {
+		published, err := newsUseCase.PublishScheduledNews(ctx)
+		for _, id := range published {
+			logger.Info("news %d published on schedule", id)
+		}
+		if err != nil {
+			logger.Error(fmt.Errorf("app - Run - PublishScheduledNews: %w", err))
+		}
+	})
+
+	webhooksDispatcher := scheduler.New(cfg.Webhook.DispatchInterval, func(ctx context.Context) {
+		if err := webhooksUseCase.DispatchWebhooks(ctx); err != nil {
+			logger.Error(fmt.Errorf("app - Run - DispatchWebhooks: %w", err))
+		}
+	})
+
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
 
@@ -59,4 +212,9 @@ func Run(cfg *config.Config) {
 	if err := httpServer.Shutdown(); err != nil {
 		logger.Error(fmt.Errorf("app - Run - httpServer.Shutdown: %w", err))
 	}
+
+	reservationsReleaser.Stop()
+	ordersExpirer.Stop()
+	webhooksDispatcher.Stop()
+	newsPublisher.Stop()
 }
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/config"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/notify"
+)
+
+// newNotifier builds the staff notification channels enabled in the config.
+// Deliveries run in the background and failed attempts are logged.
+func newNotifier(cfg *config.Config, mailer usecase.Mailer, l logger.Interface) (*notify.Async, error) {
+	var channels []notify.Channel
+
+	if len(cfg.Notify.EmailTo) > 0 {
+		channels = append(channels, notify.Channel{
+			Name:     "email",
+			Notifier: mailNotifier{mailer: mailer, to: cfg.Notify.EmailTo},
+		})
+	}
+
+	if cfg.Notify.TelegramChatID != "" {
+		if cfg.Notify.TelegramToken == "" {
+			return nil, fmt.Errorf("NOTIFY_TELEGRAM_TOKEN is required for telegram notifications")
+		}
+		channels = append(channels, notify.Channel{
+			Name: "telegram",
+			Notifier: notify.NewTelegram(cfg.Notify.TelegramToken, cfg.Notify.TelegramChatID,
+				notify.TelegramAPIURL(cfg.Notify.TelegramAPIURL)),
+		})
+	}
+
+	if cfg.Notify.WebhookURL != "" {
+		channels = append(channels, notify.Channel{
+			Name:     "webhook",
+			Notifier: notify.NewWebhook(cfg.Notify.WebhookURL, notify.WebhookSecret(cfg.Notify.WebhookSecret)),
+		})
+	}
+
+	for _, c := range channels {
+		l.Info("notifications: %s enabled", c.Name)
+	}
+
+	return notify.NewAsync(channels,
+		notify.Attempts(cfg.Notify.Attempts),
+		notify.Backoff(cfg.Notify.Backoff),
+		notify.OnError(func(channel string, msg notify.Message, err error) {
+			l.Error(fmt.Errorf("app - notify - %s - %s: %w", channel, msg.Event, err))
+		}),
+	), nil
+}
+
+// mailNotifier sends staff notifications through the same mailer as customer emails.
+type mailNotifier struct {
+	mailer usecase.Mailer
+	to     []string
+}
+
+func (n mailNotifier) Notify(ctx context.Context, msg notify.Message) error {
+	for _, to := range n.to {
+		if err := n.mailer.Send(ctx, entity.Email{To: to, Subject: msg.Title, Body: msg.Text}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
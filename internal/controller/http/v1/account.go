@@ -0,0 +1,390 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/middleware"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	_customerCookie = "bl_customer"
+	// _customerHeader carries the session token for API clients that don't keep cookies.
+	_customerHeader = "X-Customer-Token"
+	_customerKey    = "customer"
+)
+
+func customerToken(c *gin.Context) string {
+	if token, err := c.Cookie(_customerCookie); err == nil && token != "" {
+		return token
+	}
+	return c.GetHeader(_customerHeader)
+}
+
+// customerMiddleware resolves the customer session, if any. Requests without
+// a valid session go on anonymously.
+func customerMiddleware(l logger.Interface, customers usecase.Customers) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := customerToken(c); token != "" {
+			customer, err := customers.Authenticate(c.Request.Context(), token)
+			switch {
+			case err == nil:
+				c.Set(_customerKey, customer)
+			case !errors.Is(err, entity.ErrCustomerSessionExpired):
+				l.Error(err, "http - v1 - customerMiddleware")
+			}
+		}
+		c.Next()
+	}
+}
+
+// currentCustomer returns the signed-in customer or nil.
+func currentCustomer(c *gin.Context) *entity.Customer {
+	customer, _ := c.Get(_customerKey)
+	if customer, ok := customer.(*entity.Customer); ok {
+		return customer
+	}
+	return nil
+}
+
+func currentCustomerID(c *gin.Context) *uint64 {
+	if customer := currentCustomer(c); customer != nil {
+		return &customer.ID
+	}
+	return nil
+}
+
+func requireCustomer(c *gin.Context) {
+	if currentCustomer(c) == nil {
+		errorResponse(c, http.StatusUnauthorized, "sign in required")
+		return
+	}
+	c.Next()
+}
+
+func setCustomerCookie(c *gin.Context, token string, expiresAt time.Time, secure bool) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(_customerCookie, token, int(time.Until(expiresAt).Seconds()), "/", "", secure, true)
+}
+
+func clearCustomerCookie(c *gin.Context, secure bool) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(_customerCookie, "", -1, "/", "", secure, true)
+}
+
+type accountRoutes struct {
+	customers    usecase.Customers
+	orders       usecase.Orders
+	inquiries    usecase.Inquiries
+	visitors     visitorCookie
+	secureCookie bool
+	l            logger.Interface
+}
+
+func newAccountRoutes(
+	handler *gin.RouterGroup,
+	l logger.Interface,
+	customers usecase.Customers,
+	orders usecase.Orders,
+	inquiries usecase.Inquiries,
+	wishlists usecase.Wishlists,
+	limiter *ratelimit.Limiter,
+	secureCookie bool,
+) {
+	r := accountRoutes{
+		customers:    customers,
+		orders:       orders,
+		inquiries:    inquiries,
+		visitors:     visitorCookie{uc: wishlists, secure: secureCookie},
+		secureCookie: secureCookie,
+		l:            l,
+	}
+
+	h := handler.Group("/account")
+	{
+		limited := h.Group("", middleware.RateLimit(limiter))
+		limited.POST("/register", r.doRegister)
+		limited.POST("/login", r.doLogin)
+		limited.POST("/verify/resend", r.doResendVerification)
+		limited.POST("/password-reset", r.doRequestPasswordReset)
+
+		h.POST("/verify", r.doVerifyEmail)
+		h.POST("/password-reset/confirm", r.doResetPassword)
+		h.POST("/logout", r.doLogout)
+
+		private := h.Group("", requireCustomer)
+		private.GET("", r.doGetAccount)
+		private.GET("/orders", r.doGetAccountOrders)
+		private.GET("/inquiries", r.doGetAccountInquiries)
+	}
+}
+
+// @Summary     Register
+// @Description Create a customer account and email a verification link
+// @ID          account-register
+// @Tags        account
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.CustomerRegisterRequest true "Customer data"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     409 {object} response
+// @Failure     429 {object} response
+// @Failure     500 {object} response
+// @Router      /account/register [post]
+func (r *accountRoutes) doRegister(ctx *gin.Context) {
+	var req entity.CustomerRegisterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if _, err := r.customers.Register(ctx.Request.Context(), req); err != nil {
+		if errors.Is(err, entity.ErrCustomerExists) {
+			errorResponse(ctx, http.StatusConflict, "customer with this email already exists")
+			return
+		}
+		r.l.Error(err, "http - v1 - doRegister")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary     Verify email
+// @Description Confirm the email address with the token from the verification link
+// @ID          account-verify
+// @Tags        account
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.CustomerTokenRequest true "Token"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /account/verify [post]
+func (r *accountRoutes) doVerifyEmail(ctx *gin.Context) {
+	var req entity.CustomerTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := r.customers.VerifyEmail(ctx.Request.Context(), req.Token); err != nil {
+		if errors.Is(err, entity.ErrInvalidCustomerToken) {
+			errorResponse(ctx, http.StatusBadRequest, "invalid or expired token")
+			return
+		}
+		r.l.Error(err, "http - v1 - doVerifyEmail")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary     Resend verification
+// @Description Email a new verification link. Always succeeds so addresses can't be probed
+// @ID          account-verify-resend
+// @Tags        account
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.PasswordResetRequest true "Email"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     429 {object} response
+// @Failure     500 {object} response
+// @Router      /account/verify/resend [post]
+func (r *accountRoutes) doResendVerification(ctx *gin.Context) {
+	var req entity.PasswordResetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := r.customers.ResendVerification(ctx.Request.Context(), req.Email); err != nil {
+		r.l.Error(err, "http - v1 - doResendVerification")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary     Log in
+// @Description Start a customer session. The token is returned and also set as a cookie;
+// @Description the anonymous wishlist of the visitor is moved to the account
+// @ID          account-login
+// @Tags        account
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.CustomerLoginRequest true "Credentials"
+// @Success     200 {object} entity.CustomerLoginResponse
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     403 {object} response
+// @Failure     429 {object} response
+// @Failure     500 {object} response
+// @Router      /account/login [post]
+func (r *accountRoutes) doLogin(ctx *gin.Context) {
+	var req entity.CustomerLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	resp, err := r.customers.Login(ctx.Request.Context(), req, r.visitors.owner(ctx).VisitorID)
+	if err != nil {
+		switch {
+		case errors.Is(err, entity.ErrInvalidCustomerLogin):
+			errorResponse(ctx, http.StatusUnauthorized, "invalid email or password")
+		case errors.Is(err, entity.ErrEmailNotVerified):
+			errorResponse(ctx, http.StatusForbidden, "email is not verified")
+		default:
+			r.l.Error(err, "http - v1 - doLogin")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		}
+		return
+	}
+
+	setCustomerCookie(ctx, resp.Token, resp.ExpiresAt, r.secureCookie)
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// @Summary     Log out
+// @Description End the current customer session
+// @ID          account-logout
+// @Tags        account
+// @Produce     json
+// @Success     200
+// @Failure     500 {object} response
+// @Router      /account/logout [post]
+func (r *accountRoutes) doLogout(ctx *gin.Context) {
+	if token := customerToken(ctx); token != "" {
+		if err := r.customers.Logout(ctx.Request.Context(), token); err != nil {
+			r.l.Error(err, "http - v1 - doLogout")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+			return
+		}
+	}
+
+	clearCustomerCookie(ctx, r.secureCookie)
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary     Request password reset
+// @Description Email a password reset link. Always succeeds so addresses can't be probed
+// @ID          account-password-reset
+// @Tags        account
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.PasswordResetRequest true "Email"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     429 {object} response
+// @Failure     500 {object} response
+// @Router      /account/password-reset [post]
+func (r *accountRoutes) doRequestPasswordReset(ctx *gin.Context) {
+	var req entity.PasswordResetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := r.customers.RequestPasswordReset(ctx.Request.Context(), req.Email); err != nil {
+		r.l.Error(err, "http - v1 - doRequestPasswordReset")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary     Reset password
+// @Description Set a new password with the token from the reset link. All sessions are ended
+// @ID          account-password-reset-confirm
+// @Tags        account
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.PasswordResetConfirmRequest true "Token and new password"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /account/password-reset/confirm [post]
+func (r *accountRoutes) doResetPassword(ctx *gin.Context) {
+	var req entity.PasswordResetConfirmRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := r.customers.ResetPassword(ctx.Request.Context(), req); err != nil {
+		if errors.Is(err, entity.ErrInvalidCustomerToken) {
+			errorResponse(ctx, http.StatusBadRequest, "invalid or expired token")
+			return
+		}
+		r.l.Error(err, "http - v1 - doResetPassword")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary     Get account
+// @Description Get the signed-in customer
+// @ID          account-get
+// @Tags        account
+// @Produce     json
+// @Success     200 {object} entity.Customer
+// @Failure     401 {object} response
+// @Router      /account [get]
+func (r *accountRoutes) doGetAccount(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, currentCustomer(ctx))
+}
+
+// @Summary     Get account orders
+// @Description Get orders placed by the signed-in customer, newest first
+// @ID          account-orders
+// @Tags        account
+// @Produce     json
+// @Success     200 {array} entity.Order
+// @Failure     401 {object} response
+// @Failure     500 {object} response
+// @Router      /account/orders [get]
+func (r *accountRoutes) doGetAccountOrders(ctx *gin.Context) {
+	orders, err := r.orders.GetOrders(ctx.Request.Context(), entity.OrderFilter{CustomerID: currentCustomer(ctx).ID})
+	if err != nil {
+		r.l.Error(err, "http - v1 - doGetAccountOrders")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, orders)
+}
+
+// @Summary     Get account inquiries
+// @Description Get purchase requests left by the signed-in customer, newest first
+// @ID          account-inquiries
+// @Tags        account
+// @Produce     json
+// @Success     200 {array} entity.Inquiry
+// @Failure     401 {object} response
+// @Failure     500 {object} response
+// @Router      /account/inquiries [get]
+func (r *accountRoutes) doGetAccountInquiries(ctx *gin.Context) {
+	inquiries, err := r.inquiries.GetInquiries(ctx.Request.Context(), entity.InquiryFilter{CustomerID: currentCustomer(ctx).ID})
+	if err != nil {
+		r.l.Error(err, "http - v1 - doGetAccountInquiries")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, inquiries)
+}
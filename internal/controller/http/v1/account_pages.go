@@ -0,0 +1,232 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/gin-contrib/multitemplate"
+	"github.com/gin-gonic/gin"
+)
+
+func addAccountTemplates(renderer multitemplate.Renderer) {
+	pages := map[string]string{
+		"account":                 "profile.html",
+		"account-login":           "login.html",
+		"account-register":        "register.html",
+		"account-forgot-password": "forgot_password.html",
+		"account-reset-password":  "reset_password.html",
+		"account-message":         "message.html",
+	}
+
+	for name, file := range pages {
+		renderer.AddFromFiles(name,
+			"web/templates/base.html",
+			"web/templates/price.html",
+			"web/templates/account/"+file)
+	}
+}
+
+func (r *frontendRoutes) accountPage(c *gin.Context) {
+	customer := currentCustomer(c)
+	if customer == nil {
+		c.Redirect(http.StatusSeeOther, "/account/login")
+		return
+	}
+
+	orders, err := r.ordersUC.GetOrders(c.Request.Context(), entity.OrderFilter{CustomerID: customer.ID})
+	if err != nil {
+		r.l.Error(err, "http - v1 - accountPage - get orders")
+	}
+
+	inquiries, err := r.inquiriesUC.GetInquiries(c.Request.Context(), entity.InquiryFilter{CustomerID: customer.ID})
+	if err != nil {
+		r.l.Error(err, "http - v1 - accountPage - get inquiries")
+	}
+
+	c.HTML(http.StatusOK, "account", gin.H{
+		"Title":     "Личный кабинет",
+		"Customer":  customer,
+		"Orders":    orders,
+		"Inquiries": inquiries,
+	})
+}
+
+func (r *frontendRoutes) accountLoginPage(c *gin.Context) {
+	if currentCustomer(c) != nil {
+		c.Redirect(http.StatusSeeOther, "/account")
+		return
+	}
+
+	c.HTML(http.StatusOK, "account-login", gin.H{"Title": "Вход"})
+}
+
+func (r *frontendRoutes) doAccountLogin(c *gin.Context) {
+	var req entity.CustomerLoginRequest
+	data := gin.H{"Title": "Вход"}
+
+	switch {
+	case c.ShouldBind(&req) != nil:
+		data["Error"] = "Укажите email и пароль"
+	case !r.accountLimiter.Allow(c.ClientIP()):
+		data["Error"] = "Слишком много попыток, попробуйте позже"
+	default:
+		resp, err := r.customersUC.Login(c.Request.Context(), req, r.visitors.owner(c).VisitorID)
+		switch {
+		case err == nil:
+			setCustomerCookie(c, resp.Token, resp.ExpiresAt, r.visitors.secure)
+			c.Redirect(http.StatusSeeOther, "/account")
+			return
+		case errors.Is(err, entity.ErrInvalidCustomerLogin):
+			data["Error"] = "Неверный email или пароль"
+		case errors.Is(err, entity.ErrEmailNotVerified):
+			data["Error"] = "Подтвердите email по ссылке из письма"
+		default:
+			r.l.Error(err, "http - v1 - doAccountLogin")
+			data["Error"] = "Не удалось войти, попробуйте позже"
+		}
+	}
+	data["Email"] = req.Email
+
+	c.HTML(http.StatusOK, "account-login", data)
+}
+
+func (r *frontendRoutes) doAccountLogout(c *gin.Context) {
+	if token := customerToken(c); token != "" {
+		if err := r.customersUC.Logout(c.Request.Context(), token); err != nil {
+			r.l.Error(err, "http - v1 - doAccountLogout")
+		}
+	}
+
+	clearCustomerCookie(c, r.visitors.secure)
+	c.Redirect(http.StatusSeeOther, "/")
+}
+
+func (r *frontendRoutes) accountRegisterPage(c *gin.Context) {
+	c.HTML(http.StatusOK, "account-register", gin.H{"Title": "Регистрация"})
+}
+
+func (r *frontendRoutes) doAccountRegister(c *gin.Context) {
+	var req entity.CustomerRegisterRequest
+	data := gin.H{"Title": "Регистрация"}
+
+	switch {
+	case c.ShouldBind(&req) != nil:
+		data["Error"] = "Укажите имя, корректный email и пароль не короче 8 символов"
+	case !r.accountLimiter.Allow(c.ClientIP()):
+		data["Error"] = "Слишком много попыток, попробуйте позже"
+	default:
+		_, err := r.customersUC.Register(c.Request.Context(), req)
+		switch {
+		case err == nil:
+			c.HTML(http.StatusOK, "account-message", gin.H{
+				"Title":   "Почти готово",
+				"Message": "Мы отправили письмо на " + req.Email + ". Перейдите по ссылке из него, чтобы подтвердить адрес.",
+			})
+			return
+		case errors.Is(err, entity.ErrCustomerExists):
+			data["Error"] = "Аккаунт с таким email уже есть"
+		default:
+			r.l.Error(err, "http - v1 - doAccountRegister")
+			data["Error"] = "Не удалось зарегистрироваться, попробуйте позже"
+		}
+	}
+	req.Password = ""
+	data["Form"] = req
+
+	c.HTML(http.StatusOK, "account-register", data)
+}
+
+func (r *frontendRoutes) accountVerifyPage(c *gin.Context) {
+	data := gin.H{
+		"Title":   "Email подтверждён",
+		"Message": "Спасибо! Теперь можно войти в личный кабинет.",
+	}
+
+	if err := r.customersUC.VerifyEmail(c.Request.Context(), c.Query("token")); err != nil {
+		if !errors.Is(err, entity.ErrInvalidCustomerToken) {
+			r.l.Error(err, "http - v1 - accountVerifyPage")
+		}
+		data = gin.H{
+			"Title":   "Ссылка недействительна",
+			"Message": "Ссылка устарела или уже использована. Запросите новое письмо на странице восстановления пароля.",
+		}
+	}
+
+	c.HTML(http.StatusOK, "account-message", data)
+}
+
+func (r *frontendRoutes) doAccountResendVerification(c *gin.Context) {
+	r.sendAccountEmail(c, "http - v1 - doAccountResendVerification", r.customersUC.ResendVerification)
+}
+
+func (r *frontendRoutes) accountForgotPasswordPage(c *gin.Context) {
+	c.HTML(http.StatusOK, "account-forgot-password", gin.H{"Title": "Восстановление пароля"})
+}
+
+func (r *frontendRoutes) doAccountForgotPassword(c *gin.Context) {
+	r.sendAccountEmail(c, "http - v1 - doAccountForgotPassword", r.customersUC.RequestPasswordReset)
+}
+
+// sendAccountEmail handles the forms that email a link to the given address. The
+// answer doesn't depend on whether the address is registered.
+func (r *frontendRoutes) sendAccountEmail(c *gin.Context, op string, send func(ctx context.Context, email string) error) {
+	var req entity.PasswordResetRequest
+	data := gin.H{"Title": "Восстановление пароля"}
+
+	switch {
+	case c.ShouldBind(&req) != nil:
+		data["Error"] = "Укажите корректный email"
+	case !r.accountLimiter.Allow(c.ClientIP()):
+		data["Error"] = "Слишком много попыток, попробуйте позже"
+	default:
+		if err := send(c.Request.Context(), req.Email); err != nil {
+			r.l.Error(err, op)
+			data["Error"] = "Не удалось отправить письмо, попробуйте позже"
+			break
+		}
+		c.HTML(http.StatusOK, "account-message", gin.H{
+			"Title":   "Проверьте почту",
+			"Message": "Если аккаунт с адресом " + req.Email + " существует, мы отправили на него письмо со ссылкой.",
+		})
+		return
+	}
+
+	c.HTML(http.StatusOK, "account-forgot-password", data)
+}
+
+func (r *frontendRoutes) accountResetPasswordPage(c *gin.Context) {
+	c.HTML(http.StatusOK, "account-reset-password", gin.H{
+		"Title": "Новый пароль",
+		"Token": c.Query("token"),
+	})
+}
+
+func (r *frontendRoutes) doAccountResetPassword(c *gin.Context) {
+	var req entity.PasswordResetConfirmRequest
+	data := gin.H{"Title": "Новый пароль"}
+
+	if err := c.ShouldBind(&req); err != nil {
+		data["Error"] = "Пароль должен быть не короче 8 символов"
+		data["Token"] = req.Token
+		c.HTML(http.StatusOK, "account-reset-password", data)
+		return
+	}
+
+	if err := r.customersUC.ResetPassword(c.Request.Context(), req); err != nil {
+		if !errors.Is(err, entity.ErrInvalidCustomerToken) {
+			r.l.Error(err, "http - v1 - doAccountResetPassword")
+		}
+		c.HTML(http.StatusOK, "account-message", gin.H{
+			"Title":   "Ссылка недействительна",
+			"Message": "Ссылка устарела или уже использована. Запросите восстановление пароля ещё раз.",
+		})
+		return
+	}
+
+	c.HTML(http.StatusOK, "account-message", gin.H{
+		"Title":   "Пароль изменён",
+		"Message": "Войдите с новым паролем. Остальные сеансы завершены.",
+	})
+}
@@ -0,0 +1,933 @@
+package v1
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/config"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/middleware"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/ratelimit"
+	"github.com/gin-contrib/multitemplate"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	_sessionCookie   = "bl_admin_session"
+	_sessionKey      = "admin_session"
+	_csrfField       = "csrf_token"
+	_csrfHeader      = "X-CSRF-Token"
+	_adminPanelRoot  = "/admin"
+	_adminLoginPage  = "/admin/login"
+	_adminHomePage   = "/admin/pictures"
+	_adminNewsPage   = "/admin/news"
+	_adminRefsPage   = "/admin/references"
+	_adminPanelTitle = "Админка"
+)
+
+type adminPanelRoutes struct {
+	authUC     usecase.Auth
+	limiter    *ratelimit.Limiter
+	sessionsUC usecase.Sessions
+	picturesUC usecase.Pictures
+	newsUC     usecase.News
+	refUC      usecase.References
+	l          logger.Interface
+	cfg        config.Session
+}
+
+func NewAdminPanelRouter(
+	handler *gin.Engine,
+	l logger.Interface,
+	cfg config.Session,
+	auditUC usecase.Audit,
+	authUC usecase.Auth,
+	limiter *ratelimit.Limiter,
+	sessionsUC usecase.Sessions,
+	picturesUC usecase.Pictures,
+	newsUC usecase.News,
+	referencesUC usecase.References,
+) {
+	r := &adminPanelRoutes{
+		authUC:     authUC,
+		limiter:    limiter,
+		sessionsUC: sessionsUC,
+		picturesUC: picturesUC,
+		newsUC:     newsUC,
+		refUC:      referencesUC,
+		l:          l,
+		cfg:        cfg,
+	}
+
+	panel := handler.Group(_adminPanelRoot, auditMiddleware(auditUC, l, _adminPanelRoot+"/"))
+	{
+		panel.GET("/login", r.loginPage)
+		panel.POST("/login", r.doLogin)
+		panel.POST("/login/2fa", r.doLoginTwoFactor)
+
+		protected := panel.Group("", r.requireSession)
+		{
+			protected.GET("", r.homePage)
+			protected.POST("/logout", r.doLogout)
+
+			protected.GET("/pictures", r.picturesPage)
+			protected.POST("/pictures", r.doCreatePicture)
+			protected.GET("/pictures/:id", r.picturePage)
+			protected.POST("/pictures/:id", r.doUpdatePicture)
+			protected.POST("/pictures/:id/delete", r.doDeletePicture)
+			protected.POST("/pictures/:id/status", r.doChangePictureStatus)
+			protected.POST("/pictures/:id/photo", r.doUploadPhoto)
+			protected.POST("/pictures/:id/gallery", r.doUploadPhoto)
+			protected.POST("/pictures/:id/gallery/:photo_id/delete", r.doDeletePhoto)
+
+			protected.GET("/news", r.newsPage)
+			protected.POST("/news", r.doCreateNews)
+			protected.GET("/news/:id", r.newsItemPage)
+			protected.GET("/news/:id/preview", r.newsPreviewPage)
+			protected.POST("/news/:id", r.doUpdateNews)
+			protected.POST("/news/:id/delete", r.doDeleteNews)
+			protected.POST("/news/:id/images", r.doUploadNewsImage)
+			protected.POST("/news/:id/cover", r.doUploadNewsCover)
+			protected.POST("/news/:id/links", r.doSetNewsLinks)
+
+			protected.GET("/references", r.referencesPage)
+			protected.POST("/references/:kind", r.doCreateReference)
+			protected.POST("/references/:kind/:id/delete", r.doDeleteReference)
+		}
+	}
+}
+
+func addAdminPanelTemplates(renderer multitemplate.Renderer) {
+	pages := map[string]string{
+		"admin-login":      "login.html",
+		"admin-login-2fa":  "login_2fa.html",
+		"admin-pictures":   "pictures.html",
+		"admin-picture":    "picture.html",
+		"admin-news":       "news.html",
+		"admin-news-item":  "news_item.html",
+		"admin-references": "references.html",
+	}
+
+	for name, file := range pages {
+		renderer.AddFromFiles(name,
+			"web/templates/admin/base.html",
+			"web/templates/admin/partials.html",
+			"web/templates/status.html",
+			"web/templates/price.html",
+			"web/templates/admin/"+file)
+	}
+}
+
+// requireSession authenticates the panel by the session cookie and checks
+// the CSRF token on every state-changing request.
+func (r *adminPanelRoutes) requireSession(c *gin.Context) {
+	token, err := c.Cookie(_sessionCookie)
+	if err != nil || token == "" {
+		c.Redirect(http.StatusSeeOther, _adminLoginPage)
+		c.Abort()
+		return
+	}
+
+	session, err := r.sessionsUC.GetSession(c.Request.Context(), token)
+	if err != nil {
+		if !errors.Is(err, entity.ErrSessionNotFound) {
+			r.l.Error(err, "http - v1 - requireSession")
+		}
+		r.clearSessionCookie(c)
+		c.Redirect(http.StatusSeeOther, _adminLoginPage)
+		c.Abort()
+		return
+	}
+
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		sent := c.GetHeader(_csrfHeader)
+		if sent == "" {
+			sent = c.PostForm(_csrfField)
+		}
+		if subtle.ConstantTimeCompare([]byte(sent), []byte(session.CSRFToken)) != 1 {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+	}
+
+	c.Set(_sessionKey, session)
+	middleware.SetActor(c, middleware.AdminActor)
+	c.Next()
+}
+
+func (r *adminPanelRoutes) render(c *gin.Context, code int, name, title string, data gin.H) {
+	if data == nil {
+		data = gin.H{}
+	}
+
+	data["Title"] = _adminPanelTitle + " — " + title
+	data["Message"] = c.Query("message")
+	if _, ok := data["Error"]; !ok {
+		data["Error"] = c.Query("error")
+	}
+	if session, ok := c.Get(_sessionKey); ok {
+		data["CSRFToken"] = session.(*entity.AdminSession).CSRFToken
+	}
+
+	c.HTML(code, name, data)
+}
+
+// done finishes a mutation. Row-level htmx requests get an empty body that replaces
+// the target element, boosted and plain form posts are redirected back.
+func (r *adminPanelRoutes) done(c *gin.Context, location, message string) {
+	if c.GetHeader("HX-Request") == "true" && c.GetHeader("HX-Boosted") == "" {
+		c.Status(http.StatusOK)
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, location+"?message="+url.QueryEscape(message))
+}
+
+func (r *adminPanelRoutes) fail(c *gin.Context, location, message string) {
+	location += "?error=" + url.QueryEscape(message)
+
+	if c.GetHeader("HX-Request") == "true" && c.GetHeader("HX-Boosted") == "" {
+		c.Header("HX-Redirect", location)
+		c.Status(http.StatusOK)
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, location)
+}
+
+func (r *adminPanelRoutes) startSession(c *gin.Context) {
+	token, _, err := r.sessionsUC.CreateSession(c.Request.Context(), c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		r.l.Error(err, "http - v1 - startSession")
+		r.render(c, http.StatusInternalServerError, "admin-login", "Вход", gin.H{"Error": "Не удалось начать сессию"})
+		return
+	}
+
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(_sessionCookie, token, int(r.cfg.TTL.Seconds()), _adminPanelRoot, "", r.cfg.SecureCookie, true)
+
+	c.Redirect(http.StatusSeeOther, _adminHomePage)
+}
+
+func (r *adminPanelRoutes) clearSessionCookie(c *gin.Context) {
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(_sessionCookie, "", -1, _adminPanelRoot, "", r.cfg.SecureCookie, true)
+}
+
+type panelLoginForm struct {
+	Login    string `form:"login" binding:"required"`
+	Password string `form:"password" binding:"required"`
+}
+
+type panelTwoFactorForm struct {
+	ChallengeToken string `form:"challenge_token" binding:"required"`
+	Code           string `form:"code" binding:"required"`
+}
+
+func (r *adminPanelRoutes) loginPage(c *gin.Context) {
+	if token, err := c.Cookie(_sessionCookie); err == nil && token != "" {
+		if _, err := r.sessionsUC.GetSession(c.Request.Context(), token); err == nil {
+			c.Redirect(http.StatusSeeOther, _adminHomePage)
+			return
+		}
+	}
+
+	r.render(c, http.StatusOK, "admin-login", "Вход", nil)
+}
+
+func (r *adminPanelRoutes) doLogin(c *gin.Context) {
+	var form panelLoginForm
+	if err := c.ShouldBind(&form); err != nil {
+		r.render(c, http.StatusBadRequest, "admin-login", "Вход", gin.H{"Error": "Введите логин и пароль"})
+		return
+	}
+	if !r.limiter.Allow(c.ClientIP()) {
+		r.render(c, http.StatusTooManyRequests, "admin-login", "Вход", gin.H{"Error": "Слишком много попыток входа, попробуйте позже"})
+		return
+	}
+
+	resp, err := r.authUC.Login(c.Request.Context(), form.Login, form.Password)
+	if err != nil {
+		if !errors.Is(err, entity.ErrInvalidCredentials) {
+			r.l.Error(err, "http - v1 - panel doLogin")
+		}
+		r.render(c, http.StatusUnauthorized, "admin-login", "Вход", gin.H{"Error": "Неверный логин или пароль"})
+		return
+	}
+
+	if resp.TwoFactorRequired {
+		r.render(c, http.StatusOK, "admin-login-2fa", "Вход", gin.H{"ChallengeToken": resp.ChallengeToken})
+		return
+	}
+
+	r.startSession(c)
+}
+
+func (r *adminPanelRoutes) doLoginTwoFactor(c *gin.Context) {
+	var form panelTwoFactorForm
+	if err := c.ShouldBind(&form); err != nil {
+		r.render(c, http.StatusBadRequest, "admin-login", "Вход", gin.H{"Error": "Войдите заново"})
+		return
+	}
+	if !r.limiter.Allow(c.ClientIP()) {
+		r.render(c, http.StatusTooManyRequests, "admin-login", "Вход", gin.H{"Error": "Слишком много попыток входа, попробуйте позже"})
+		return
+	}
+
+	if _, err := r.authUC.CompleteLogin(c.Request.Context(), form.ChallengeToken, form.Code); err != nil {
+		if errors.Is(err, entity.ErrInvalidTwoFactorCode) {
+			r.render(c, http.StatusUnauthorized, "admin-login-2fa", "Вход", gin.H{
+				"ChallengeToken": form.ChallengeToken,
+				"Error":          "Неверный код",
+			})
+			return
+		}
+		if !errors.Is(err, entity.ErrInvalidChallenge) {
+			r.l.Error(err, "http - v1 - panel doLoginTwoFactor")
+		}
+		r.render(c, http.StatusUnauthorized, "admin-login", "Вход", gin.H{"Error": "Сессия входа истекла, войдите заново"})
+		return
+	}
+
+	r.startSession(c)
+}
+
+func (r *adminPanelRoutes) doLogout(c *gin.Context) {
+	if token, err := c.Cookie(_sessionCookie); err == nil {
+		if err := r.sessionsUC.DeleteSession(c.Request.Context(), token); err != nil {
+			r.l.Error(err, "http - v1 - panel doLogout")
+		}
+	}
+
+	r.clearSessionCookie(c)
+	c.Redirect(http.StatusSeeOther, _adminLoginPage)
+}
+
+func (r *adminPanelRoutes) homePage(c *gin.Context) {
+	c.Redirect(http.StatusSeeOther, _adminHomePage)
+}
+
+type panelPictureForm struct {
+	Title           string `form:"title" binding:"required"`
+	Description     string `form:"description"`
+	Price           int    `form:"price" binding:"required"`
+	Currency        string `form:"currency"`
+	AuthorID        uint64 `form:"author_id" binding:"required"`
+	DimensionsID    uint64 `form:"dimensions_id" binding:"required"`
+	WorkTechniqueID uint64 `form:"work_technique_id" binding:"required"`
+	GenreID         uint64 `form:"genre_id" binding:"required"`
+}
+
+func (r *adminPanelRoutes) referencesData(c *gin.Context, data gin.H) bool {
+	ctx := c.Request.Context()
+
+	genres, err := r.refUC.GetGenres(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - panel referencesData - genres")
+		return false
+	}
+	authors, err := r.refUC.GetAuthors(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - panel referencesData - authors")
+		return false
+	}
+	dimensions, err := r.refUC.GetDimensions(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - panel referencesData - dimensions")
+		return false
+	}
+	techniques, err := r.refUC.GetWorkTechniques(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - panel referencesData - work techniques")
+		return false
+	}
+
+	data["Genres"] = genres
+	data["Authors"] = authors
+	data["Dimensions"] = dimensions
+	data["WorkTechniques"] = techniques
+	return true
+}
+
+func (r *adminPanelRoutes) picturesPage(c *gin.Context) {
+	pictures, err := r.picturesUC.GetPictures(c.Request.Context(), entity.PictureFilter{})
+	if err != nil {
+		r.l.Error(err, "http - v1 - panel picturesPage")
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	data := gin.H{"Pictures": pictures}
+	if !r.referencesData(c, data) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	r.render(c, http.StatusOK, "admin-pictures", "Картины", data)
+}
+
+func (r *adminPanelRoutes) doCreatePicture(c *gin.Context) {
+	var form panelPictureForm
+	if err := c.ShouldBind(&form); err != nil {
+		r.fail(c, _adminHomePage, "Заполните все поля картины")
+		return
+	}
+
+	id, err := r.picturesUC.CreatePicture(c.Request.Context(), entity.PictureCreateRequest{
+		Title:           form.Title,
+		Description:     form.Description,
+		Price:           form.Price,
+		Currency:        form.Currency,
+		AuthorID:        form.AuthorID,
+		DimensionsID:    form.DimensionsID,
+		WorkTechniqueID: form.WorkTechniqueID,
+		GenreID:         form.GenreID,
+	})
+	if err != nil {
+		if errors.Is(err, entity.ErrUnknownCurrency) {
+			r.fail(c, _adminHomePage, "Неизвестная валюта")
+			return
+		}
+		r.l.Error(err, "http - v1 - panel doCreatePicture")
+		r.fail(c, _adminHomePage, "Не удалось создать картину")
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, _adminHomePage+"/"+strconv.FormatUint(id, 10)+"?message="+url.QueryEscape("Картина создана, загрузите фото"))
+}
+
+func (r *adminPanelRoutes) picturePage(c *gin.Context) {
+	pictureID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	picture, err := r.picturesUC.GetPictureByID(c.Request.Context(), pictureID, entity.PriceQuery{})
+	if err != nil {
+		if errors.Is(err, entity.ErrPictureNotFound) {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		r.l.Error(err, "http - v1 - panel picturePage")
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	data := gin.H{"Picture": picture, "Statuses": entity.PictureStatuses}
+	if !r.referencesData(c, data) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	r.render(c, http.StatusOK, "admin-picture", picture.Title, data)
+}
+
+func (r *adminPanelRoutes) doUpdatePicture(c *gin.Context) {
+	pictureID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+	location := _adminHomePage + "/" + c.Param("id")
+
+	var form panelPictureForm
+	if err := c.ShouldBind(&form); err != nil {
+		r.fail(c, location, "Заполните все поля картины")
+		return
+	}
+
+	err = r.picturesUC.UpdatePicture(c.Request.Context(), pictureID, entity.PictureUpdateRequest{
+		Title:           &form.Title,
+		Description:     &form.Description,
+		Price:           &form.Price,
+		Currency:        &form.Currency,
+		AuthorID:        &form.AuthorID,
+		DimensionsID:    &form.DimensionsID,
+		WorkTechniqueID: &form.WorkTechniqueID,
+		GenreID:         &form.GenreID,
+	})
+	if err != nil {
+		if errors.Is(err, entity.ErrUnknownCurrency) {
+			r.fail(c, location, "Неизвестная валюта")
+			return
+		}
+		r.l.Error(err, "http - v1 - panel doUpdatePicture")
+		r.fail(c, location, "Не удалось сохранить картину")
+		return
+	}
+
+	r.done(c, location, "Картина сохранена")
+}
+
+func (r *adminPanelRoutes) doDeletePicture(c *gin.Context) {
+	pictureID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	if err := r.picturesUC.DeletePicture(c.Request.Context(), pictureID); err != nil {
+		r.l.Error(err, "http - v1 - panel doDeletePicture")
+		r.fail(c, _adminHomePage, "Не удалось удалить картину")
+		return
+	}
+
+	r.done(c, _adminHomePage, "Картина удалена")
+}
+
+type panelPictureStatusForm struct {
+	Status   string `form:"status" binding:"required"`
+	Override bool   `form:"override"`
+}
+
+func (r *adminPanelRoutes) doChangePictureStatus(c *gin.Context) {
+	pictureID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+	location := _adminHomePage + "/" + c.Param("id")
+
+	var form panelPictureStatusForm
+	if err := c.ShouldBind(&form); err != nil {
+		r.fail(c, location, "Выберите статус")
+		return
+	}
+
+	err = r.picturesUC.ChangePictureStatus(c.Request.Context(), pictureID, entity.PictureStatusRequest{
+		Status:   entity.PictureStatus(form.Status),
+		Override: form.Override,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, entity.ErrPictureStatusTransition):
+			r.fail(c, location, "Такой переход статуса запрещён, отметьте «Принудительно», если уверены")
+		case errors.Is(err, entity.ErrPictureStatusConflict):
+			r.fail(c, location, "Статус уже изменили, обновите страницу")
+		default:
+			r.l.Error(err, "http - v1 - panel doChangePictureStatus")
+			r.fail(c, location, "Не удалось изменить статус")
+		}
+		return
+	}
+
+	r.done(c, location, "Статус изменён")
+}
+
+func (r *adminPanelRoutes) doUploadPhoto(c *gin.Context) {
+	pictureID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+	location := _adminHomePage + "/" + c.Param("id")
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		r.fail(c, location, "Выберите файл")
+		return
+	}
+
+	_, err = r.picturesUC.UploadPhoto(c.Request.Context(), file, entity.PhotoUploadRequest{
+		PictureID: pictureID,
+		IsMain:    c.FullPath() == _adminHomePage+"/:id/photo",
+	})
+	if err != nil {
+		r.l.Error(err, "http - v1 - panel doUploadPhoto")
+		r.fail(c, location, "Не удалось загрузить фото")
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, location+"?message="+url.QueryEscape("Фото загружено"))
+}
+
+func (r *adminPanelRoutes) doDeletePhoto(c *gin.Context) {
+	pictureID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+	photoID, err := strconv.ParseUint(c.Param("photo_id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+	location := _adminHomePage + "/" + c.Param("id")
+
+	if _, err := r.picturesUC.DeletePhoto(c.Request.Context(), pictureID, photoID); err != nil {
+		r.l.Error(err, "http - v1 - panel doDeletePhoto")
+		r.fail(c, location, "Не удалось удалить фото")
+		return
+	}
+
+	r.done(c, location, "Фото удалено")
+}
+
+type panelNewsForm struct {
+	Title   string            `form:"title" binding:"required"`
+	Content string            `form:"content" binding:"required"`
+	Status  entity.NewsStatus `form:"status"`
+	// PublishAt comes from a datetime-local input, so it is in the server time zone.
+	PublishAt time.Time `form:"publish_at" time_format:"2006-01-02T15:04"`
+}
+
+func (f panelNewsForm) publishAt() *time.Time {
+	if f.PublishAt.IsZero() {
+		return nil
+	}
+	return &f.PublishAt
+}
+
+func newsFormError(err error, fallback string) string {
+	switch {
+	case errors.Is(err, entity.ErrNewsPublishAtRequired):
+		return "Укажите дату публикации"
+	case errors.Is(err, entity.ErrUnknownNewsStatus):
+		return "Неизвестный статус"
+	}
+	return fallback
+}
+
+func (r *adminPanelRoutes) newsPage(c *gin.Context) {
+	filter := entity.NewsFilter{Status: entity.NewsStatus(c.Query("status"))}
+	if filter.Status != "" && !filter.Status.Valid() {
+		filter.Status = ""
+	}
+
+	news, err := r.newsUC.GetAdminNews(c.Request.Context(), filter)
+	if err != nil {
+		r.l.Error(err, "http - v1 - panel newsPage")
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	r.render(c, http.StatusOK, "admin-news", "Новости", gin.H{
+		"News":     news,
+		"Status":   string(filter.Status),
+		"Statuses": entity.NewsStatuses,
+	})
+}
+
+func (r *adminPanelRoutes) doCreateNews(c *gin.Context) {
+	var form panelNewsForm
+	if err := c.ShouldBind(&form); err != nil {
+		r.fail(c, _adminNewsPage, "Заполните заголовок и текст")
+		return
+	}
+
+	_, err := r.newsUC.CreateNews(c.Request.Context(), entity.NewsCreateRequest{
+		Title:     form.Title,
+		Content:   form.Content,
+		Status:    form.Status,
+		PublishAt: form.publishAt(),
+	})
+	if err != nil {
+		r.l.Error(err, "http - v1 - panel doCreateNews")
+		r.fail(c, _adminNewsPage, newsFormError(err, "Не удалось создать новость"))
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, _adminNewsPage+"?message="+url.QueryEscape("Новость создана"))
+}
+
+func (r *adminPanelRoutes) newsItemPage(c *gin.Context) {
+	newsID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	news, err := r.newsUC.PreviewNews(c.Request.Context(), newsID)
+	if err != nil {
+		if errors.Is(err, entity.ErrNewsNotFound) {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		r.l.Error(err, "http - v1 - panel newsItemPage")
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	pictures, err := r.picturesUC.GetPictures(c.Request.Context(), entity.PictureFilter{})
+	if err != nil {
+		r.l.Error(err, "http - v1 - panel newsItemPage - pictures")
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	authors, err := r.refUC.GetAuthors(c.Request.Context())
+	if err != nil {
+		r.l.Error(err, "http - v1 - panel newsItemPage - authors")
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	linkedPictures := make(map[uint64]bool, len(news.PictureIDs))
+	for _, id := range news.PictureIDs {
+		linkedPictures[id] = true
+	}
+	linkedAuthors := make(map[uint64]bool, len(news.AuthorIDs))
+	for _, id := range news.AuthorIDs {
+		linkedAuthors[id] = true
+	}
+
+	r.render(c, http.StatusOK, "admin-news-item", news.Title, gin.H{
+		"News":           news,
+		"Statuses":       entity.NewsStatuses,
+		"Pictures":       pictures,
+		"Authors":        authors,
+		"LinkedPictures": linkedPictures,
+		"LinkedAuthors":  linkedAuthors,
+	})
+}
+
+// newsPreviewPage shows news of any status with the public page template.
+func (r *adminPanelRoutes) newsPreviewPage(c *gin.Context) {
+	newsID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	news, err := r.newsUC.PreviewNews(c.Request.Context(), newsID)
+	if err != nil {
+		if errors.Is(err, entity.ErrNewsNotFound) {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		r.l.Error(err, "http - v1 - panel newsPreviewPage")
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	c.HTML(http.StatusOK, "news-item", gin.H{
+		"Title":   news.Title,
+		"News":    news,
+		"Preview": true,
+	})
+}
+
+func (r *adminPanelRoutes) doUpdateNews(c *gin.Context) {
+	newsID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+	location := _adminNewsPage + "/" + c.Param("id")
+
+	var form panelNewsForm
+	if err := c.ShouldBind(&form); err != nil {
+		r.fail(c, location, "Заполните заголовок и текст")
+		return
+	}
+
+	req := entity.NewsUpdateRequest{
+		Title:     &form.Title,
+		Content:   &form.Content,
+		PublishAt: form.publishAt(),
+	}
+	if form.Status != "" {
+		req.Status = &form.Status
+	}
+
+	err = r.newsUC.UpdateNews(c.Request.Context(), newsID, req)
+	if err != nil {
+		r.l.Error(err, "http - v1 - panel doUpdateNews")
+		r.fail(c, location, newsFormError(err, "Не удалось сохранить новость"))
+		return
+	}
+
+	r.done(c, location, "Новость сохранена")
+}
+
+func (r *adminPanelRoutes) doDeleteNews(c *gin.Context) {
+	newsID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	if err := r.newsUC.DeleteNews(c.Request.Context(), newsID); err != nil {
+		r.l.Error(err, "http - v1 - panel doDeleteNews")
+		r.fail(c, _adminNewsPage, "Не удалось удалить новость")
+		return
+	}
+
+	r.done(c, _adminNewsPage, "Новость удалена")
+}
+
+// doUploadNewsImage returns the Markdown snippet in the page message for the editor to paste.
+func (r *adminPanelRoutes) doUploadNewsImage(c *gin.Context) {
+	if _, err := strconv.ParseUint(c.Param("id"), 10, 64); err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+	location := _adminNewsPage + "/" + c.Param("id")
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		r.fail(c, location, "Выберите файл")
+		return
+	}
+
+	image, err := r.newsUC.UploadNewsImage(c.Request.Context(), file)
+	if err != nil {
+		r.l.Error(err, "http - v1 - panel doUploadNewsImage")
+		r.fail(c, location, "Не удалось загрузить изображение")
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, location+"?message="+url.QueryEscape("Изображение загружено, вставьте в текст: "+image.Markdown))
+}
+
+func (r *adminPanelRoutes) doUploadNewsCover(c *gin.Context) {
+	newsID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+	location := _adminNewsPage + "/" + c.Param("id")
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		r.fail(c, location, "Выберите файл")
+		return
+	}
+
+	if _, err := r.newsUC.UploadNewsCover(c.Request.Context(), newsID, file); err != nil {
+		r.l.Error(err, "http - v1 - panel doUploadNewsCover")
+		r.fail(c, location, "Не удалось загрузить обложку")
+		return
+	}
+
+	r.done(c, location, "Обложка загружена")
+}
+
+// panelNewsLinksForm comes from multiple selects; pictures keep the order they are listed in.
+type panelNewsLinksForm struct {
+	PictureIDs []uint64 `form:"picture_ids"`
+	AuthorIDs  []uint64 `form:"author_ids"`
+}
+
+func (r *adminPanelRoutes) doSetNewsLinks(c *gin.Context) {
+	newsID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+	location := _adminNewsPage + "/" + c.Param("id")
+
+	var form panelNewsLinksForm
+	if err := c.ShouldBind(&form); err != nil {
+		r.fail(c, location, "Некорректный список работ")
+		return
+	}
+
+	err = r.newsUC.SetNewsLinks(c.Request.Context(), newsID, entity.NewsLinksRequest{
+		PictureIDs: form.PictureIDs,
+		AuthorIDs:  form.AuthorIDs,
+	})
+	if err != nil {
+		r.l.Error(err, "http - v1 - panel doSetNewsLinks")
+		r.fail(c, location, "Не удалось сохранить связи")
+		return
+	}
+
+	r.done(c, location, "Связи сохранены")
+}
+
+func (r *adminPanelRoutes) referencesPage(c *gin.Context) {
+	data := gin.H{}
+	if !r.referencesData(c, data) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	r.render(c, http.StatusOK, "admin-references", "Справочники", data)
+}
+
+func (r *adminPanelRoutes) doCreateReference(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var err error
+	switch c.Param("kind") {
+	case "genres":
+		name := c.PostForm("name")
+		if name == "" {
+			r.fail(c, _adminRefsPage, "Введите название жанра")
+			return
+		}
+		_, err = r.refUC.CreateGenre(ctx, name)
+	case "authors":
+		fullName := c.PostForm("full_name")
+		if fullName == "" {
+			r.fail(c, _adminRefsPage, "Введите имя автора")
+			return
+		}
+		_, err = r.refUC.CreateAuthor(ctx, fullName)
+	case "dimensions":
+		width, errW := strconv.Atoi(c.PostForm("width"))
+		height, errH := strconv.Atoi(c.PostForm("height"))
+		if errW != nil || errH != nil || width <= 0 || height <= 0 {
+			r.fail(c, _adminRefsPage, "Введите ширину и высоту")
+			return
+		}
+		_, err = r.refUC.CreateDimension(ctx, width, height)
+	case "work-techniques":
+		name := c.PostForm("name")
+		if name == "" {
+			r.fail(c, _adminRefsPage, "Введите название техники")
+			return
+		}
+		_, err = r.refUC.CreateWorkTechnique(ctx, name)
+	default:
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	if err != nil {
+		r.l.Error(err, "http - v1 - panel doCreateReference")
+		r.fail(c, _adminRefsPage, "Не удалось сохранить значение")
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, _adminRefsPage+"?message="+url.QueryEscape("Значение добавлено"))
+}
+
+func (r *adminPanelRoutes) doDeleteReference(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	switch c.Param("kind") {
+	case "genres":
+		err = r.refUC.DeleteGenre(ctx, id)
+	case "authors":
+		err = r.refUC.DeleteAuthor(ctx, id)
+	case "dimensions":
+		err = r.refUC.DeleteDimension(ctx, id)
+	case "work-techniques":
+		err = r.refUC.DeleteWorkTechnique(ctx, id)
+	default:
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	if err != nil {
+		r.l.Error(err, "http - v1 - panel doDeleteReference")
+		r.fail(c, _adminRefsPage, "Не удалось удалить значение, возможно оно используется")
+		return
+	}
+
+	r.done(c, _adminRefsPage, "Значение удалено")
+}
@@ -0,0 +1,122 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type apiKeysRoutes struct {
+	u usecase.APIKeys
+	l logger.Interface
+}
+
+func newAPIKeysRoutes(handler *gin.RouterGroup, l logger.Interface, k usecase.APIKeys, authMiddleware gin.HandlerFunc) {
+	r := apiKeysRoutes{k, l}
+
+	adminHandler := handler.Group("/admin", authMiddleware, middleware.RequireAdmin())
+	{
+		adminHandler.GET("/api-keys", r.doGetAPIKeys)
+		adminHandler.POST("/api-keys", r.doCreateAPIKey)
+		adminHandler.DELETE("/api-keys/:id", r.doRevokeAPIKey)
+	}
+}
+
+// @Summary     Get API keys
+// @Description Get all API keys without secrets
+// @ID          get-api-keys
+// @Tags        admin
+// @Produce     json
+// @Success     200 {array} entity.APIKey
+// @Failure     401 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/api-keys [get]
+// @Security    BearerAuth
+func (r *apiKeysRoutes) doGetAPIKeys(ctx *gin.Context) {
+	keys, err := r.u.GetAPIKeys(ctx.Request.Context())
+	if err != nil {
+		r.l.Error(err, "http - v1 - doGetAPIKeys")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, keys)
+}
+
+// @Summary     Create API key
+// @Description Mint a named API key with scopes. The key is returned only once
+// @ID          create-api-key
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.APIKeyCreateRequest true "API key data"
+// @Success     200 {object} entity.APIKeyCreateResponse
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/api-keys [post]
+// @Security    BearerAuth
+func (r *apiKeysRoutes) doCreateAPIKey(ctx *gin.Context) {
+	var req entity.APIKeyCreateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		r.l.Error(err, "http - v1 - doCreateAPIKey")
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	key, err := r.u.CreateAPIKey(ctx.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, entity.ErrUnknownScope) || errors.Is(err, entity.ErrExpiryInPast) {
+			errorResponse(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		r.l.Error(err, "http - v1 - doCreateAPIKey")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, key)
+}
+
+// @Summary     Revoke API key
+// @Description Revoke API key by ID
+// @ID          revoke-api-key
+// @Tags        admin
+// @Produce     json
+// @Param       id path int true "API key ID"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     403 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/api-keys/{id} [delete]
+// @Security    BearerAuth
+func (r *apiKeysRoutes) doRevokeAPIKey(ctx *gin.Context) {
+	id := ctx.Param("id")
+	keyID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	if err := r.u.RevokeAPIKey(ctx.Request.Context(), keyID); err != nil {
+		if errors.Is(err, entity.ErrAPIKeyNotFound) {
+			errorResponse(ctx, http.StatusNotFound, "api key not found")
+			return
+		}
+		r.l.Error(err, "http - v1 - doRevokeAPIKey")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
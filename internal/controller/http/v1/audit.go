@@ -0,0 +1,130 @@
+package v1
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type auditRoutes struct {
+	u usecase.Audit
+	l logger.Interface
+}
+
+func newAuditRoutes(handler *gin.RouterGroup, l logger.Interface, a usecase.Audit, authMiddleware gin.HandlerFunc) {
+	r := auditRoutes{a, l}
+
+	adminHandler := handler.Group("/admin", authMiddleware, middleware.RequireAdmin())
+	{
+		adminHandler.GET("/audit", r.doGetAuditLog)
+	}
+}
+
+// @Summary     Get audit log
+// @Description Get admin mutations, newest first
+// @ID          get-audit-log
+// @Tags        admin
+// @Produce     json
+// @Param       actor       query string false "Actor, e.g. admin or api_key:<name>"
+// @Param       action      query string false "create, update or delete"
+// @Param       entity_type query string false "Entity type, e.g. picture"
+// @Param       entity_id   query string false "Entity ID"
+// @Param       from        query string false "From time (RFC 3339)"
+// @Param       to          query string false "To time (RFC 3339)"
+// @Param       limit       query int    false "Limit (default 50, max 500)"
+// @Param       offset      query int    false "Offset"
+// @Success     200 {array} entity.AuditRecord
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/audit [get]
+// @Security    BearerAuth
+func (r *auditRoutes) doGetAuditLog(ctx *gin.Context) {
+	var filter entity.AuditFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+		return
+	}
+
+	records, err := r.u.GetAuditLog(ctx.Request.Context(), filter)
+	if err != nil {
+		r.l.Error(err, "http - v1 - doGetAuditLog")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, records)
+}
+
+// _auditSkippedRoutes are session endpoints under the audited prefix: they change
+// no entity, so there is nothing to record.
+var _auditSkippedRoutes = map[string]bool{
+	"login":     true,
+	"login/2fa": true,
+	"logout":    true,
+}
+
+// auditMiddleware attaches request details for the audited usecases and records
+// successful admin mutations that no usecase has recorded on its own.
+func auditMiddleware(a usecase.Audit, l logger.Interface, prefix string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		action := auditAction(ctx.Request.Method)
+		route, ok := strings.CutPrefix(ctx.FullPath(), prefix)
+		if action == "" || !ok || _auditSkippedRoutes[route] {
+			ctx.Next()
+			return
+		}
+
+		meta := &usecase.AuditMeta{
+			IP:    ctx.ClientIP(),
+			Actor: func() string { return middleware.Actor(ctx) },
+		}
+		ctx.Request = ctx.Request.WithContext(usecase.WithAuditMeta(ctx.Request.Context(), meta))
+
+		ctx.Next()
+
+		// unauthenticated requests are not mutations worth recording
+		if meta.Recorded() || middleware.Actor(ctx) == "" || ctx.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+
+		err := a.Record(ctx.Request.Context(), action, auditEntityType(ctx.FullPath(), prefix), ctx.Param("id"), nil, nil)
+		if err != nil {
+			l.Error(err, "http - v1 - auditMiddleware")
+		}
+	}
+}
+
+func auditAction(method string) string {
+	switch method {
+	case http.MethodPost:
+		return entity.AuditActionCreate
+	case http.MethodPut, http.MethodPatch:
+		return entity.AuditActionUpdate
+	case http.MethodDelete:
+		return entity.AuditActionDelete
+	default:
+		return ""
+	}
+}
+
+// auditEntityType turns a route like /api/admin/pictures/:id/gallery into "pictures.gallery".
+func auditEntityType(fullPath, prefix string) string {
+	parts := strings.Split(strings.TrimPrefix(fullPath, prefix), "/")
+
+	segments := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part == "" || strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
+			continue
+		}
+		segments = append(segments, part)
+	}
+
+	return strings.Join(segments, ".")
+}
@@ -2,11 +2,14 @@
 package v1
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
 	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase"
 	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/middleware"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/ratelimit"
 	"github.com/gin-gonic/gin"
 )
 
@@ -15,10 +18,25 @@ type authRoutes struct {
 	l logger.Interface
 }
 
-func newAuthRoutes(handler *gin.RouterGroup, l logger.Interface, a usecase.Auth) {
+func newAuthRoutes(
+	handler *gin.RouterGroup,
+	l logger.Interface,
+	a usecase.Auth,
+	limiter *ratelimit.Limiter,
+	authMiddleware gin.HandlerFunc,
+) {
 	r := authRoutes{a, l}
 
-	handler.POST("/admin/login", r.doLogin)
+	handler.POST("/admin/login", middleware.RateLimit(limiter), r.doLogin)
+	handler.POST("/admin/login/2fa", middleware.RateLimit(limiter), r.doLoginTwoFactor)
+
+	adminHandler := handler.Group("/admin", authMiddleware, middleware.RequireAdmin())
+	{
+		adminHandler.POST("/2fa/enroll", r.doEnrollTOTP)
+		adminHandler.POST("/2fa/confirm", r.doConfirmTOTP)
+		adminHandler.POST("/2fa/recovery-codes", r.doRegenerateRecoveryCodes)
+		adminHandler.POST("/2fa/disable", r.doDisableTOTP)
+	}
 }
 
 type doLoginRequest struct {
@@ -27,7 +45,7 @@ type doLoginRequest struct {
 }
 
 // @Summary     Admin login
-// @Description Login admin
+// @Description Login admin. When two-factor authentication is enabled a short-lived challenge token is returned instead of the access token
 // @ID          admin-login
 // @Tags        auth
 // @Accept      json
@@ -36,6 +54,7 @@ type doLoginRequest struct {
 // @Success     200 {object} entity.AuthResponse
 // @Failure     400 {object} response
 // @Failure     401 {object} response
+// @Failure     429 {object} response
 // @Failure     500 {object} response
 // @Router      /admin/login [post]
 func (a *authRoutes) doLogin(ctx *gin.Context) {
@@ -46,11 +65,175 @@ func (a *authRoutes) doLogin(ctx *gin.Context) {
 		return
 	}
 
-	token, err := a.u.Login(ctx.Request.Context(), request.Login, request.Password)
+	resp, err := a.u.Login(ctx.Request.Context(), request.Login, request.Password)
+	if err != nil {
+		if errors.Is(err, entity.ErrInvalidCredentials) {
+			errorResponse(ctx, http.StatusUnauthorized, "invalid credentials")
+			return
+		}
+		a.l.Error(err, "http - v1 - doLogin")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// @Summary     Complete admin login
+// @Description Exchange challenge token and TOTP or recovery code for access token
+// @ID          admin-login-2fa
+// @Tags        auth
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.TwoFactorLoginRequest true "Challenge token and code"
+// @Success     200 {object} entity.AuthResponse
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     429 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/login/2fa [post]
+func (a *authRoutes) doLoginTwoFactor(ctx *gin.Context) {
+	var request entity.TwoFactorLoginRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		a.l.Error(err, "http - v1 - doLoginTwoFactor")
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	resp, err := a.u.CompleteLogin(ctx.Request.Context(), request.ChallengeToken, request.Code)
+	if err != nil {
+		a.twoFactorErrorResponse(ctx, err, "http - v1 - doLoginTwoFactor")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// @Summary     Enroll TOTP
+// @Description Generate a new TOTP secret and provisioning URI for QR code. Enrollment must be confirmed with a code
+// @ID          enroll-totp
+// @Tags        admin
+// @Produce     json
+// @Success     200 {object} entity.TOTPEnrollResponse
+// @Failure     401 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/2fa/enroll [post]
+// @Security    BearerAuth
+func (a *authRoutes) doEnrollTOTP(ctx *gin.Context) {
+	resp, err := a.u.EnrollTOTP(ctx.Request.Context())
+	if err != nil {
+		a.twoFactorErrorResponse(ctx, err, "http - v1 - doEnrollTOTP")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// @Summary     Confirm TOTP
+// @Description Enable two-factor authentication with the first code and get recovery codes
+// @ID          confirm-totp
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.TwoFactorCodeRequest true "TOTP code"
+// @Success     200 {object} entity.RecoveryCodesResponse
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/2fa/confirm [post]
+// @Security    BearerAuth
+func (a *authRoutes) doConfirmTOTP(ctx *gin.Context) {
+	var request entity.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		a.l.Error(err, "http - v1 - doConfirmTOTP")
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	resp, err := a.u.ConfirmTOTP(ctx.Request.Context(), request.Code)
+	if err != nil {
+		a.twoFactorErrorResponse(ctx, err, "http - v1 - doConfirmTOTP")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// @Summary     Regenerate recovery codes
+// @Description Replace recovery codes. Requires a current TOTP code
+// @ID          regenerate-recovery-codes
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.TwoFactorCodeRequest true "TOTP code"
+// @Success     200 {object} entity.RecoveryCodesResponse
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/2fa/recovery-codes [post]
+// @Security    BearerAuth
+func (a *authRoutes) doRegenerateRecoveryCodes(ctx *gin.Context) {
+	var request entity.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		a.l.Error(err, "http - v1 - doRegenerateRecoveryCodes")
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	resp, err := a.u.RegenerateRecoveryCodes(ctx.Request.Context(), request.Code)
 	if err != nil {
-		errorResponse(ctx, http.StatusUnauthorized, "invalid credentials")
+		a.twoFactorErrorResponse(ctx, err, "http - v1 - doRegenerateRecoveryCodes")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// @Summary     Disable TOTP
+// @Description Disable two-factor authentication. Requires a TOTP or recovery code
+// @ID          disable-totp
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.TwoFactorCodeRequest true "TOTP or recovery code"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/2fa/disable [post]
+// @Security    BearerAuth
+func (a *authRoutes) doDisableTOTP(ctx *gin.Context) {
+	var request entity.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		a.l.Error(err, "http - v1 - doDisableTOTP")
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := a.u.DisableTOTP(ctx.Request.Context(), request.Code); err != nil {
+		a.twoFactorErrorResponse(ctx, err, "http - v1 - doDisableTOTP")
 		return
 	}
 
-	ctx.JSON(http.StatusOK, entity.AuthResponse{Token: token})
+	ctx.JSON(http.StatusOK, nil)
+}
+
+func (a *authRoutes) twoFactorErrorResponse(ctx *gin.Context, err error, op string) {
+	switch {
+	case errors.Is(err, entity.ErrInvalidChallenge):
+		errorResponse(ctx, http.StatusUnauthorized, "invalid or expired challenge token")
+	case errors.Is(err, entity.ErrInvalidTwoFactorCode):
+		errorResponse(ctx, http.StatusUnauthorized, "invalid code")
+	case errors.Is(err, entity.ErrTwoFactorNotEnrolled):
+		errorResponse(ctx, http.StatusConflict, "two-factor authentication is not enrolled")
+	case errors.Is(err, entity.ErrTwoFactorAlreadyEnabled):
+		errorResponse(ctx, http.StatusConflict, "two-factor authentication is already enabled")
+	default:
+		a.l.Error(err, op)
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+	}
 }
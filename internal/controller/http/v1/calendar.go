@@ -0,0 +1,107 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/ical"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	_calendarProdID      = "-//Beyond Limits//Exhibitions//RU"
+	_calendarName        = "Beyond Limits — выставки"
+	_calendarContentType = "text/calendar; charset=utf-8"
+	// _maxCalendarEvents matches the largest page the exhibitions usecase returns.
+	_maxCalendarEvents = 200
+)
+
+// @Summary     Exhibitions calendar
+// @Description iCalendar feed with all exhibitions, suitable for calendar subscriptions
+// @ID          get-events-calendar
+// @Tags        exhibitions
+// @Produce     text/calendar
+// @Success     200 {string} string
+// @Failure     500 {object} response
+// @Router      /events.ics [get]
+func (r *exhibitionsRoutes) doGetEventsCalendar(ctx *gin.Context) {
+	exhibitions, err := r.u.GetExhibitions(ctx.Request.Context(), entity.ExhibitionFilter{Limit: _maxCalendarEvents})
+	if err != nil {
+		r.l.Error(err, "http - v1 - doGetEventsCalendar")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	calendar := ical.Calendar{ProdID: _calendarProdID, Name: _calendarName}
+	now := time.Now()
+	for _, exhibition := range exhibitions {
+		calendar.Events = append(calendar.Events, r.exhibitionEvent(exhibition, now))
+	}
+
+	ctx.Data(http.StatusOK, _calendarContentType, calendar.Marshal())
+}
+
+// @Summary     Exhibition calendar event
+// @Description Download a single exhibition as an .ics file
+// @ID          get-exhibition-calendar
+// @Tags        exhibitions
+// @Produce     text/calendar
+// @Param       id path int true "Exhibition ID"
+// @Success     200 {string} string
+// @Failure     400 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /exhibitions/{id}/event.ics [get]
+func (r *exhibitionsRoutes) doGetExhibitionCalendar(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	exhibition, err := r.u.GetExhibitionByID(ctx.Request.Context(), id, entity.PriceQuery{})
+	if err != nil {
+		if errors.Is(err, entity.ErrExhibitionNotFound) {
+			errorResponse(ctx, http.StatusNotFound, "exhibition not found")
+			return
+		}
+		r.l.Error(err, "http - v1 - doGetExhibitionCalendar")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	calendar := ical.Calendar{
+		ProdID: _calendarProdID,
+		Events: []ical.Event{r.exhibitionEvent(*exhibition, time.Now())},
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="exhibition-%d.ics"`, exhibition.ID))
+	ctx.Data(http.StatusOK, _calendarContentType, calendar.Marshal())
+}
+
+func (r *exhibitionsRoutes) exhibitionEvent(exhibition entity.Exhibition, stamp time.Time) ical.Event {
+	return ical.Event{
+		UID:          fmt.Sprintf("exhibition-%d@%s", exhibition.ID, siteHost(r.baseURL)),
+		Summary:      exhibition.Title,
+		Description:  exhibition.Description,
+		Location:     exhibition.Venue,
+		URL:          fmt.Sprintf("%s/exhibitions/%d", r.baseURL, exhibition.ID),
+		Start:        exhibition.StartsAt,
+		End:          exhibition.EndsAt,
+		Stamp:        stamp,
+		LastModified: exhibition.UpdatedAt,
+	}
+}
+
+// siteHost returns the host part of the site base URL, used to make globally unique IDs.
+func siteHost(baseURL string) string {
+	if u, err := url.Parse(baseURL); err == nil && u.Hostname() != "" {
+		return u.Hostname()
+	}
+	return baseURL
+}
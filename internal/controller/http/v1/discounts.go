@@ -0,0 +1,123 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type discountsRoutes struct {
+	u usecase.Discounts
+	l logger.Interface
+}
+
+func newDiscountsRoutes(handler *gin.RouterGroup, l logger.Interface, d usecase.Discounts, authMiddleware gin.HandlerFunc) {
+	r := discountsRoutes{d, l}
+
+	adminHandler := handler.Group("/admin", authMiddleware, middleware.RequireScope(entity.ScopePicturesWrite))
+	{
+		adminHandler.GET("/discounts", r.doGetDiscounts)
+		adminHandler.POST("/discounts", r.doCreateDiscount)
+		adminHandler.DELETE("/discounts/:id", r.doDeleteDiscount)
+	}
+}
+
+// @Summary     Get discounts
+// @Description Get all discount rules and promo codes, latest first
+// @ID          get-discounts
+// @Tags        admin
+// @Produce     json
+// @Success     200 {array} entity.DiscountRule
+// @Failure     401 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/discounts [get]
+// @Security    BearerAuth
+func (r *discountsRoutes) doGetDiscounts(ctx *gin.Context) {
+	discounts, err := r.u.GetDiscounts(ctx.Request.Context())
+	if err != nil {
+		r.l.Error(err, "http - v1 - doGetDiscounts")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, discounts)
+}
+
+// @Summary     Create discount
+// @Description Create a discount rule, optionally available only with a promo code
+// @ID          create-discount
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.DiscountCreateRequest true "Discount rule"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/discounts [post]
+// @Security    BearerAuth
+func (r *discountsRoutes) doCreateDiscount(ctx *gin.Context) {
+	var req entity.DiscountCreateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if _, err := r.u.CreateDiscount(ctx.Request.Context(), req); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrUnknownDiscountKind),
+			errors.Is(err, entity.ErrDiscountValue),
+			errors.Is(err, entity.ErrDiscountPeriod),
+			errors.Is(err, entity.ErrUnknownCurrency):
+			errorResponse(ctx, http.StatusBadRequest, err.Error())
+		case errors.Is(err, entity.ErrPromoCodeExists):
+			errorResponse(ctx, http.StatusConflict, "promo code already exists")
+		default:
+			r.l.Error(err, "http - v1 - doCreateDiscount")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary     Delete discount
+// @Description Delete discount rule by ID
+// @ID          delete-discount
+// @Tags        admin
+// @Produce     json
+// @Param       id path int true "Discount ID"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/discounts/{id} [delete]
+// @Security    BearerAuth
+func (r *discountsRoutes) doDeleteDiscount(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	if err := r.u.DeleteDiscount(ctx.Request.Context(), id); err != nil {
+		if errors.Is(err, entity.ErrDiscountNotFound) {
+			errorResponse(ctx, http.StatusNotFound, "discount not found")
+			return
+		}
+		r.l.Error(err, "http - v1 - doDeleteDiscount")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
@@ -0,0 +1,313 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type exhibitionsRoutes struct {
+	u usecase.Exhibitions
+	l logger.Interface
+	// baseURL is used for absolute links in calendar events.
+	baseURL string
+}
+
+func newExhibitionsRoutes(
+	handler *gin.RouterGroup,
+	l logger.Interface,
+	e usecase.Exhibitions,
+	baseURL string,
+	authMiddleware gin.HandlerFunc,
+) {
+	r := exhibitionsRoutes{e, l, baseURL}
+
+	handler.GET("/exhibitions", r.doGetExhibitions)
+	handler.GET("/exhibitions/:id", r.doGetExhibitionByID)
+	handler.GET("/exhibitions/:id/event.ics", r.doGetExhibitionCalendar)
+	handler.GET("/events.ics", r.doGetEventsCalendar)
+
+	adminHandler := handler.Group("/admin", authMiddleware, middleware.RequireScope(entity.ScopePicturesWrite))
+	{
+		adminHandler.POST("/exhibitions", r.doCreateExhibition)
+		adminHandler.PATCH("/exhibitions/:id", r.doUpdateExhibition)
+		adminHandler.PUT("/exhibitions/:id/pictures", r.doSetExhibitionPictures)
+		adminHandler.POST("/exhibitions/:id/cover", r.doUploadExhibitionCover)
+		adminHandler.DELETE("/exhibitions/:id", r.doDeleteExhibition)
+	}
+}
+
+// @Summary     Get exhibitions
+// @Description Get exhibitions without their pictures
+// @ID          get-exhibitions
+// @Tags        exhibitions
+// @Produce     json
+// @Param       period query string false "current, upcoming or past; all when empty"
+// @Param       limit  query int    false "Limit (default 50, max 200)"
+// @Param       offset query int    false "Offset"
+// @Success     200 {array} entity.Exhibition
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /exhibitions [get]
+func (r *exhibitionsRoutes) doGetExhibitions(ctx *gin.Context) {
+	var filter entity.ExhibitionFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+		return
+	}
+
+	exhibitions, err := r.u.GetExhibitions(ctx.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, entity.ErrUnknownPeriod) {
+			errorResponse(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		r.l.Error(err, "http - v1 - doGetExhibitions")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, exhibitions)
+}
+
+// @Summary     Get exhibition by ID
+// @Description Get exhibition with its pictures in curator order
+// @ID          get-exhibition-by-id
+// @Tags        exhibitions
+// @Produce     json
+// @Param       id path int true "Exhibition ID"
+// @Param       currency query string false "Convert prices into currency, e.g. EUR"
+// @Param       promo query string false "Promo code"
+// @Success     200 {object} entity.Exhibition
+// @Failure     400 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /exhibitions/{id} [get]
+func (r *exhibitionsRoutes) doGetExhibitionByID(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	exhibition, err := r.u.GetExhibitionByID(ctx.Request.Context(), id, priceQuery(ctx))
+	if err != nil {
+		switch {
+		case errors.Is(err, entity.ErrUnknownCurrency):
+			errorResponse(ctx, http.StatusBadRequest, "unknown currency")
+		case errors.Is(err, entity.ErrExhibitionNotFound):
+			errorResponse(ctx, http.StatusNotFound, "exhibition not found")
+		default:
+			r.l.Error(err, "http - v1 - doGetExhibitionByID")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, exhibition)
+}
+
+// @Summary     Create exhibition
+// @Description Create exhibition, optionally with pictures in the given order
+// @ID          create-exhibition
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.ExhibitionCreateRequest true "Exhibition data"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/exhibitions [post]
+// @Security    BearerAuth
+func (r *exhibitionsRoutes) doCreateExhibition(ctx *gin.Context) {
+	var req entity.ExhibitionCreateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if _, err := r.u.CreateExhibition(ctx.Request.Context(), req); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrExhibitionPeriod):
+			errorResponse(ctx, http.StatusBadRequest, err.Error())
+		case errors.Is(err, entity.ErrPictureNotFound):
+			errorResponse(ctx, http.StatusBadRequest, "picture not found")
+		default:
+			r.l.Error(err, "http - v1 - doCreateExhibition")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary     Update exhibition
+// @Description Update exhibition fields
+// @ID          update-exhibition
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Exhibition ID"
+// @Param       request body entity.ExhibitionUpdateRequest true "Changed fields"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/exhibitions/{id} [patch]
+// @Security    BearerAuth
+func (r *exhibitionsRoutes) doUpdateExhibition(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	var req entity.ExhibitionUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := r.u.UpdateExhibition(ctx.Request.Context(), id, req); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrExhibitionPeriod):
+			errorResponse(ctx, http.StatusBadRequest, err.Error())
+		case errors.Is(err, entity.ErrExhibitionNotFound):
+			errorResponse(ctx, http.StatusNotFound, "exhibition not found")
+		default:
+			r.l.Error(err, "http - v1 - doUpdateExhibition")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary     Set exhibition pictures
+// @Description Replace exhibition pictures; they are shown in the given order
+// @ID          set-exhibition-pictures
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Exhibition ID"
+// @Param       request body entity.ExhibitionPicturesRequest true "Picture IDs in display order"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/exhibitions/{id}/pictures [put]
+// @Security    BearerAuth
+func (r *exhibitionsRoutes) doSetExhibitionPictures(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	var req entity.ExhibitionPicturesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := r.u.SetExhibitionPictures(ctx.Request.Context(), id, req); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrPictureNotFound):
+			errorResponse(ctx, http.StatusBadRequest, "picture not found")
+		case errors.Is(err, entity.ErrExhibitionNotFound):
+			errorResponse(ctx, http.StatusNotFound, "exhibition not found")
+		default:
+			r.l.Error(err, "http - v1 - doSetExhibitionPictures")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary     Upload exhibition cover
+// @Description Upload cover photo for exhibition, replacing the previous one
+// @ID          upload-exhibition-cover
+// @Tags        admin
+// @Accept      multipart/form-data
+// @Produce     json
+// @Param       id path int true "Exhibition ID"
+// @Param       file formData file true "Image file"
+// @Success     200 {object} entity.ExhibitionCoverResponse
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/exhibitions/{id}/cover [post]
+// @Security    BearerAuth
+func (r *exhibitionsRoutes) doUploadExhibitionCover(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "file is required")
+		return
+	}
+
+	url, err := r.u.UploadCover(ctx.Request.Context(), id, file)
+	if err != nil {
+		if errors.Is(err, entity.ErrExhibitionNotFound) {
+			errorResponse(ctx, http.StatusNotFound, "exhibition not found")
+			return
+		}
+		r.l.Error(err, "http - v1 - doUploadExhibitionCover")
+		errorResponse(ctx, http.StatusInternalServerError, "can't upload photo")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entity.ExhibitionCoverResponse{URL: url})
+}
+
+// @Summary     Delete exhibition
+// @Description Delete exhibition by ID; its pictures are not affected
+// @ID          delete-exhibition
+// @Tags        admin
+// @Produce     json
+// @Param       id path int true "Exhibition ID"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/exhibitions/{id} [delete]
+// @Security    BearerAuth
+func (r *exhibitionsRoutes) doDeleteExhibition(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	if err := r.u.DeleteExhibition(ctx.Request.Context(), id); err != nil {
+		if errors.Is(err, entity.ErrExhibitionNotFound) {
+			errorResponse(ctx, http.StatusNotFound, "exhibition not found")
+			return
+		}
+		r.l.Error(err, "http - v1 - doDeleteExhibition")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/feed"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const _feedSize = 20
+
+type feedRoutes struct {
+	n         usecase.News
+	l         logger.Interface
+	baseURL   string
+	siteTitle string
+}
+
+func newFeedRoutes(handler *gin.Engine, l logger.Interface, n usecase.News, baseURL, siteTitle string) {
+	r := feedRoutes{n, l, baseURL, siteTitle}
+
+	handler.GET("/news/rss.xml", r.doGetRSS)
+	handler.GET("/news/atom.xml", r.doGetAtom)
+}
+
+func (r *feedRoutes) doGetRSS(ctx *gin.Context) {
+	r.serveFeed(ctx, "application/rss+xml; charset=utf-8", "/news/rss.xml", feed.Feed.RSS)
+}
+
+func (r *feedRoutes) doGetAtom(ctx *gin.Context) {
+	r.serveFeed(ctx, "application/atom+xml; charset=utf-8", "/news/atom.xml", feed.Feed.Atom)
+}
+
+// serveFeed answers conditional requests with 304 Not Modified: the ETag is a hash
+// of the feed body and Last-Modified is the latest update among its items.
+func (r *feedRoutes) serveFeed(
+	ctx *gin.Context,
+	contentType, path string,
+	encode func(feed.Feed) ([]byte, error),
+) {
+	news, _, err := r.n.GetNewsPage(ctx.Request.Context(), entity.PageQuery{Limit: _feedSize})
+	if err != nil {
+		r.l.Error(err, "http - v1 - serveFeed")
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	newsFeed := r.newsFeed(news, path)
+	body, err := encode(newsFeed)
+	if err != nil {
+		r.l.Error(err, "http - v1 - serveFeed")
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+
+	var modified time.Time
+	if len(news) > 0 {
+		modified = newsFeed.Updated
+	}
+	http.ServeContent(ctx.Writer, ctx.Request, "", modified, bytes.NewReader(body))
+}
+
+func (r *feedRoutes) newsFeed(news []entity.News, path string) feed.Feed {
+	f := feed.Feed{
+		Title:       r.siteTitle + " — новости",
+		Description: "Новости галереи " + r.siteTitle,
+		Link:        r.baseURL + "/news",
+		SelfLink:    r.baseURL + path,
+		Author:      r.siteTitle,
+	}
+
+	for _, n := range news {
+		if n.UpdatedAt.After(f.Updated) {
+			f.Updated = n.UpdatedAt
+		}
+		f.Items = append(f.Items, feed.Item{
+			ID:        fmt.Sprintf("tag:%s,2025:news-%d", siteHost(r.baseURL), n.ID),
+			Title:     n.Title,
+			Link:      r.baseURL + "/news/" + n.Slug,
+			Content:   n.ContentHTML,
+			Published: *n.PublishAt,
+			Updated:   n.UpdatedAt,
+		})
+	}
+	if f.Updated.IsZero() {
+		f.Updated = time.Now()
+	}
+
+	return f
+}
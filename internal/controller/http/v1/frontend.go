@@ -2,18 +2,37 @@
 package v1
 
 import (
+	"errors"
+	"html/template"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
 
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
 	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase"
 	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/ratelimit"
 	"github.com/gin-contrib/multitemplate"
 	"github.com/gin-gonic/gin"
 )
 
 type frontendRoutes struct {
-	picturesUC usecase.Pictures
-	refUC      usecase.References
-	l          logger.Interface
+	picturesUC     usecase.Pictures
+	refUC          usecase.References
+	inquiriesUC    usecase.Inquiries
+	inquiryLimiter *ratelimit.Limiter
+	wishlistsUC    usecase.Wishlists
+	visitors       visitorCookie
+	customersUC    usecase.Customers
+	ordersUC       usecase.Orders
+	accountLimiter *ratelimit.Limiter
+	exhibitionsUC  usecase.Exhibitions
+	newsUC         usecase.News
+	searchUC       usecase.Search
+	l              logger.Interface
 }
 
 func NewFrontendRouter(
@@ -21,11 +40,31 @@ func NewFrontendRouter(
 	logger logger.Interface,
 	picturesUC usecase.Pictures,
 	referencesUC usecase.References,
+	inquiriesUC usecase.Inquiries,
+	inquiryLimiter *ratelimit.Limiter,
+	wishlistsUC usecase.Wishlists,
+	customersUC usecase.Customers,
+	ordersUC usecase.Orders,
+	accountLimiter *ratelimit.Limiter,
+	exhibitionsUC usecase.Exhibitions,
+	newsUC usecase.News,
+	searchUC usecase.Search,
+	secureCookie bool,
 ) {
 	r := &frontendRoutes{
-		picturesUC: picturesUC,
-		refUC:      referencesUC,
-		l:          logger,
+		picturesUC:     picturesUC,
+		refUC:          referencesUC,
+		inquiriesUC:    inquiriesUC,
+		inquiryLimiter: inquiryLimiter,
+		wishlistsUC:    wishlistsUC,
+		visitors:       visitorCookie{uc: wishlistsUC, secure: secureCookie},
+		customersUC:    customersUC,
+		ordersUC:       ordersUC,
+		accountLimiter: accountLimiter,
+		exhibitionsUC:  exhibitionsUC,
+		newsUC:         newsUC,
+		searchUC:       searchUC,
+		l:              logger,
 	}
 
 	handler.HTMLRender = r.createRenderer()
@@ -33,9 +72,43 @@ func NewFrontendRouter(
 	handler.Static("/static", "./web/static")
 	handler.Static("/uploads", "./uploads")
 
-	handler.GET("/", r.homePage)
-	handler.GET("/pictures", r.galleryPage)
-	handler.GET("/pictures/:id", r.picturePage)
+	pages := handler.Group("", customerMiddleware(logger, customersUC))
+	{
+		pages.GET("/", r.homePage)
+		pages.GET("/pictures", r.galleryPage)
+		pages.GET("/pictures/:id", r.picturePage)
+		pages.POST("/pictures/:id/inquiry", r.doSendInquiry)
+		pages.GET("/exhibitions", r.exhibitionsPage)
+		pages.GET("/exhibitions/:id", r.exhibitionPage)
+		pages.GET("/news", r.newsPage)
+		pages.GET("/news/:id", r.newsItemPage)
+		pages.GET("/search", r.searchPage)
+		pages.GET("/search/suggest", r.searchSuggestions)
+		pages.GET("/wishlist", r.wishlistPage)
+		pages.POST("/wishlist/:id", r.doAddToWishlist)
+		pages.POST("/wishlist/:id/remove", r.doRemoveFromWishlist)
+
+		pages.GET("/account", r.accountPage)
+		pages.GET("/account/login", r.accountLoginPage)
+		pages.POST("/account/login", r.doAccountLogin)
+		pages.POST("/account/logout", r.doAccountLogout)
+		pages.GET("/account/register", r.accountRegisterPage)
+		pages.POST("/account/register", r.doAccountRegister)
+		pages.GET("/account/verify", r.accountVerifyPage)
+		pages.POST("/account/verify/resend", r.doAccountResendVerification)
+		pages.GET("/account/forgot-password", r.accountForgotPasswordPage)
+		pages.POST("/account/forgot-password", r.doAccountForgotPassword)
+		pages.GET("/account/reset-password", r.accountResetPasswordPage)
+		pages.POST("/account/reset-password", r.doAccountResetPassword)
+	}
+}
+
+// _newsFuncs lets templates output ContentHTML of news and search snippets,
+// which the usecases have already sanitized or escaped.
+var _newsFuncs = template.FuncMap{
+	"sanitizedHTML": func(s string) template.HTML {
+		return template.HTML(s)
+	},
 }
 
 func (r *frontendRoutes) createRenderer() multitemplate.Renderer {
@@ -47,12 +120,62 @@ func (r *frontendRoutes) createRenderer() multitemplate.Renderer {
 
 	renderer.AddFromFiles("gallery",
 		"web/templates/base.html",
+		"web/templates/status.html",
+		"web/templates/price.html",
 		"web/templates/gallery.html")
 
+	// the next page of the gallery is rendered on its own for infinite scroll
+	renderer.Add("gallery-cards", template.Must(template.ParseFiles(
+		"web/templates/status.html",
+		"web/templates/price.html",
+		"web/templates/gallery.html")).Lookup("gallery-cards"))
+
 	renderer.AddFromFiles("picture",
 		"web/templates/base.html",
+		"web/templates/status.html",
+		"web/templates/inquiry_form.html",
 		"web/templates/picture.html")
 
+	renderer.AddFromFiles("exhibitions",
+		"web/templates/base.html",
+		"web/templates/exhibitions.html")
+
+	renderer.AddFromFiles("exhibition",
+		"web/templates/base.html",
+		"web/templates/status.html",
+		"web/templates/price.html",
+		"web/templates/exhibition.html")
+
+	renderer.AddFromFilesFuncs("news", _newsFuncs,
+		"web/templates/base.html",
+		"web/templates/news.html")
+
+	renderer.AddFromFilesFuncs("news-item", _newsFuncs,
+		"web/templates/base.html",
+		"web/templates/status.html",
+		"web/templates/price.html",
+		"web/templates/news_item.html")
+
+	renderer.AddFromFilesFuncs("search", _newsFuncs,
+		"web/templates/base.html",
+		"web/templates/search.html")
+
+	renderer.AddFromFiles("wishlist",
+		"web/templates/base.html",
+		"web/templates/status.html",
+		"web/templates/price.html",
+		"web/templates/wishlist.html")
+
+	renderer.Add("search-suggestions", template.Must(
+		template.ParseFiles("web/templates/search_suggestions.html")).Lookup("search-suggestions"))
+
+	// the form is also rendered on its own as the htmx response
+	renderer.Add("inquiry-form", template.Must(
+		template.ParseFiles("web/templates/inquiry_form.html")).Lookup("inquiry-form"))
+
+	addAccountTemplates(renderer)
+	addAdminPanelTemplates(renderer)
+
 	return renderer
 }
 
@@ -62,36 +185,455 @@ func (r *frontendRoutes) homePage(c *gin.Context) {
 	})
 }
 
+// _galleryFacets are the filters of the gallery page in display order.
+var _galleryFacets = []struct {
+	facet entity.PictureFacet
+	title string
+}{
+	{entity.PictureFacetGenre, "Жанр"},
+	{entity.PictureFacetAuthor, "Автор"},
+	{entity.PictureFacetTechnique, "Техника"},
+	{entity.PictureFacetDimension, "Размер"},
+	{entity.PictureFacetPrice, "Цена"},
+}
+
+type facetGroup struct {
+	Title string
+	Chips []facetChip
+}
+
+// facetChip is a facet value with the gallery URL that chooses it, or drops it if already chosen.
+type facetChip struct {
+	entity.FacetValue
+	URL string
+}
+
+// _gallerySorts are the orders offered in the gallery, the first one is the default.
+var _gallerySorts = []struct {
+	sort  entity.PictureSort
+	title string
+}{
+	{entity.PictureSortDateDesc, "Сначала новые"},
+	{entity.PictureSortDateAsc, "Сначала старые"},
+	{entity.PictureSortPriceAsc, "Дешевле"},
+	{entity.PictureSortPriceDesc, "Дороже"},
+}
+
+type sortLink struct {
+	Title    string
+	URL      string
+	Selected bool
+}
+
+// galleryPage takes the filter parameters of GET /api/pictures; a filter that
+// doesn't parse or validate is dropped by redirecting to the whole gallery.
+// Pictures come in pages: htmx requests with a cursor get just the cards of the
+// next page, which replace the loader at the end of the grid.
 func (r *frontendRoutes) galleryPage(c *gin.Context) {
-	pictures, err := r.picturesUC.GetPictures(c.Request.Context())
+	filter, err := pictureFilter(c)
+	if err != nil {
+		c.Redirect(http.StatusFound, "/pictures")
+		return
+	}
+	if filter.Sort == "" {
+		filter.Sort = _gallerySorts[0].sort
+	}
+
+	cursor := c.Query("cursor")
+	pictures, next, err := r.picturesUC.GetPicturesPage(c.Request.Context(), filter, entity.PageQuery{Cursor: cursor})
 	if err != nil {
-		r.l.Error(err, "http - v1 - homePage - get pictures")
+		if invalidPictureFilter(err) {
+			c.Redirect(http.StatusFound, "/pictures")
+			return
+		}
+		r.l.Error(err, "http - v1 - galleryPage - get pictures")
+	}
+
+	query := c.Request.URL.Query()
+	query.Del("cursor")
+
+	moreURL := ""
+	if next != "" {
+		more := maps.Clone(query)
+		more.Set("cursor", next)
+		moreURL = "/pictures?" + more.Encode()
+	}
+
+	if cursor != "" && c.GetHeader("HX-Request") == "true" {
+		c.HTML(http.StatusOK, "gallery-cards", gin.H{
+			"Pictures": pictures,
+			"MoreURL":  moreURL,
+		})
+		return
+	}
+
+	sorts := make([]sortLink, 0, len(_gallerySorts))
+	for i, s := range _gallerySorts {
+		q := maps.Clone(query)
+		q.Del("sort")
+		if i > 0 {
+			q.Set("sort", string(s.sort))
+		}
+		link := sortLink{Title: s.title, URL: "/pictures", Selected: s.sort == filter.Sort}
+		if len(q) > 0 {
+			link.URL += "?" + q.Encode()
+		}
+		sorts = append(sorts, link)
+	}
+
+	facets := make([]entity.PictureFacet, 0, len(_galleryFacets))
+	for _, f := range _galleryFacets {
+		facets = append(facets, f.facet)
+	}
+
+	var groups []facetGroup
+	counts, err := r.picturesUC.GetPictureFacets(c.Request.Context(), filter, facets)
+	if err != nil {
+		r.l.Error(err, "http - v1 - galleryPage - get facets")
+		counts = &entity.PictureFacetCounts{}
+	}
+
+	for _, f := range _galleryFacets {
+		values := counts.Values[f.facet]
+		if len(values) == 0 {
+			continue
+		}
+
+		group := facetGroup{Title: f.title}
+		for _, value := range values {
+			group.Chips = append(group.Chips, facetChip{FacetValue: value, URL: facetURL(query, f.facet, value)})
+		}
+		groups = append(groups, group)
 	}
 
 	c.HTML(200, "gallery", gin.H{
 		"Title":    "Галерея",
 		"Pictures": pictures,
+		"Facets":   groups,
+		"Currency": counts.Currency,
+		"Filtered": len(query) > 1 || (len(query) == 1 && !query.Has("sort")),
+		"Sorts":    sorts,
+		"MoreURL":  moreURL,
 	})
 }
 
+func invalidPictureFilter(err error) bool {
+	return errors.Is(err, entity.ErrUnknownPictureStatus) ||
+		errors.Is(err, entity.ErrInvalidPriceRange) ||
+		errors.Is(err, entity.ErrUnknownCurrency) ||
+		errors.Is(err, entity.ErrUnknownPictureSort) ||
+		errors.Is(err, entity.ErrInvalidCursor)
+}
+
+// facetURL toggles the value in the gallery query: genres, authors, techniques and
+// dimensions are added to or removed from their lists, a price range replaces the chosen one.
+func facetURL(query url.Values, facet entity.PictureFacet, value entity.FacetValue) string {
+	q := make(url.Values, len(query))
+	for key, values := range query {
+		q[key] = slices.Clone(values)
+	}
+
+	if facet == entity.PictureFacetPrice {
+		q.Del("minprice")
+		q.Del("maxprice")
+		if !value.Selected {
+			if value.Price.Min > 0 {
+				q.Set("minprice", strconv.Itoa(value.Price.Min))
+			}
+			if value.Price.Max > 0 {
+				q.Set("maxprice", strconv.Itoa(value.Price.Max))
+			}
+		}
+	} else {
+		param := string(facet)
+		ids, _ := parseIDs(q.Get(param))
+		if value.Selected {
+			ids = slices.DeleteFunc(ids, func(id uint64) bool { return id == value.ID })
+		} else {
+			ids = append(ids, value.ID)
+		}
+
+		items := make([]string, 0, len(ids))
+		for _, id := range ids {
+			items = append(items, strconv.FormatUint(id, 10))
+		}
+		if len(items) == 0 {
+			q.Del(param)
+		} else {
+			q.Set(param, strings.Join(items, ","))
+		}
+	}
+
+	if len(q) == 0 {
+		return "/pictures"
+	}
+	return "/pictures?" + q.Encode()
+}
+
+// picturePage accepts an ID or a slug; anything but the current slug is
+// redirected to it, so every picture has one canonical URL.
 func (r *frontendRoutes) picturePage(c *gin.Context) {
+	ref := c.Param("id")
+	pictureID, slug := parseRef(ref)
+
+	var picture *entity.Picture
+	var err error
+	if slug == "" {
+		picture, err = r.picturesUC.GetPictureByID(c.Request.Context(), pictureID, entity.PriceQuery{})
+	} else {
+		picture, err = r.picturesUC.GetPictureBySlug(c.Request.Context(), slug, entity.PriceQuery{})
+	}
+	if err != nil {
+		if !errors.Is(err, entity.ErrPictureNotFound) {
+			r.l.Error(err, "http - v1 - picturePage - get picture")
+		}
+		c.AbortWithStatus(404)
+		return
+	}
+
+	if ref != picture.Slug {
+		redirectToSlug(c, picture.Slug)
+		return
+	}
+
+	news, err := r.newsUC.GetPictureNews(c.Request.Context(), picture.ID)
+	if err != nil {
+		r.l.Error(err, "http - v1 - picturePage - get news")
+	}
+
+	c.HTML(200, "picture", gin.H{
+		"Title":         picture.Title,
+		"Picture":       picture,
+		"News":          news,
+		"Inquiry":       inquiryFormData(picture.ID, c.Query("inquiry")),
+		"WishlistAdded": c.Query("wishlist") == "added",
+	})
+}
+
+func inquiryFormData(pictureID uint64, result string) gin.H {
+	data := gin.H{"PictureID": pictureID}
+
+	switch result {
+	case "sent":
+		data["Sent"] = true
+	case "error":
+		data["Error"] = "Не удалось отправить заявку, проверьте поля и попробуйте ещё раз"
+	}
+
+	return data
+}
+
+// doSendInquiry accepts the inquiry form from the picture page. htmx requests get
+// the re-rendered form back, plain form posts are redirected to the picture page.
+func (r *frontendRoutes) doSendInquiry(c *gin.Context) {
 	id := c.Param("id")
 	pictureID, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
-		r.l.Error(err, "http - v1 - picturePage - parse id")
-		c.AbortWithStatus(400)
+		c.AbortWithStatus(http.StatusBadRequest)
 		return
 	}
 
-	picture, err := r.picturesUC.GetPictureByID(c.Request.Context(), pictureID)
+	var req entity.InquiryCreateRequest
+	data := gin.H{"PictureID": pictureID}
+
+	switch {
+	case c.ShouldBind(&req) != nil:
+		data["Error"] = "Укажите имя и способ связи"
+	case !r.inquiryLimiter.Allow(c.ClientIP()):
+		data["Error"] = "Слишком много заявок, попробуйте позже"
+	default:
+		req.CustomerID = currentCustomerID(c)
+		_, err := r.inquiriesUC.CreateInquiry(c.Request.Context(), pictureID, c.ClientIP(), req)
+		switch {
+		case err == nil, errors.Is(err, entity.ErrInquirySpam):
+			data["Sent"] = true
+		case errors.Is(err, entity.ErrPictureNotFound):
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		default:
+			r.l.Error(err, "http - v1 - doSendInquiry")
+			data["Error"] = "Не удалось отправить заявку, попробуйте позже"
+		}
+	}
+	data["Form"] = req
+
+	if c.GetHeader("HX-Request") == "true" {
+		c.HTML(http.StatusOK, "inquiry-form", data)
+		return
+	}
+
+	if data["Sent"] == true {
+		c.Redirect(http.StatusSeeOther, "/pictures/"+id+"?inquiry=sent")
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/pictures/"+id+"?inquiry=error")
+}
+
+func (r *frontendRoutes) exhibitionsPage(c *gin.Context) {
+	data := gin.H{"Title": "Выставки"}
+
+	for key, period := range map[string]entity.ExhibitionPeriod{
+		"Current":  entity.ExhibitionPeriodCurrent,
+		"Upcoming": entity.ExhibitionPeriodUpcoming,
+		"Past":     entity.ExhibitionPeriodPast,
+	} {
+		exhibitions, err := r.exhibitionsUC.GetExhibitions(c.Request.Context(), entity.ExhibitionFilter{Period: period})
+		if err != nil {
+			r.l.Error(err, "http - v1 - exhibitionsPage")
+		}
+		data[key] = exhibitions
+	}
+
+	c.HTML(200, "exhibitions", data)
+}
+
+func (r *frontendRoutes) exhibitionPage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
-		r.l.Error(err, "http - v1 - picturePage - get picture")
-		c.AbortWithStatus(404)
+		c.AbortWithStatus(http.StatusBadRequest)
 		return
 	}
 
-	c.HTML(200, "picture", gin.H{
-		"Title":   picture.Title,
-		"Picture": picture,
+	exhibition, err := r.exhibitionsUC.GetExhibitionByID(c.Request.Context(), id, entity.PriceQuery{})
+	if err != nil {
+		if !errors.Is(err, entity.ErrExhibitionNotFound) {
+			r.l.Error(err, "http - v1 - exhibitionPage")
+		}
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	c.HTML(200, "exhibition", gin.H{
+		"Title":      exhibition.Title,
+		"Exhibition": exhibition,
+	})
+}
+
+func (r *frontendRoutes) newsPage(c *gin.Context) {
+	news, err := r.newsUC.GetNews(c.Request.Context())
+	if err != nil {
+		r.l.Error(err, "http - v1 - newsPage")
+	}
+
+	c.HTML(200, "news", gin.H{
+		"Title": "Новости",
+		"News":  news,
+	})
+}
+
+// newsItemPage redirects to the current slug like picturePage.
+func (r *frontendRoutes) newsItemPage(c *gin.Context) {
+	ref := c.Param("id")
+	id, slug := parseRef(ref)
+
+	var news *entity.News
+	var err error
+	if slug == "" {
+		news, err = r.newsUC.GetNewsByID(c.Request.Context(), id)
+	} else {
+		news, err = r.newsUC.GetNewsBySlug(c.Request.Context(), slug)
+	}
+	if err != nil {
+		if !errors.Is(err, entity.ErrNewsNotFound) {
+			r.l.Error(err, "http - v1 - newsItemPage")
+		}
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	if ref != news.Slug {
+		redirectToSlug(c, news.Slug)
+		return
+	}
+
+	c.HTML(200, "news-item", gin.H{
+		"Title": news.Title,
+		"News":  news,
 	})
 }
+
+func (r *frontendRoutes) searchPage(c *gin.Context) {
+	query := entity.SearchQuery{Q: c.Query("q")}
+	data := gin.H{
+		"Title": "Поиск",
+		"Query": query.Q,
+	}
+
+	hits, err := r.searchUC.Search(c.Request.Context(), query)
+	switch {
+	case err == nil:
+		data["Hits"] = hits
+	case errors.Is(err, entity.ErrSearchQueryEmpty):
+	default:
+		r.l.Error(err, "http - v1 - searchPage")
+		data["Error"] = "Поиск временно недоступен, попробуйте позже"
+	}
+
+	c.HTML(http.StatusOK, "search", data)
+}
+
+// searchSuggestions renders the suggestion list for the htmx search field of the gallery.
+// Without input or on errors the list is just empty, the field keeps working as a search form.
+func (r *frontendRoutes) searchSuggestions(c *gin.Context) {
+	suggestions, err := r.searchUC.Suggest(c.Request.Context(), entity.SearchQuery{Q: c.Query("q")})
+	if err != nil && !errors.Is(err, entity.ErrSearchQueryEmpty) {
+		r.l.Error(err, "http - v1 - searchSuggestions")
+	}
+
+	c.HTML(http.StatusOK, "search-suggestions", gin.H{
+		"Suggestions": suggestions,
+	})
+}
+
+func (r *frontendRoutes) wishlistPage(c *gin.Context) {
+	pictures, err := r.wishlistsUC.GetWishlist(c.Request.Context(), r.visitors.owner(c), entity.PriceQuery{})
+	if err != nil {
+		r.l.Error(err, "http - v1 - wishlistPage")
+	}
+
+	c.HTML(200, "wishlist", gin.H{
+		"Title":    "Избранное",
+		"Pictures": pictures,
+	})
+}
+
+func (r *frontendRoutes) doAddToWishlist(c *gin.Context) {
+	id := c.Param("id")
+	pictureID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	owner, err := r.visitors.ensureOwner(c)
+	if err == nil {
+		err = r.wishlistsUC.AddToWishlist(c.Request.Context(), owner, pictureID)
+	}
+	if err != nil {
+		if errors.Is(err, entity.ErrPictureNotFound) {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		r.l.Error(err, "http - v1 - doAddToWishlist")
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, "/pictures/"+id+"?wishlist=added")
+}
+
+func (r *frontendRoutes) doRemoveFromWishlist(c *gin.Context) {
+	pictureID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	if err := r.wishlistsUC.RemoveFromWishlist(c.Request.Context(), r.visitors.owner(c), pictureID); err != nil {
+		r.l.Error(err, "http - v1 - doRemoveFromWishlist")
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, "/wishlist")
+}
@@ -0,0 +1,208 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/middleware"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+type inquiriesRoutes struct {
+	u usecase.Inquiries
+	l logger.Interface
+}
+
+func newInquiriesRoutes(
+	handler *gin.RouterGroup,
+	l logger.Interface,
+	i usecase.Inquiries,
+	limiter *ratelimit.Limiter,
+	authMiddleware gin.HandlerFunc,
+) {
+	r := inquiriesRoutes{i, l}
+
+	// Public routes
+	handler.POST("/pictures/:id/inquiries", middleware.RateLimit(limiter), r.doCreateInquiry)
+
+	// Admin routes
+	adminHandler := handler.Group("/admin", authMiddleware, middleware.RequireScope(entity.ScopeInquiriesWrite))
+	{
+		adminHandler.GET("/inquiries", r.doGetInquiries)
+		adminHandler.PATCH("/inquiries/:id/assign", r.doAssignInquiry)
+		adminHandler.PATCH("/inquiries/:id/status", r.doChangeInquiryStatus)
+	}
+}
+
+// @Summary     Create inquiry
+// @Description Leave a purchase request for the picture
+// @ID          create-inquiry
+// @Tags        inquiries
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Picture ID"
+// @Param       request body entity.InquiryCreateRequest true "Contact data"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     404 {object} response
+// @Failure     429 {object} response
+// @Failure     500 {object} response
+// @Router      /pictures/{id}/inquiries [post]
+func (r *inquiriesRoutes) doCreateInquiry(ctx *gin.Context) {
+	pictureID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	var req entity.InquiryCreateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req.CustomerID = currentCustomerID(ctx)
+
+	if _, err := r.u.CreateInquiry(ctx.Request.Context(), pictureID, ctx.ClientIP(), req); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrInquirySpam):
+			// bots get the same answer as people so they don't learn about the honeypot
+			ctx.JSON(http.StatusOK, nil)
+		case errors.Is(err, entity.ErrPictureNotFound):
+			errorResponse(ctx, http.StatusNotFound, "picture not found")
+		default:
+			r.l.Error(err, "http - v1 - doCreateInquiry")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary     Get inquiries
+// @Description Get purchase requests, newest first
+// @ID          get-inquiries
+// @Tags        admin
+// @Produce     json
+// @Param       status      query string false "new, in_progress, closed or spam"
+// @Param       assigned_to query string false "Assignee"
+// @Param       picture_id  query int    false "Picture ID"
+// @Param       limit       query int    false "Limit (default 50, max 500)"
+// @Param       offset      query int    false "Offset"
+// @Success     200 {array} entity.Inquiry
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/inquiries [get]
+// @Security    BearerAuth
+func (r *inquiriesRoutes) doGetInquiries(ctx *gin.Context) {
+	var filter entity.InquiryFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+		return
+	}
+
+	inquiries, err := r.u.GetInquiries(ctx.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, entity.ErrUnknownInquiryStatus) {
+			errorResponse(ctx, http.StatusBadRequest, "unknown status")
+			return
+		}
+		r.l.Error(err, "http - v1 - doGetInquiries")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, inquiries)
+}
+
+// @Summary     Assign inquiry
+// @Description Assign the inquiry to a manager, empty value unassigns it
+// @ID          assign-inquiry
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Inquiry ID"
+// @Param       request body entity.InquiryAssignRequest true "Assignee"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/inquiries/{id}/assign [patch]
+// @Security    BearerAuth
+func (r *inquiriesRoutes) doAssignInquiry(ctx *gin.Context) {
+	inquiryID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	var req entity.InquiryAssignRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := r.u.AssignInquiry(ctx.Request.Context(), inquiryID, req); err != nil {
+		if errors.Is(err, entity.ErrInquiryNotFound) {
+			errorResponse(ctx, http.StatusNotFound, "inquiry not found")
+			return
+		}
+		r.l.Error(err, "http - v1 - doAssignInquiry")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary     Change inquiry status
+// @Description Change status of the inquiry
+// @ID          change-inquiry-status
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Inquiry ID"
+// @Param       request body entity.InquiryStatusRequest true "New status"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/inquiries/{id}/status [patch]
+// @Security    BearerAuth
+func (r *inquiriesRoutes) doChangeInquiryStatus(ctx *gin.Context) {
+	inquiryID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	var req entity.InquiryStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := r.u.ChangeInquiryStatus(ctx.Request.Context(), inquiryID, req); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrUnknownInquiryStatus):
+			errorResponse(ctx, http.StatusBadRequest, "unknown status")
+		case errors.Is(err, entity.ErrInquiryNotFound):
+			errorResponse(ctx, http.StatusNotFound, "inquiry not found")
+		default:
+			r.l.Error(err, "http - v1 - doChangeInquiryStatus")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
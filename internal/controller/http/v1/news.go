@@ -8,6 +8,7 @@ import (
 	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
 	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase"
 	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/middleware"
 	"github.com/gin-gonic/gin"
 )
 
@@ -22,61 +23,166 @@ func newNewsRoutes(handler *gin.RouterGroup, l logger.Interface, n usecase.News,
 	handler.GET("/news", r.doGetNews)
 	handler.GET("/news/:id", r.doGetNewsByID)
 
-	adminHandler := handler.Group("/admin", authMiddleware)
+	adminHandler := handler.Group("/admin", authMiddleware, middleware.RequireScope(entity.ScopeNewsWrite))
 	{
+		adminHandler.GET("/news", r.doGetAdminNews)
+		adminHandler.GET("/news/:id", r.doPreviewNews)
 		adminHandler.POST("/news", r.doCreateNews)
 		adminHandler.PATCH("/news/:id", r.doUpdateNews)
 		adminHandler.DELETE("/news/:id", r.doDeleteNews)
+		adminHandler.PUT("/news/:id/links", r.doSetNewsLinks)
+		adminHandler.POST("/news/:id/cover", r.doUploadNewsCover)
+		adminHandler.POST("/news/images", r.doUploadNewsImage)
 	}
 }
 
 // @Summary     Get news
-// @Description Get all news
+// @Description Get published news, latest first. With cursor or limit the response is a page
+// @Description with the cursor of the next one
 // @ID          get-news
 // @Tags        news
 // @Accept      json
 // @Produce     json
+// @Param       cursor query string false "Cursor of the page from next_cursor of the previous one"
+// @Param       limit query int false "News per page, 24 by default, at most 100"
 // @Success     200 {array} entity.News
+// @Success     200 {object} entity.NewsListResponse
+// @Failure     400 {object} response
 // @Failure     500 {object} response
 // @Router      /news [get]
 func (n *newsRoutes) doGetNews(ctx *gin.Context) {
-	news, err := n.u.GetNews(ctx.Request.Context())
+	var page entity.PageQuery
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+		return
+	}
+
+	if page.Cursor == "" && page.Limit == 0 {
+		news, err := n.u.GetNews(ctx.Request.Context())
+		if err != nil {
+			n.l.Error(err, "http - v1 - doGetNews")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+			return
+		}
+
+		ctx.JSON(http.StatusOK, news)
+		return
+	}
+
+	news, next, err := n.u.GetNewsPage(ctx.Request.Context(), page)
 	if err != nil {
+		if errors.Is(err, entity.ErrInvalidCursor) {
+			errorResponse(ctx, http.StatusBadRequest, "invalid cursor")
+			return
+		}
 		n.l.Error(err, "http - v1 - doGetNews")
 		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
 		return
 	}
 
-	ctx.JSON(http.StatusOK, news)
+	ctx.JSON(http.StatusOK, entity.NewsListResponse{News: news, NextCursor: next})
 }
 
-// @Summary     Get news by ID
-// @Description Get news by ID
+// @Summary     Get news by ID or slug
+// @Description Get news by ID or slug. A previous slug redirects to the current one
 // @ID          get-news-by-id
 // @Tags        news
 // @Accept      json
 // @Produce     json
-// @Param       id path int true "News ID"
+// @Param       id path string true "News ID or slug"
 // @Success     200 {object} entity.News
-// @Failure     400 {object} response
+// @Success     301
 // @Failure     404 {object} response
 // @Failure     500 {object} response
 // @Router      /news/{id} [get]
 func (n *newsRoutes) doGetNewsByID(ctx *gin.Context) {
-	id := ctx.Param("id")
-	newsID, err := strconv.ParseUint(id, 10, 64)
+	newsID, slug := parseRef(ctx.Param("id"))
+
+	var news *entity.News
+	var err error
+	if slug == "" {
+		news, err = n.u.GetNewsByID(ctx.Request.Context(), newsID)
+	} else {
+		news, err = n.u.GetNewsBySlug(ctx.Request.Context(), slug)
+	}
+	if err != nil {
+		if errors.Is(err, entity.ErrNewsNotFound) {
+			errorResponse(ctx, http.StatusNotFound, "news not found")
+			return
+		}
+		n.l.Error(err, "http - v1 - doGetNewsByID")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	if slug != "" && slug != news.Slug {
+		redirectToSlug(ctx, news.Slug)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, news)
+}
+
+// @Summary     Get news for admins
+// @Description Get news in any status, newest first
+// @ID          get-admin-news
+// @Tags        admin
+// @Produce     json
+// @Param       status query string false "draft, scheduled, published or archived"
+// @Success     200 {array} entity.News
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/news [get]
+// @Security    BearerAuth
+func (n *newsRoutes) doGetAdminNews(ctx *gin.Context) {
+	var filter entity.NewsFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+		return
+	}
+
+	news, err := n.u.GetAdminNews(ctx.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, entity.ErrUnknownNewsStatus) {
+			errorResponse(ctx, http.StatusBadRequest, "unknown news status")
+			return
+		}
+		n.l.Error(err, "http - v1 - doGetAdminNews")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, news)
+}
+
+// @Summary     Preview news
+// @Description Get news by ID in any status
+// @ID          preview-news
+// @Tags        admin
+// @Produce     json
+// @Param       id path int true "News ID"
+// @Success     200 {object} entity.News
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/news/{id} [get]
+// @Security    BearerAuth
+func (n *newsRoutes) doPreviewNews(ctx *gin.Context) {
+	newsID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
 		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
 		return
 	}
 
-	news, err := n.u.GetNewsByID(ctx.Request.Context(), newsID)
+	news, err := n.u.PreviewNews(ctx.Request.Context(), newsID)
 	if err != nil {
 		if errors.Is(err, entity.ErrNewsNotFound) {
 			errorResponse(ctx, http.StatusNotFound, "news not found")
 			return
 		}
-		n.l.Error(err, "http - v1 - doGetNewsByID")
+		n.l.Error(err, "http - v1 - doPreviewNews")
 		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
 		return
 	}
@@ -105,9 +211,20 @@ func (n *newsRoutes) doCreateNews(ctx *gin.Context) {
 		return
 	}
 
-	if err := n.u.CreateNews(ctx.Request.Context(), req); err != nil {
-		n.l.Error(err, "http - v1 - doCreateNews")
-		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+	if _, err := n.u.CreateNews(ctx.Request.Context(), req); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrUnknownNewsStatus):
+			errorResponse(ctx, http.StatusBadRequest, "unknown news status")
+		case errors.Is(err, entity.ErrNewsPublishAtRequired):
+			errorResponse(ctx, http.StatusBadRequest, "publish_at is required to schedule news")
+		case errors.Is(err, entity.ErrPictureNotFound):
+			errorResponse(ctx, http.StatusBadRequest, "picture not found")
+		case errors.Is(err, entity.ErrAuthorNotFound):
+			errorResponse(ctx, http.StatusBadRequest, "author not found")
+		default:
+			n.l.Error(err, "http - v1 - doCreateNews")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		}
 		return
 	}
 
@@ -144,8 +261,17 @@ func (n *newsRoutes) doUpdateNews(ctx *gin.Context) {
 	}
 
 	if err := n.u.UpdateNews(ctx.Request.Context(), newsID, req); err != nil {
-		n.l.Error(err, "http - v1 - doUpdateNews")
-		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		switch {
+		case errors.Is(err, entity.ErrNewsNotFound):
+			errorResponse(ctx, http.StatusNotFound, "news not found")
+		case errors.Is(err, entity.ErrUnknownNewsStatus):
+			errorResponse(ctx, http.StatusBadRequest, "unknown news status")
+		case errors.Is(err, entity.ErrNewsPublishAtRequired):
+			errorResponse(ctx, http.StatusBadRequest, "publish_at is required to schedule news")
+		default:
+			n.l.Error(err, "http - v1 - doUpdateNews")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		}
 		return
 	}
 
@@ -162,6 +288,7 @@ func (n *newsRoutes) doUpdateNews(ctx *gin.Context) {
 // @Success     200
 // @Failure     400 {object} response
 // @Failure     401 {object} response
+// @Failure     404 {object} response
 // @Failure     500 {object} response
 // @Router      /admin/news/{id} [delete]
 // @Security    BearerAuth
@@ -174,6 +301,10 @@ func (n *newsRoutes) doDeleteNews(ctx *gin.Context) {
 	}
 
 	if err := n.u.DeleteNews(ctx.Request.Context(), newsID); err != nil {
+		if errors.Is(err, entity.ErrNewsNotFound) {
+			errorResponse(ctx, http.StatusNotFound, "news not found")
+			return
+		}
 		n.l.Error(err, "http - v1 - doDeleteNews")
 		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
 		return
@@ -181,3 +312,122 @@ func (n *newsRoutes) doDeleteNews(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, nil)
 }
+
+// @Summary     Set news links
+// @Description Replace pictures and authors the news is about; pictures keep the given order
+// @ID          set-news-links
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "News ID"
+// @Param       request body entity.NewsLinksRequest true "Linked pictures and authors"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/news/{id}/links [put]
+// @Security    BearerAuth
+func (n *newsRoutes) doSetNewsLinks(ctx *gin.Context) {
+	newsID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	var req entity.NewsLinksRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		n.l.Error(err, "http - v1 - doSetNewsLinks")
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := n.u.SetNewsLinks(ctx.Request.Context(), newsID, req); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrNewsNotFound):
+			errorResponse(ctx, http.StatusNotFound, "news not found")
+		case errors.Is(err, entity.ErrPictureNotFound):
+			errorResponse(ctx, http.StatusBadRequest, "picture not found")
+		case errors.Is(err, entity.ErrAuthorNotFound):
+			errorResponse(ctx, http.StatusBadRequest, "author not found")
+		default:
+			n.l.Error(err, "http - v1 - doSetNewsLinks")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary     Upload news cover
+// @Description Upload cover photo for news, replacing the previous one
+// @ID          upload-news-cover
+// @Tags        admin
+// @Accept      multipart/form-data
+// @Produce     json
+// @Param       id path int true "News ID"
+// @Param       file formData file true "Image file"
+// @Success     200 {object} entity.NewsCoverResponse
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/news/{id}/cover [post]
+// @Security    BearerAuth
+func (n *newsRoutes) doUploadNewsCover(ctx *gin.Context) {
+	newsID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "file is required")
+		return
+	}
+
+	url, err := n.u.UploadNewsCover(ctx.Request.Context(), newsID, file)
+	if err != nil {
+		if errors.Is(err, entity.ErrNewsNotFound) {
+			errorResponse(ctx, http.StatusNotFound, "news not found")
+			return
+		}
+		n.l.Error(err, "http - v1 - doUploadNewsCover")
+		errorResponse(ctx, http.StatusInternalServerError, "can't upload photo")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, entity.NewsCoverResponse{URL: url})
+}
+
+// @Summary     Upload news image
+// @Description Upload an image to embed in news content
+// @ID          upload-news-image
+// @Tags        admin
+// @Accept      multipart/form-data
+// @Produce     json
+// @Param       file formData file true "Image file"
+// @Success     200 {object} entity.NewsImageResponse
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/news/images [post]
+// @Security    BearerAuth
+func (n *newsRoutes) doUploadNewsImage(ctx *gin.Context) {
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "file is required")
+		return
+	}
+
+	image, err := n.u.UploadNewsImage(ctx.Request.Context(), file)
+	if err != nil {
+		n.l.Error(err, "http - v1 - doUploadNewsImage")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, image)
+}
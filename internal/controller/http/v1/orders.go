@@ -0,0 +1,273 @@
+package v1
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/middleware"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	_paymentSignatureHeader = "X-Payment-Signature"
+	_maxWebhookBodySize     = 64 << 10
+)
+
+type ordersRoutes struct {
+	u usecase.Orders
+	l logger.Interface
+}
+
+func newOrdersRoutes(
+	handler *gin.RouterGroup,
+	l logger.Interface,
+	o usecase.Orders,
+	payments usecase.PaymentProvider,
+	simulatorEnabled bool,
+	limiter *ratelimit.Limiter,
+	authMiddleware gin.HandlerFunc,
+) {
+	r := ordersRoutes{o, l}
+
+	// Public routes, only with a payment provider configured
+	if payments != nil {
+		handler.POST("/pictures/:id/orders", middleware.RateLimit(limiter), r.doCreateOrder)
+		handler.POST("/payments/webhook", r.doPaymentWebhook)
+
+		// the fake gateway has no payment page, so its confirmation URL finishes the payment right away;
+		// anyone could mark orders paid through it, hence the explicit flag
+		if simulator, ok := payments.(usecase.PaymentSimulator); ok && simulatorEnabled {
+			handler.GET("/payments/fake/:payment_id", r.doSimulatePayment(simulator))
+		}
+	}
+
+	// Admin routes
+	adminHandler := handler.Group("/admin", authMiddleware, middleware.RequireScope(entity.ScopeOrdersWrite))
+	{
+		adminHandler.GET("/orders", r.doGetOrders)
+		adminHandler.GET("/orders/:id", r.doGetOrderByID)
+		adminHandler.POST("/orders/:id/cancel", r.doCancelOrder)
+	}
+}
+
+// @Summary     Create order
+// @Description Order the picture and get a payment link. The picture is reserved until the payment finishes
+// @ID          create-order
+// @Tags        orders
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Picture ID"
+// @Param       request body entity.OrderCreateRequest true "Customer data"
+// @Success     200 {object} entity.OrderCreateResponse
+// @Failure     400 {object} response
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     429 {object} response
+// @Failure     500 {object} response
+// @Router      /pictures/{id}/orders [post]
+func (r *ordersRoutes) doCreateOrder(ctx *gin.Context) {
+	pictureID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	var req entity.OrderCreateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	req.CustomerID = currentCustomerID(ctx)
+
+	resp, err := r.u.CreateOrder(ctx.Request.Context(), pictureID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, entity.ErrPictureNotFound):
+			errorResponse(ctx, http.StatusNotFound, "picture not found")
+		case errors.Is(err, entity.ErrPictureNotAvailable):
+			errorResponse(ctx, http.StatusConflict, "picture is not available")
+		case errors.Is(err, entity.ErrPromoCodeNotFound):
+			errorResponse(ctx, http.StatusBadRequest, "invalid promo code")
+		default:
+			r.l.Error(err, "http - v1 - doCreateOrder")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// @Summary     Payment webhook
+// @Description Payment provider notification, signed with HMAC-SHA256 of the body in X-Payment-Signature
+// @ID          payment-webhook
+// @Tags        orders
+// @Accept      json
+// @Produce     json
+// @Param       X-Payment-Signature header string true "Hex HMAC-SHA256 of the body"
+// @Param       request body entity.PaymentEvent true "Payment event"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /payments/webhook [post]
+func (r *ordersRoutes) doPaymentWebhook(ctx *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, _maxWebhookBodySize))
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	r.handlePayment(ctx, body, ctx.GetHeader(_paymentSignatureHeader))
+}
+
+func (r *ordersRoutes) doSimulatePayment(simulator usecase.PaymentSimulator) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		status := entity.PaymentEventStatus(ctx.DefaultQuery("status", string(entity.PaymentSucceeded)))
+
+		body, signature, err := simulator.SimulatePayment(ctx.Param("payment_id"), status)
+		if err != nil {
+			errorResponse(ctx, http.StatusNotFound, "payment not found")
+			return
+		}
+
+		r.handlePayment(ctx, body, signature)
+	}
+}
+
+func (r *ordersRoutes) handlePayment(ctx *gin.Context, body []byte, signature string) {
+	if err := r.u.HandlePaymentWebhook(ctx.Request.Context(), body, signature); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrInvalidSignature):
+			errorResponse(ctx, http.StatusUnauthorized, "invalid signature")
+		case errors.Is(err, entity.ErrUnknownPaymentStatus):
+			errorResponse(ctx, http.StatusBadRequest, "unknown payment status")
+		case errors.Is(err, entity.ErrOrderNotFound):
+			errorResponse(ctx, http.StatusNotFound, "order not found")
+		case errors.Is(err, entity.ErrOrderPaidLate):
+			// recorded and reported to staff, the provider must not retry
+			r.l.Error(err, "http - v1 - handlePayment")
+			ctx.JSON(http.StatusOK, nil)
+		default:
+			r.l.Error(err, "http - v1 - handlePayment")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary     Get orders
+// @Description Get orders, newest first
+// @ID          get-orders
+// @Tags        admin
+// @Produce     json
+// @Param       status     query string false "pending, paid, failed, cancelled or paid_late"
+// @Param       picture_id query int    false "Picture ID"
+// @Param       limit      query int    false "Limit (default 50, max 500)"
+// @Param       offset     query int    false "Offset"
+// @Success     200 {array} entity.Order
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/orders [get]
+// @Security    BearerAuth
+func (r *ordersRoutes) doGetOrders(ctx *gin.Context) {
+	var filter entity.OrderFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+		return
+	}
+
+	orders, err := r.u.GetOrders(ctx.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, entity.ErrUnknownOrderStatus) {
+			errorResponse(ctx, http.StatusBadRequest, "unknown status")
+			return
+		}
+		r.l.Error(err, "http - v1 - doGetOrders")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, orders)
+}
+
+// @Summary     Get order by ID
+// @Description Get order by ID
+// @ID          get-order-by-id
+// @Tags        admin
+// @Produce     json
+// @Param       id path int true "Order ID"
+// @Success     200 {object} entity.Order
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/orders/{id} [get]
+// @Security    BearerAuth
+func (r *ordersRoutes) doGetOrderByID(ctx *gin.Context) {
+	orderID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	order, err := r.u.GetOrderByID(ctx.Request.Context(), orderID)
+	if err != nil {
+		if errors.Is(err, entity.ErrOrderNotFound) {
+			errorResponse(ctx, http.StatusNotFound, "order not found")
+			return
+		}
+		r.l.Error(err, "http - v1 - doGetOrderByID")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, order)
+}
+
+// @Summary     Cancel order
+// @Description Cancel a pending order and put the picture back on sale
+// @ID          cancel-order
+// @Tags        admin
+// @Produce     json
+// @Param       id path int true "Order ID"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/orders/{id}/cancel [post]
+// @Security    BearerAuth
+func (r *ordersRoutes) doCancelOrder(ctx *gin.Context) {
+	orderID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	if err := r.u.CancelOrder(ctx.Request.Context(), orderID); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrOrderNotFound):
+			errorResponse(ctx, http.StatusNotFound, "order not found")
+		case errors.Is(err, entity.ErrOrderNotPending):
+			errorResponse(ctx, http.StatusConflict, "order is not pending")
+		default:
+			r.l.Error(err, "http - v1 - doCancelOrder")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
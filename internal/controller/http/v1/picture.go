@@ -4,31 +4,43 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
 	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase"
 	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/middleware"
 	"github.com/gin-gonic/gin"
 )
 
 type picturesRoutes struct {
-	u usecase.Pictures
-	l logger.Interface
+	u    usecase.Pictures
+	news usecase.News
+	l    logger.Interface
 }
 
-func newPicturesRoutes(handler *gin.RouterGroup, l logger.Interface, p usecase.Pictures, authMiddleware gin.HandlerFunc) {
-	r := picturesRoutes{p, l}
+func newPicturesRoutes(
+	handler *gin.RouterGroup,
+	l logger.Interface,
+	p usecase.Pictures,
+	n usecase.News,
+	authMiddleware gin.HandlerFunc,
+) {
+	r := picturesRoutes{p, n, l}
 
 	// Public routes
 	handler.GET("/pictures", r.doGetPictures)
 	handler.GET("/pictures/:id", r.doGetPictureByID)
 
 	// Admin routes
-	adminHandler := handler.Group("/admin", authMiddleware)
+	adminHandler := handler.Group("/admin", authMiddleware, middleware.RequireScope(entity.ScopePicturesWrite))
 	{
 		adminHandler.POST("/pictures", r.doCreatePicture)
 		adminHandler.PATCH("/pictures/:id", r.doUpdatePicture)
 		adminHandler.DELETE("/pictures/:id", r.doDeletePicture)
+		adminHandler.PATCH("/pictures/:id/status", r.doChangePictureStatus)
+		adminHandler.GET("/pictures/:id/status-history", r.doGetPictureStatusHistory)
+		adminHandler.GET("/pictures/:id/price-history", r.doGetPriceHistory)
 
 		// Фото
 		adminHandler.POST("/pictures/:id/photo", r.doUploadMainPhoto)
@@ -38,51 +50,205 @@ func newPicturesRoutes(handler *gin.RouterGroup, l logger.Interface, p usecase.P
 }
 
 // @Summary     Get pictures
-// @Description Get all pictures
+// @Description Get all pictures, optionally filtered. With facets the response is an object with the pictures
+// @Description and counts of pictures for each value of the facets in the same filter
 // @ID          get-pictures
 // @Tags        pictures
 // @Accept      json
 // @Produce     json
+// @Param       status query string false "Comma-separated statuses: available, reserved, sold, not_for_sale"
+// @Param       currency query string false "Convert prices into currency, e.g. EUR"
+// @Param       promo query string false "Promo code"
+// @Param       search query string false "Full-text query over title and description"
+// @Param       genre query string false "Comma-separated genre IDs"
+// @Param       author query string false "Comma-separated author IDs"
+// @Param       technique query string false "Comma-separated work technique IDs"
+// @Param       dimensions query string false "Comma-separated dimension IDs"
+// @Param       minprice query int false "Lowest price in currency, inclusive"
+// @Param       maxprice query int false "Highest price in currency, exclusive"
+// @Param       facets query string false "Comma-separated facets to count: genre, author, technique, dimensions, price"
+// @Param       sort query string false "Order: datedesc, dateasc, priceasc, pricedesc; by ID if omitted, datedesc for pages"
+// @Param       cursor query string false "Cursor of the page from next_cursor of the previous one"
+// @Param       limit query int false "Pictures per page, 24 by default, at most 100"
 // @Success     200 {array} entity.Picture
+// @Success     200 {object} entity.PictureListResponse
+// @Failure     400 {object} response
 // @Failure     500 {object} response
 // @Router      /pictures [get]
 func (p *picturesRoutes) doGetPictures(ctx *gin.Context) {
-	pictures, err := p.u.GetPictures(ctx.Request.Context())
+	filter, err := pictureFilter(ctx)
 	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+		return
+	}
+
+	var page entity.PageQuery
+	if err := ctx.ShouldBindQuery(&page); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+		return
+	}
+	paged := page.Cursor != "" || page.Limit != 0
+
+	var facets []entity.PictureFacet
+	for _, facet := range splitList(ctx.Query("facets")) {
+		facets = append(facets, entity.PictureFacet(facet))
+	}
+
+	var resp entity.PictureListResponse
+	if paged {
+		resp.Pictures, resp.NextCursor, err = p.u.GetPicturesPage(ctx.Request.Context(), filter, page)
+	} else {
+		resp.Pictures, err = p.u.GetPictures(ctx.Request.Context(), filter)
+	}
+	if err != nil {
+		p.pictureListError(ctx, err)
+		return
+	}
+
+	if !paged && len(facets) == 0 {
+		ctx.JSON(http.StatusOK, resp.Pictures)
+		return
+	}
+
+	if len(facets) > 0 {
+		resp.Facets, err = p.u.GetPictureFacets(ctx.Request.Context(), filter, facets)
+		if err != nil {
+			p.pictureListError(ctx, err)
+			return
+		}
+	}
+
+	if resp.Pictures == nil {
+		resp.Pictures = []entity.Picture{}
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (p *picturesRoutes) pictureListError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, entity.ErrUnknownPictureStatus):
+		errorResponse(ctx, http.StatusBadRequest, "unknown status")
+	case errors.Is(err, entity.ErrUnknownPictureFacet):
+		errorResponse(ctx, http.StatusBadRequest, "unknown facet")
+	case errors.Is(err, entity.ErrInvalidPriceRange):
+		errorResponse(ctx, http.StatusBadRequest, "invalid price range")
+	case errors.Is(err, entity.ErrUnknownPictureSort):
+		errorResponse(ctx, http.StatusBadRequest, "unknown sort")
+	case errors.Is(err, entity.ErrInvalidCursor):
+		errorResponse(ctx, http.StatusBadRequest, "invalid cursor")
+	case errors.Is(err, entity.ErrUnknownCurrency):
+		errorResponse(ctx, http.StatusBadRequest, "unknown currency")
+	default:
 		p.l.Error(err, "http - v1 - doGetPictures")
 		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
-		return
+	}
+}
+
+// pictureFilter reads the listing filter from the query; the gallery page takes the same parameters.
+func pictureFilter(ctx *gin.Context) (entity.PictureFilter, error) {
+	filter := entity.PictureFilter{
+		PriceQuery: priceQuery(ctx),
+		Search:     ctx.Query("search"),
+		Sort:       entity.PictureSort(ctx.Query("sort")),
+	}
+	for _, status := range splitList(ctx.Query("status")) {
+		filter.Statuses = append(filter.Statuses, entity.PictureStatus(status))
 	}
 
-	ctx.JSON(http.StatusOK, pictures)
+	var err error
+	if filter.GenreIDs, err = parseIDs(ctx.Query("genre")); err != nil {
+		return filter, err
+	}
+	if filter.AuthorIDs, err = parseIDs(ctx.Query("author")); err != nil {
+		return filter, err
+	}
+	if filter.TechniqueIDs, err = parseIDs(ctx.Query("technique")); err != nil {
+		return filter, err
+	}
+	if filter.DimensionIDs, err = parseIDs(ctx.Query("dimensions")); err != nil {
+		return filter, err
+	}
+
+	if value := ctx.Query("minprice"); value != "" {
+		if filter.Price.Min, err = strconv.Atoi(value); err != nil {
+			return filter, err
+		}
+	}
+	if value := ctx.Query("maxprice"); value != "" {
+		if filter.Price.Max, err = strconv.Atoi(value); err != nil {
+			return filter, err
+		}
+	}
+	return filter, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseIDs(value string) ([]uint64, error) {
+	var ids []uint64
+	for _, item := range splitList(value) {
+		id, err := strconv.ParseUint(item, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
 }
 
-// @Summary     Get picture by ID
-// @Description Get picture by ID
+// @Summary     Get picture by ID or slug
+// @Description Get picture by ID or slug with the published news mentioning it. A previous slug redirects to the current one
 // @ID          get-picture-by-id
 // @Tags        pictures
 // @Accept      json
 // @Produce     json
-// @Param       id path int true "Picture ID"
+// @Param       id path string true "Picture ID or slug"
+// @Param       currency query string false "Convert price into currency, e.g. EUR"
+// @Param       promo query string false "Promo code"
 // @Success     200 {object} entity.Picture
+// @Success     301
 // @Failure     400 {object} response
 // @Failure     404 {object} response
 // @Failure     500 {object} response
 // @Router      /pictures/{id} [get]
 func (p *picturesRoutes) doGetPictureByID(ctx *gin.Context) {
-	id := ctx.Param("id")
-	pictureID, err := strconv.ParseUint(id, 10, 64)
+	pictureID, slug := parseRef(ctx.Param("id"))
+
+	var picture *entity.Picture
+	var err error
+	if slug == "" {
+		picture, err = p.u.GetPictureByID(ctx.Request.Context(), pictureID, priceQuery(ctx))
+	} else {
+		picture, err = p.u.GetPictureBySlug(ctx.Request.Context(), slug, priceQuery(ctx))
+	}
 	if err != nil {
-		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		switch {
+		case errors.Is(err, entity.ErrUnknownCurrency):
+			errorResponse(ctx, http.StatusBadRequest, "unknown currency")
+		case errors.Is(err, entity.ErrPictureNotFound):
+			errorResponse(ctx, http.StatusNotFound, "picture not found")
+		default:
+			p.l.Error(err, "http - v1 - doGetPictureByID")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		}
+		return
+	}
+
+	if slug != "" && slug != picture.Slug {
+		redirectToSlug(ctx, picture.Slug)
 		return
 	}
 
-	picture, err := p.u.GetPictureByID(ctx.Request.Context(), pictureID)
+	picture.News, err = p.news.GetPictureNews(ctx.Request.Context(), picture.ID)
 	if err != nil {
-		if errors.Is(err, entity.ErrPictureNotFound) {
-			errorResponse(ctx, http.StatusNotFound, "picture not found")
-			return
-		}
 		p.l.Error(err, "http - v1 - doGetPictureByID")
 		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
 		return
@@ -91,6 +257,10 @@ func (p *picturesRoutes) doGetPictureByID(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, picture)
 }
 
+func priceQuery(ctx *gin.Context) entity.PriceQuery {
+	return entity.PriceQuery{Currency: ctx.Query("currency"), PromoCode: ctx.Query("promo")}
+}
+
 // @Summary     Create picture
 // @Description Create new picture (without photos)
 // @ID          create-picture
@@ -112,7 +282,11 @@ func (p *picturesRoutes) doCreatePicture(ctx *gin.Context) {
 		return
 	}
 
-	if err := p.u.CreatePicture(ctx.Request.Context(), req); err != nil {
+	if _, err := p.u.CreatePicture(ctx.Request.Context(), req); err != nil {
+		if errors.Is(err, entity.ErrUnknownCurrency) {
+			errorResponse(ctx, http.StatusBadRequest, "unknown currency")
+			return
+		}
 		p.l.Error(err, "http - v1 - doCreatePicture")
 		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
 		return
@@ -151,8 +325,15 @@ func (p *picturesRoutes) doUpdatePicture(ctx *gin.Context) {
 	}
 
 	if err := p.u.UpdatePicture(ctx.Request.Context(), pictureID, req); err != nil {
-		p.l.Error(err, "http - v1 - doUpdatePicture")
-		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		switch {
+		case errors.Is(err, entity.ErrUnknownCurrency):
+			errorResponse(ctx, http.StatusBadRequest, "unknown currency")
+		case errors.Is(err, entity.ErrPictureNotFound):
+			errorResponse(ctx, http.StatusNotFound, "picture not found")
+		default:
+			p.l.Error(err, "http - v1 - doUpdatePicture")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		}
 		return
 	}
 
@@ -189,6 +370,126 @@ func (p *picturesRoutes) doDeletePicture(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, nil)
 }
 
+// @Summary     Change picture status
+// @Description Move picture to another status. Sold pictures can be changed only with override
+// @ID          change-picture-status
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Picture ID"
+// @Param       request body entity.PictureStatusRequest true "New status"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/pictures/{id}/status [patch]
+// @Security    BearerAuth
+func (p *picturesRoutes) doChangePictureStatus(ctx *gin.Context) {
+	pictureID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	var req entity.PictureStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		p.l.Error(err, "http - v1 - doChangePictureStatus")
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := p.u.ChangePictureStatus(ctx.Request.Context(), pictureID, req); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrUnknownPictureStatus):
+			errorResponse(ctx, http.StatusBadRequest, "unknown status")
+		case errors.Is(err, entity.ErrPictureNotFound):
+			errorResponse(ctx, http.StatusNotFound, "picture not found")
+		case errors.Is(err, entity.ErrPictureStatusTransition):
+			errorResponse(ctx, http.StatusConflict, "status transition not allowed")
+		case errors.Is(err, entity.ErrPictureStatusConflict):
+			errorResponse(ctx, http.StatusConflict, "status changed concurrently, retry")
+		default:
+			p.l.Error(err, "http - v1 - doChangePictureStatus")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary     Get picture status history
+// @Description Get status transitions of the picture, newest first
+// @ID          get-picture-status-history
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Picture ID"
+// @Success     200 {array} entity.PictureStatusTransition
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/pictures/{id}/status-history [get]
+// @Security    BearerAuth
+func (p *picturesRoutes) doGetPictureStatusHistory(ctx *gin.Context) {
+	pictureID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	history, err := p.u.GetPictureStatusHistory(ctx.Request.Context(), pictureID)
+	if err != nil {
+		if errors.Is(err, entity.ErrPictureNotFound) {
+			errorResponse(ctx, http.StatusNotFound, "picture not found")
+			return
+		}
+		p.l.Error(err, "http - v1 - doGetPictureStatusHistory")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, history)
+}
+
+// @Summary     Get picture price history
+// @Description Get price changes of the picture, newest first
+// @ID          get-picture-price-history
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Picture ID"
+// @Success     200 {array} entity.PriceChange
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/pictures/{id}/price-history [get]
+// @Security    BearerAuth
+func (p *picturesRoutes) doGetPriceHistory(ctx *gin.Context) {
+	pictureID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	history, err := p.u.GetPriceHistory(ctx.Request.Context(), pictureID)
+	if err != nil {
+		if errors.Is(err, entity.ErrPictureNotFound) {
+			errorResponse(ctx, http.StatusNotFound, "picture not found")
+			return
+		}
+		p.l.Error(err, "http - v1 - doGetPriceHistory")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, history)
+}
+
 // @Summary     Upload main photo
 // @Description Upload main photo for picture
 // @ID          upload-main-photo
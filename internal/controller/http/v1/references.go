@@ -1,11 +1,14 @@
 package v1
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
 	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase"
 	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/middleware"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,10 +22,11 @@ func newReferencesRoutes(handler *gin.RouterGroup, l logger.Interface, r usecase
 
 	handler.GET("/genres", routes.doGetGenres)
 	handler.GET("/authors", routes.doGetAuthors)
+	handler.GET("/authors/:id", routes.doGetAuthor)
 	handler.GET("/dimensions", routes.doGetDimensions)
 	handler.GET("/work-techniques", routes.doGetWorkTechniques)
 
-	adminHandler := handler.Group("/admin", authMiddleware)
+	adminHandler := handler.Group("/admin", authMiddleware, middleware.RequireScope(entity.ScopeReferencesWrite))
 	{
 		adminHandler.POST("/genres", routes.doCreateGenre)
 		adminHandler.DELETE("/genres/:id", routes.doDeleteGenre)
@@ -83,7 +87,7 @@ func (r *referencesRoutes) doCreateGenre(ctx *gin.Context) {
 		return
 	}
 
-	if err := r.u.CreateGenre(ctx.Request.Context(), request.Name); err != nil {
+	if _, err := r.u.CreateGenre(ctx.Request.Context(), request.Name); err != nil {
 		r.l.Error(err, "http - v1 - doCreateGenre")
 		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
 		return
@@ -143,6 +147,46 @@ func (r *referencesRoutes) doGetAuthors(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, authors)
 }
 
+// @Summary     Get author by ID or slug
+// @Description Get author by ID or slug. A previous slug redirects to the current one
+// @ID          get-author
+// @Tags        references
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "Author ID or slug"
+// @Success     200 {object} entity.Author
+// @Success     301
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /authors/{id} [get]
+func (r *referencesRoutes) doGetAuthor(ctx *gin.Context) {
+	authorID, slug := parseRef(ctx.Param("id"))
+
+	var author *entity.Author
+	var err error
+	if slug == "" {
+		author, err = r.u.GetAuthorByID(ctx.Request.Context(), authorID)
+	} else {
+		author, err = r.u.GetAuthorBySlug(ctx.Request.Context(), slug)
+	}
+	if err != nil {
+		if errors.Is(err, entity.ErrAuthorNotFound) {
+			errorResponse(ctx, http.StatusNotFound, "author not found")
+			return
+		}
+		r.l.Error(err, "http - v1 - doGetAuthor")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	if slug != "" && slug != author.Slug {
+		redirectToSlug(ctx, author.Slug)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, author)
+}
+
 type doCreateAuthorRequest struct {
 	FullName string `json:"full_name" binding:"required"`
 }
@@ -168,7 +212,7 @@ func (r *referencesRoutes) doCreateAuthor(ctx *gin.Context) {
 		return
 	}
 
-	if err := r.u.CreateAuthor(ctx.Request.Context(), request.FullName); err != nil {
+	if _, err := r.u.CreateAuthor(ctx.Request.Context(), request.FullName); err != nil {
 		r.l.Error(err, "http - v1 - doCreateAuthor")
 		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
 		return
@@ -254,7 +298,7 @@ func (r *referencesRoutes) doCreateDimension(ctx *gin.Context) {
 		return
 	}
 
-	if err := r.u.CreateDimension(ctx.Request.Context(), request.Width, request.Height); err != nil {
+	if _, err := r.u.CreateDimension(ctx.Request.Context(), request.Width, request.Height); err != nil {
 		r.l.Error(err, "http - v1 - doCreateDimension")
 		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
 		return
@@ -339,7 +383,7 @@ func (r *referencesRoutes) doCreateWorkTechnique(ctx *gin.Context) {
 		return
 	}
 
-	if err := r.u.CreateWorkTechnique(ctx.Request.Context(), request.Name); err != nil {
+	if _, err := r.u.CreateWorkTechnique(ctx.Request.Context(), request.Name); err != nil {
 		r.l.Error(err, "http - v1 - doCreateWorkTechnique")
 		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
 		return
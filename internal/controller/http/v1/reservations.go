@@ -0,0 +1,109 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type reservationsRoutes struct {
+	u usecase.Reservations
+	l logger.Interface
+}
+
+func newReservationsRoutes(handler *gin.RouterGroup, l logger.Interface, r usecase.Reservations, authMiddleware gin.HandlerFunc) {
+	routes := reservationsRoutes{r, l}
+
+	adminHandler := handler.Group("/admin", authMiddleware, middleware.RequireScope(entity.ScopePicturesWrite))
+	{
+		adminHandler.GET("/pictures/:id/reservations", routes.doGetReservations)
+		adminHandler.POST("/pictures/:id/reservations", routes.doCreateReservation)
+	}
+}
+
+// @Summary     Get reservations
+// @Description Get holds of the picture, newest first
+// @ID          get-reservations
+// @Tags        admin
+// @Produce     json
+// @Param       id path int true "Picture ID"
+// @Success     200 {array} entity.Reservation
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/pictures/{id}/reservations [get]
+// @Security    BearerAuth
+func (r *reservationsRoutes) doGetReservations(ctx *gin.Context) {
+	pictureID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	reservations, err := r.u.GetReservations(ctx.Request.Context(), pictureID)
+	if err != nil {
+		if errors.Is(err, entity.ErrPictureNotFound) {
+			errorResponse(ctx, http.StatusNotFound, "picture not found")
+			return
+		}
+		r.l.Error(err, "http - v1 - doGetReservations")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reservations)
+}
+
+// @Summary     Create reservation
+// @Description Hold the picture for a customer until the given time and mark it reserved
+// @ID          create-reservation
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Picture ID"
+// @Param       request body entity.ReservationCreateRequest true "Hold data"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     404 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/pictures/{id}/reservations [post]
+// @Security    BearerAuth
+func (r *reservationsRoutes) doCreateReservation(ctx *gin.Context) {
+	pictureID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	var req entity.ReservationCreateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if _, err := r.u.CreateReservation(ctx.Request.Context(), pictureID, req); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrHoldUntilInPast):
+			errorResponse(ctx, http.StatusBadRequest, err.Error())
+		case errors.Is(err, entity.ErrPictureNotFound):
+			errorResponse(ctx, http.StatusNotFound, "picture not found")
+		case errors.Is(err, entity.ErrPictureNotAvailable):
+			errorResponse(ctx, http.StatusConflict, "picture is not available")
+		default:
+			r.l.Error(err, "http - v1 - doCreateReservation")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
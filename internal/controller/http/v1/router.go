@@ -5,37 +5,98 @@ import (
 	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase"
 	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
 	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/middleware"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/ratelimit"
 	"github.com/gin-gonic/gin"
 )
 
 func NewRouter(
 	handler *gin.Engine,
 	logger logger.Interface,
-	cfg config.Admin,
+	cfg *config.Config,
 	authUseCase usecase.Auth,
+	sessionsUseCase usecase.Sessions,
+	apiKeysUseCase usecase.APIKeys,
+	auditUseCase usecase.Audit,
 	referencesUseCase usecase.References,
 	picturesUseCase usecase.Pictures,
 	newsUseCase usecase.News,
+	inquiriesUseCase usecase.Inquiries,
+	reservationsUseCase usecase.Reservations,
+	ordersUseCase usecase.Orders,
+	paymentProvider usecase.PaymentProvider,
+	discountsUseCase usecase.Discounts,
+	wishlistsUseCase usecase.Wishlists,
+	customersUseCase usecase.Customers,
+	webhooksUseCase usecase.Webhooks,
+	exhibitionsUseCase usecase.Exhibitions,
+	searchUseCase usecase.Search,
 ) {
 	handler.Use(gin.Logger())
 	handler.Use(gin.Recovery())
 
-	apiRouter := handler.Group("/api")
+	// shared by the API and the picture page form so both count towards one limit
+	inquiryLimiter := ratelimit.New(cfg.Inquiry.RateLimit, cfg.Inquiry.RateWindow)
+	// same for the account API and pages
+	accountLimiter := ratelimit.New(cfg.Account.RateLimit, cfg.Account.RateWindow)
+	orderLimiter := ratelimit.New(cfg.Order.RateLimit, cfg.Order.RateWindow)
+	// admin login and 2FA in the API and the panel
+	loginLimiter := ratelimit.New(cfg.Admin.LoginRateLimit, cfg.Admin.LoginRateWindow)
+
+	apiRouter := handler.Group("/api",
+		auditMiddleware(auditUseCase, logger, "/api/admin/"),
+		customerMiddleware(logger, customersUseCase))
 	{
 		newCommonRoutes(apiRouter)
-		newAuthRoutes(apiRouter, logger, authUseCase)
+		authMiddleware := middleware.AuthMiddleware(logger, cfg.Admin.JWTSecret, apiKeysUseCase)
 
-		authMiddleware := middleware.AuthMiddleware(logger, cfg.JWTSecret)
+		newAuthRoutes(apiRouter, logger, authUseCase, loginLimiter, authMiddleware)
+		newAPIKeysRoutes(apiRouter, logger, apiKeysUseCase, authMiddleware)
+		newAuditRoutes(apiRouter, logger, auditUseCase, authMiddleware)
 
 		newReferencesRoutes(apiRouter, logger, referencesUseCase, authMiddleware)
-		newPicturesRoutes(apiRouter, logger, picturesUseCase, authMiddleware)
+		newPicturesRoutes(apiRouter, logger, picturesUseCase, newsUseCase, authMiddleware)
+		newDiscountsRoutes(apiRouter, logger, discountsUseCase, authMiddleware)
+		newWebhooksRoutes(apiRouter, logger, webhooksUseCase, authMiddleware)
+		newExhibitionsRoutes(apiRouter, logger, exhibitionsUseCase, cfg.Site.BaseURL, authMiddleware)
+		newWishlistRoutes(apiRouter, logger, wishlistsUseCase, cfg.Session.SecureCookie, authMiddleware)
 		newNewsRoutes(apiRouter, logger, newsUseCase, authMiddleware)
+		newSearchRoutes(apiRouter, logger, searchUseCase)
+		newReservationsRoutes(apiRouter, logger, reservationsUseCase, authMiddleware)
+		newOrdersRoutes(apiRouter, logger, ordersUseCase, paymentProvider, cfg.Payment.FakeEnabled, orderLimiter, authMiddleware)
+		newInquiriesRoutes(apiRouter, logger, inquiriesUseCase, inquiryLimiter, authMiddleware)
+		newAccountRoutes(apiRouter, logger, customersUseCase, ordersUseCase, inquiriesUseCase, wishlistsUseCase,
+			accountLimiter, cfg.Session.SecureCookie)
 	}
 
+	newFeedRoutes(handler, logger, newsUseCase, cfg.Site.BaseURL, cfg.Site.Title)
+
 	NewFrontendRouter(
 		handler,
 		logger,
 		picturesUseCase,
 		referencesUseCase,
+		inquiriesUseCase,
+		inquiryLimiter,
+		wishlistsUseCase,
+		customersUseCase,
+		ordersUseCase,
+		accountLimiter,
+		exhibitionsUseCase,
+		newsUseCase,
+		searchUseCase,
+		cfg.Session.SecureCookie,
+	)
+
+	NewAdminPanelRouter(
+		handler,
+		logger,
+		cfg.Session,
+		auditUseCase,
+		authUseCase,
+		loginLimiter,
+		sessionsUseCase,
+		picturesUseCase,
+		newsUseCase,
+		referencesUseCase,
 	)
 }
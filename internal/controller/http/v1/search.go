@@ -0,0 +1,87 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+type searchRoutes struct {
+	u usecase.Search
+	l logger.Interface
+}
+
+func newSearchRoutes(handler *gin.RouterGroup, l logger.Interface, s usecase.Search) {
+	r := searchRoutes{s, l}
+
+	handler.GET("/search", r.doSearch)
+	handler.GET("/search/suggest", r.doSuggest)
+}
+
+// @Summary     Search
+// @Description Full-text search over pictures, authors, genres, techniques and published news, best hits first
+// @ID          search
+// @Tags        search
+// @Produce     json
+// @Param       q query string true "Query: words, \"quoted phrases\", -excluded words, or"
+// @Param       limit query int false "Maximum number of hits, 20 by default, at most 50"
+// @Success     200 {array} entity.SearchHit
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /search [get]
+func (r *searchRoutes) doSearch(ctx *gin.Context) {
+	var query entity.SearchQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+		return
+	}
+
+	hits, err := r.u.Search(ctx.Request.Context(), query)
+	if err != nil {
+		if errors.Is(err, entity.ErrSearchQueryEmpty) {
+			errorResponse(ctx, http.StatusBadRequest, "search query is empty")
+			return
+		}
+		r.l.Error(err, "http - v1 - doSearch")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, hits)
+}
+
+// @Summary     Suggest
+// @Description Picture titles, author names and genres similar to the input, tolerating typos; for search-as-you-type
+// @ID          search-suggest
+// @Tags        search
+// @Produce     json
+// @Param       q query string true "What the visitor has typed so far"
+// @Param       limit query int false "Maximum number of suggestions, 8 by default, at most 20"
+// @Success     200 {array} entity.SearchSuggestion
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /search/suggest [get]
+func (r *searchRoutes) doSuggest(ctx *gin.Context) {
+	var query entity.SearchQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+		return
+	}
+
+	suggestions, err := r.u.Suggest(ctx.Request.Context(), query)
+	if err != nil {
+		if errors.Is(err, entity.ErrSearchQueryEmpty) {
+			errorResponse(ctx, http.StatusBadRequest, "search query is empty")
+			return
+		}
+		r.l.Error(err, "http - v1 - doSuggest")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, suggestions)
+}
@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseRef splits a path parameter that holds either a numeric ID or a slug.
+// Slugs are never purely numeric, so the two can't be confused.
+func parseRef(param string) (id uint64, slug string) {
+	if id, err := strconv.ParseUint(param, 10, 64); err == nil {
+		return id, ""
+	}
+	return 0, param
+}
+
+// redirectToSlug answers 301 to the same URL with the last path segment
+// replaced by the current slug, keeping the query string.
+func redirectToSlug(ctx *gin.Context, slug string) {
+	path := ctx.Request.URL.Path
+	location := path[:strings.LastIndexByte(path, '/')+1] + url.PathEscape(slug)
+	if query := ctx.Request.URL.RawQuery; query != "" {
+		location += "?" + query
+	}
+
+	ctx.Redirect(http.StatusMovedPermanently, location)
+}
@@ -0,0 +1,233 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type webhooksRoutes struct {
+	u usecase.Webhooks
+	l logger.Interface
+}
+
+func newWebhooksRoutes(handler *gin.RouterGroup, l logger.Interface, w usecase.Webhooks, authMiddleware gin.HandlerFunc) {
+	r := webhooksRoutes{w, l}
+
+	adminHandler := handler.Group("/admin", authMiddleware, middleware.RequireAdmin())
+	{
+		adminHandler.GET("/webhooks", r.doGetSubscriptions)
+		adminHandler.POST("/webhooks", r.doCreateSubscription)
+		adminHandler.PATCH("/webhooks/:id", r.doUpdateSubscription)
+		adminHandler.DELETE("/webhooks/:id", r.doDeleteSubscription)
+		adminHandler.GET("/webhooks/deliveries", r.doGetDeliveries)
+		adminHandler.POST("/webhooks/deliveries/:id/retry", r.doRetryDelivery)
+	}
+}
+
+// @Summary     Get webhook subscriptions
+// @Description Get catalog webhook subscriptions. Secrets are not returned
+// @ID          get-webhooks
+// @Tags        admin
+// @Produce     json
+// @Success     200 {array} entity.WebhookSubscription
+// @Failure     401 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/webhooks [get]
+// @Security    BearerAuth
+func (r *webhooksRoutes) doGetSubscriptions(ctx *gin.Context) {
+	subscriptions, err := r.u.GetSubscriptions(ctx.Request.Context())
+	if err != nil {
+		r.l.Error(err, "http - v1 - doGetSubscriptions")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subscriptions)
+}
+
+// @Summary     Create webhook subscription
+// @Description Subscribe a URL to catalog events: picture.created, picture.updated, picture.priced, picture.sold, picture.deleted
+// @ID          create-webhook
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       request body entity.WebhookSubscriptionCreateRequest true "Subscription"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/webhooks [post]
+// @Security    BearerAuth
+func (r *webhooksRoutes) doCreateSubscription(ctx *gin.Context) {
+	var req entity.WebhookSubscriptionCreateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if _, err := r.u.CreateSubscription(ctx.Request.Context(), req); err != nil {
+		if errors.Is(err, entity.ErrUnknownWebhookEvent) {
+			errorResponse(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		r.l.Error(err, "http - v1 - doCreateSubscription")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary     Update webhook subscription
+// @Description Change URL, secret or events of a subscription, or pause it with active=false
+// @ID          update-webhook
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Subscription ID"
+// @Param       request body entity.WebhookSubscriptionUpdateRequest true "Changed fields"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/webhooks/{id} [patch]
+// @Security    BearerAuth
+func (r *webhooksRoutes) doUpdateSubscription(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	var req entity.WebhookSubscriptionUpdateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := r.u.UpdateSubscription(ctx.Request.Context(), id, req); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrUnknownWebhookEvent):
+			errorResponse(ctx, http.StatusBadRequest, err.Error())
+		case errors.Is(err, entity.ErrWebhookNotFound):
+			errorResponse(ctx, http.StatusNotFound, "webhook subscription not found")
+		default:
+			r.l.Error(err, "http - v1 - doUpdateSubscription")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary     Delete webhook subscription
+// @Description Delete subscription by ID together with its delivery log
+// @ID          delete-webhook
+// @Tags        admin
+// @Produce     json
+// @Param       id path int true "Subscription ID"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/webhooks/{id} [delete]
+// @Security    BearerAuth
+func (r *webhooksRoutes) doDeleteSubscription(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	if err := r.u.DeleteSubscription(ctx.Request.Context(), id); err != nil {
+		if errors.Is(err, entity.ErrWebhookNotFound) {
+			errorResponse(ctx, http.StatusNotFound, "webhook subscription not found")
+			return
+		}
+		r.l.Error(err, "http - v1 - doDeleteSubscription")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary     Get webhook deliveries
+// @Description Get the delivery log, newest first
+// @ID          get-webhook-deliveries
+// @Tags        admin
+// @Produce     json
+// @Param       subscription_id query int    false "Subscription ID"
+// @Param       status          query string false "pending, delivered or failed"
+// @Param       event           query string false "Event, e.g. picture.sold"
+// @Param       limit           query int    false "Limit (default 50, max 500)"
+// @Param       offset          query int    false "Offset"
+// @Success     200 {array} entity.WebhookDelivery
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/webhooks/deliveries [get]
+// @Security    BearerAuth
+func (r *webhooksRoutes) doGetDeliveries(ctx *gin.Context) {
+	var filter entity.WebhookDeliveryFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+		return
+	}
+
+	deliveries, err := r.u.GetDeliveries(ctx.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, entity.ErrUnknownWebhookStatus) || errors.Is(err, entity.ErrUnknownWebhookEvent) {
+			errorResponse(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		r.l.Error(err, "http - v1 - doGetDeliveries")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+// @Summary     Retry webhook delivery
+// @Description Queue a failed delivery for one more attempt
+// @ID          retry-webhook-delivery
+// @Tags        admin
+// @Produce     json
+// @Param       id path int true "Delivery ID"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/webhooks/deliveries/{id}/retry [post]
+// @Security    BearerAuth
+func (r *webhooksRoutes) doRetryDelivery(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	if err := r.u.RetryDelivery(ctx.Request.Context(), id); err != nil {
+		if errors.Is(err, entity.ErrWebhookDeliveryNotFound) {
+			errorResponse(ctx, http.StatusNotFound, "failed webhook delivery not found")
+			return
+		}
+		r.l.Error(err, "http - v1 - doRetryDelivery")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
@@ -0,0 +1,201 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/usecase"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	_visitorCookie       = "bl_visitor"
+	_visitorCookieMaxAge = 365 * 24 * 60 * 60
+	_maxVisitorIDLength  = 64
+)
+
+// visitorCookie keeps the id of an anonymous visitor that keys their wishlist.
+// A signed-in customer owns the wishlist instead.
+type visitorCookie struct {
+	uc     usecase.Wishlists
+	secure bool
+}
+
+func (v visitorCookie) owner(c *gin.Context) entity.WishlistOwner {
+	if customer := currentCustomer(c); customer != nil {
+		return entity.WishlistOwner{CustomerID: customer.ID}
+	}
+
+	id, err := c.Cookie(_visitorCookie)
+	if err != nil || len(id) > _maxVisitorIDLength {
+		return entity.WishlistOwner{}
+	}
+	return entity.WishlistOwner{VisitorID: id}
+}
+
+// ensureOwner returns the visitor's wishlist owner, issuing the cookie on the first save.
+func (v visitorCookie) ensureOwner(c *gin.Context) (entity.WishlistOwner, error) {
+	if owner := v.owner(c); !owner.Empty() {
+		return owner, nil
+	}
+
+	id, err := v.uc.NewVisitorID()
+	if err != nil {
+		return entity.WishlistOwner{}, err
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(_visitorCookie, id, _visitorCookieMaxAge, "/", "", v.secure, true)
+	return entity.WishlistOwner{VisitorID: id}, nil
+}
+
+type wishlistRoutes struct {
+	u        usecase.Wishlists
+	visitors visitorCookie
+	l        logger.Interface
+}
+
+func newWishlistRoutes(
+	handler *gin.RouterGroup,
+	l logger.Interface,
+	w usecase.Wishlists,
+	secureCookie bool,
+	authMiddleware gin.HandlerFunc,
+) {
+	r := wishlistRoutes{u: w, visitors: visitorCookie{uc: w, secure: secureCookie}, l: l}
+
+	// Public routes
+	handler.GET("/wishlist", r.doGetWishlist)
+	handler.POST("/wishlist/:picture_id", r.doAddToWishlist)
+	handler.DELETE("/wishlist/:picture_id", r.doRemoveFromWishlist)
+
+	// Admin routes
+	adminHandler := handler.Group("/admin", authMiddleware, middleware.RequireScope(entity.ScopePicturesWrite))
+	{
+		adminHandler.GET("/reports/favourites", r.doGetFavourites)
+	}
+}
+
+// @Summary     Get wishlist
+// @Description Get pictures saved by the visitor identified by the wishlist cookie
+// @ID          get-wishlist
+// @Tags        wishlist
+// @Produce     json
+// @Param       currency query string false "Convert prices into currency, e.g. EUR"
+// @Param       promo query string false "Promo code"
+// @Success     200 {array} entity.Picture
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /wishlist [get]
+func (r *wishlistRoutes) doGetWishlist(ctx *gin.Context) {
+	pictures, err := r.u.GetWishlist(ctx.Request.Context(), r.visitors.owner(ctx), priceQuery(ctx))
+	if err != nil {
+		switch {
+		case errors.Is(err, entity.ErrUnknownCurrency):
+			errorResponse(ctx, http.StatusBadRequest, "unknown currency")
+		default:
+			r.l.Error(err, "http - v1 - doGetWishlist")
+			errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pictures)
+}
+
+// @Summary     Add to wishlist
+// @Description Save the picture to the visitor's wishlist, issuing the wishlist cookie if needed
+// @ID          add-to-wishlist
+// @Tags        wishlist
+// @Produce     json
+// @Param       picture_id path int true "Picture ID"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /wishlist/{picture_id} [post]
+func (r *wishlistRoutes) doAddToWishlist(ctx *gin.Context) {
+	pictureID, err := strconv.ParseUint(ctx.Param("picture_id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	owner, err := r.visitors.ensureOwner(ctx)
+	if err != nil {
+		r.l.Error(err, "http - v1 - doAddToWishlist")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	if err := r.u.AddToWishlist(ctx.Request.Context(), owner, pictureID); err != nil {
+		if errors.Is(err, entity.ErrPictureNotFound) {
+			errorResponse(ctx, http.StatusNotFound, "picture not found")
+			return
+		}
+		r.l.Error(err, "http - v1 - doAddToWishlist")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary     Remove from wishlist
+// @Description Remove the picture from the visitor's wishlist
+// @ID          remove-from-wishlist
+// @Tags        wishlist
+// @Produce     json
+// @Param       picture_id path int true "Picture ID"
+// @Success     200
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /wishlist/{picture_id} [delete]
+func (r *wishlistRoutes) doRemoveFromWishlist(ctx *gin.Context) {
+	pictureID, err := strconv.ParseUint(ctx.Param("picture_id"), 10, 64)
+	if err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid ID")
+		return
+	}
+
+	if err := r.u.RemoveFromWishlist(ctx.Request.Context(), r.visitors.owner(ctx), pictureID); err != nil {
+		r.l.Error(err, "http - v1 - doRemoveFromWishlist")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, nil)
+}
+
+// @Summary     Get favourites report
+// @Description Get the most wishlisted pictures
+// @ID          get-favourites-report
+// @Tags        admin
+// @Produce     json
+// @Param       limit query int false "Number of pictures, 20 by default"
+// @Success     200 {array} entity.FavouritePicture
+// @Failure     400 {object} response
+// @Failure     401 {object} response
+// @Failure     500 {object} response
+// @Router      /admin/reports/favourites [get]
+// @Security    BearerAuth
+func (r *wishlistRoutes) doGetFavourites(ctx *gin.Context) {
+	var filter entity.FavouritesFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		errorResponse(ctx, http.StatusBadRequest, "invalid query parameters")
+		return
+	}
+
+	favourites, err := r.u.GetFavourites(ctx.Request.Context(), filter)
+	if err != nil {
+		r.l.Error(err, "http - v1 - doGetFavourites")
+		errorResponse(ctx, http.StatusInternalServerError, "internal service problems")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, favourites)
+}
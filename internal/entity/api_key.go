@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	ScopePicturesWrite   = "pictures:write"
+	ScopeNewsWrite       = "news:write"
+	ScopeReferencesWrite = "references:write"
+	ScopeInquiriesWrite  = "inquiries:write"
+	ScopeOrdersWrite     = "orders:write"
+)
+
+var APIKeyScopes = []string{
+	ScopePicturesWrite,
+	ScopeNewsWrite,
+	ScopeReferencesWrite,
+	ScopeInquiriesWrite,
+	ScopeOrdersWrite,
+}
+
+type APIKey struct {
+	ID         uint64     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type APIKeyCreateRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type APIKeyCreateResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrInvalidAPIKey  = errors.New("invalid api key")
+	ErrUnknownScope   = errors.New("unknown api key scope")
+	ErrExpiryInPast   = errors.New("expiry must be in the future")
+)
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+type AuditRecord struct {
+	ID         uint64          `json:"id"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before" swaggertype:"object"`
+	After      json.RawMessage `json:"after" swaggertype:"object"`
+	IP         string          `json:"ip"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type AuditFilter struct {
+	Actor      string     `form:"actor"`
+	Action     string     `form:"action"`
+	EntityType string     `form:"entity_type"`
+	EntityID   string     `form:"entity_id"`
+	From       *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit      uint64     `form:"limit"`
+	Offset     uint64     `form:"offset"`
+}
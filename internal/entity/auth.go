@@ -1,10 +1,47 @@
 package entity
 
+import "errors"
+
 type AuthRequest struct {
 	Login    string `json:"login" binding:"required"`
 	Password string `json:"password" binding:"required"`
 }
 
 type AuthResponse struct {
-	Token string `json:"token"`
+	Token             string `json:"token,omitempty"`
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TOTP struct {
+	Login        string
+	Secret       string
+	Enabled      bool
+	LastUsedStep int64
+}
+
+type TOTPEnrollResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
 }
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+var (
+	ErrInvalidCredentials      = errors.New("invalid credentials")
+	ErrInvalidChallenge        = errors.New("invalid or expired challenge token")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+	ErrTwoFactorNotEnrolled    = errors.New("two-factor authentication is not enrolled")
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+)
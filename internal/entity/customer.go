@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+type Customer struct {
+	ID         uint64     `json:"id"`
+	Email      string     `json:"email"`
+	Name       string     `json:"name"`
+	VerifiedAt *time.Time `json:"verified_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CustomerTokenPurpose tells what a one-time token emailed to a customer is for.
+type CustomerTokenPurpose string
+
+const (
+	CustomerTokenVerifyEmail   CustomerTokenPurpose = "verify_email"
+	CustomerTokenResetPassword CustomerTokenPurpose = "reset_password"
+)
+
+type CustomerRegisterRequest struct {
+	Email    string `json:"email" form:"email" binding:"required,email,max=255"`
+	Name     string `json:"name" form:"name" binding:"required,max=255"`
+	Password string `json:"password" form:"password" binding:"required,min=8,max=72"`
+}
+
+type CustomerLoginRequest struct {
+	Email    string `json:"email" form:"email" binding:"required"`
+	Password string `json:"password" form:"password" binding:"required"`
+}
+
+type CustomerLoginResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Customer  Customer  `json:"customer"`
+}
+
+type CustomerTokenRequest struct {
+	Token string `json:"token" form:"token" binding:"required"`
+}
+
+type PasswordResetRequest struct {
+	Email string `json:"email" form:"email" binding:"required,email"`
+}
+
+type PasswordResetConfirmRequest struct {
+	Token    string `json:"token" form:"token" binding:"required"`
+	Password string `json:"password" form:"password" binding:"required,min=8,max=72"`
+}
+
+// Email is a message sent through the Mailer.
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+var (
+	ErrCustomerExists         = errors.New("customer with this email already exists")
+	ErrCustomerNotFound       = errors.New("customer not found")
+	ErrInvalidCustomerLogin   = errors.New("invalid email or password")
+	ErrEmailNotVerified       = errors.New("email is not verified")
+	ErrInvalidCustomerToken   = errors.New("invalid or expired token")
+	ErrCustomerSessionExpired = errors.New("customer session expired")
+)
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+type DiscountKind string
+
+const (
+	DiscountKindPercent DiscountKind = "percent"
+	DiscountKindFixed   DiscountKind = "fixed"
+)
+
+func (k DiscountKind) Valid() bool {
+	return k == DiscountKindPercent || k == DiscountKindFixed
+}
+
+// DiscountRule lowers prices of matching pictures between StartsAt and EndsAt.
+// Empty scope fields match any picture, set ones must all match. Value is a
+// percentage for percent rules and an amount in Currency for fixed ones.
+// A rule with a PromoCode applies only to buyers who entered the code.
+type DiscountRule struct {
+	ID              uint64       `json:"id"`
+	Name            string       `json:"name"`
+	Kind            DiscountKind `json:"kind"`
+	Value           int          `json:"value"`
+	Currency        string       `json:"currency"`
+	GenreID         *uint64      `json:"genre_id"`
+	AuthorID        *uint64      `json:"author_id"`
+	WorkTechniqueID *uint64      `json:"work_technique_id"`
+	PictureIDs      []uint64     `json:"picture_ids"`
+	PromoCode       *string      `json:"promo_code"`
+	StartsAt        time.Time    `json:"starts_at"`
+	EndsAt          time.Time    `json:"ends_at"`
+	CreatedAt       time.Time    `json:"created_at"`
+}
+
+type DiscountCreateRequest struct {
+	Name            string       `json:"name" binding:"required,max=255"`
+	Kind            DiscountKind `json:"kind" binding:"required"`
+	Value           int          `json:"value" binding:"required,min=1"`
+	Currency        string       `json:"currency"`
+	GenreID         *uint64      `json:"genre_id"`
+	AuthorID        *uint64      `json:"author_id"`
+	WorkTechniqueID *uint64      `json:"work_technique_id"`
+	PictureIDs      []uint64     `json:"picture_ids"`
+	PromoCode       string       `json:"promo_code" binding:"max=64"`
+	StartsAt        time.Time    `json:"starts_at" binding:"required"`
+	EndsAt          time.Time    `json:"ends_at" binding:"required"`
+}
+
+// AppliedDiscount is the rule that produced the effective price of a picture.
+type AppliedDiscount struct {
+	ID        uint64 `json:"id"`
+	Name      string `json:"name"`
+	PromoCode string `json:"promo_code,omitempty"`
+}
+
+var (
+	ErrDiscountNotFound    = errors.New("discount not found")
+	ErrUnknownDiscountKind = errors.New("unknown discount kind")
+	ErrDiscountValue       = errors.New("percent discount must not exceed 100")
+	ErrDiscountPeriod      = errors.New("discount must end after it starts")
+	ErrPromoCodeExists     = errors.New("promo code already exists")
+	ErrPromoCodeNotFound   = errors.New("promo code not found or expired")
+)
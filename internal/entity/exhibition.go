@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// Exhibition is a curated group of pictures. Pictures keep the order set by the curator.
+type Exhibition struct {
+	ID          uint64    `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Venue       string    `json:"venue"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+	CoverURL    string    `json:"cover_url"`
+	PictureIDs  []uint64  `json:"picture_ids"`
+	Pictures    []Picture `json:"pictures,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type ExhibitionCreateRequest struct {
+	Title       string    `json:"title" binding:"required,max=255"`
+	Description string    `json:"description"`
+	Venue       string    `json:"venue" binding:"max=255"`
+	StartsAt    time.Time `json:"starts_at" binding:"required"`
+	EndsAt      time.Time `json:"ends_at" binding:"required"`
+	PictureIDs  []uint64  `json:"picture_ids"`
+}
+
+type ExhibitionUpdateRequest struct {
+	Title       *string    `json:"title" binding:"omitempty,max=255"`
+	Description *string    `json:"description"`
+	Venue       *string    `json:"venue" binding:"omitempty,max=255"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+}
+
+// ExhibitionPicturesRequest replaces the pictures of an exhibition; the order of IDs is kept.
+type ExhibitionPicturesRequest struct {
+	PictureIDs []uint64 `json:"picture_ids" binding:"required"`
+}
+
+type ExhibitionCoverResponse struct {
+	URL string `json:"url"`
+}
+
+// ExhibitionPeriod selects exhibitions relative to now.
+type ExhibitionPeriod string
+
+const (
+	ExhibitionPeriodCurrent  ExhibitionPeriod = "current"
+	ExhibitionPeriodUpcoming ExhibitionPeriod = "upcoming"
+	ExhibitionPeriodPast     ExhibitionPeriod = "past"
+)
+
+func (p ExhibitionPeriod) Valid() bool {
+	return p == ExhibitionPeriodCurrent || p == ExhibitionPeriodUpcoming || p == ExhibitionPeriodPast
+}
+
+type ExhibitionFilter struct {
+	Period ExhibitionPeriod `form:"period"`
+	Limit  uint64           `form:"limit"`
+	Offset uint64           `form:"offset"`
+}
+
+var (
+	ErrExhibitionNotFound = errors.New("exhibition not found")
+	ErrExhibitionPeriod   = errors.New("exhibition must end after it starts")
+	ErrUnknownPeriod      = errors.New("unknown exhibition period")
+)
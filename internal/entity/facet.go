@@ -0,0 +1,56 @@
+package entity
+
+// PictureFacet is a property of pictures the listing can be narrowed by.
+type PictureFacet string
+
+const (
+	PictureFacetGenre     PictureFacet = "genre"
+	PictureFacetAuthor    PictureFacet = "author"
+	PictureFacetTechnique PictureFacet = "technique"
+	PictureFacetDimension PictureFacet = "dimensions"
+	PictureFacetPrice     PictureFacet = "price"
+)
+
+var PictureFacets = []PictureFacet{
+	PictureFacetGenre,
+	PictureFacetAuthor,
+	PictureFacetTechnique,
+	PictureFacetDimension,
+	PictureFacetPrice,
+}
+
+func (f PictureFacet) Valid() bool {
+	for _, facet := range PictureFacets {
+		if f == facet {
+			return true
+		}
+	}
+	return false
+}
+
+// FacetValue is a value of a facet with the number of pictures matching the filter
+// if the value were chosen. Counts of a facet ignore the facet's own filter, so
+// choosing another value of it widens the listing rather than emptying it.
+// Price values have Price set instead of ID and Name.
+type FacetValue struct {
+	ID       uint64      `json:"id,omitempty"`
+	Name     string      `json:"name,omitempty"`
+	Price    *PriceRange `json:"price,omitempty"`
+	Count    int         `json:"count"`
+	Selected bool        `json:"selected"`
+}
+
+// PictureFacetCounts holds values of the requested facets, most frequent first;
+// price ranges go in ascending order and are in Currency.
+type PictureFacetCounts struct {
+	Currency string                        `json:"currency"`
+	Values   map[PictureFacet][]FacetValue `json:"values"`
+}
+
+// PictureListResponse is the picture listing together with facet counts in the same filter,
+// if they were requested, and the cursor of the next page, if the listing is paginated and goes on.
+type PictureListResponse struct {
+	Pictures   []Picture           `json:"pictures"`
+	Facets     *PictureFacetCounts `json:"facets,omitempty"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+type InquiryStatus string
+
+const (
+	InquiryStatusNew        InquiryStatus = "new"
+	InquiryStatusInProgress InquiryStatus = "in_progress"
+	InquiryStatusClosed     InquiryStatus = "closed"
+	InquiryStatusSpam       InquiryStatus = "spam"
+)
+
+var InquiryStatuses = []InquiryStatus{
+	InquiryStatusNew,
+	InquiryStatusInProgress,
+	InquiryStatusClosed,
+	InquiryStatusSpam,
+}
+
+func (s InquiryStatus) Valid() bool {
+	for _, status := range InquiryStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+type Inquiry struct {
+	ID           uint64        `json:"id"`
+	PictureID    uint64        `json:"picture_id"`
+	PictureTitle string        `json:"picture_title"`
+	CustomerID   *uint64       `json:"customer_id"`
+	Name         string        `json:"name"`
+	Contact      string        `json:"contact"`
+	Message      string        `json:"message"`
+	Status       InquiryStatus `json:"status"`
+	AssignedTo   string        `json:"assigned_to"`
+	IP           string        `json:"ip"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+type InquiryCreateRequest struct {
+	Name    string `json:"name" form:"name" binding:"required,max=255"`
+	Contact string `json:"contact" form:"contact" binding:"required,max=255"`
+	Message string `json:"message" form:"message" binding:"max=4000"`
+	// Website is a honeypot: the field is hidden from people, so only bots fill it in.
+	Website string `json:"website" form:"website"`
+	// CustomerID is set from the session of a signed-in customer, not from the request body.
+	CustomerID *uint64 `json:"-" form:"-"`
+}
+
+type InquiryFilter struct {
+	Status     InquiryStatus `form:"status"`
+	AssignedTo string        `form:"assigned_to"`
+	PictureID  uint64        `form:"picture_id"`
+	CustomerID uint64        `form:"customer_id"`
+	Limit      uint64        `form:"limit"`
+	Offset     uint64        `form:"offset"`
+}
+
+type InquiryAssignRequest struct {
+	AssignedTo string `json:"assigned_to"`
+}
+
+type InquiryStatusRequest struct {
+	Status InquiryStatus `json:"status" binding:"required"`
+}
+
+var (
+	ErrInquiryNotFound      = errors.New("inquiry not found")
+	ErrUnknownInquiryStatus = errors.New("unknown inquiry status")
+	ErrInquirySpam          = errors.New("inquiry looks like spam")
+)
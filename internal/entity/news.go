@@ -5,23 +5,127 @@ import (
 	"time"
 )
 
+type NewsStatus string
+
+const (
+	NewsStatusDraft     NewsStatus = "draft"
+	NewsStatusScheduled NewsStatus = "scheduled"
+	NewsStatusPublished NewsStatus = "published"
+	NewsStatusArchived  NewsStatus = "archived"
+)
+
+var NewsStatuses = []NewsStatus{
+	NewsStatusDraft,
+	NewsStatusScheduled,
+	NewsStatusPublished,
+	NewsStatusArchived,
+}
+
+func (s NewsStatus) Valid() bool {
+	for _, status := range NewsStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// News content is stored as Markdown; ContentHTML is rendered from it on read
+// and sanitized, so it is safe to embed in pages as is. PublishAt is the
+// publication time: a scheduled item goes public once it passes.
+// PictureIDs and AuthorIDs link the works the news is about; Pictures and
+// Authors are filled in only for a single news item. Slug follows the title
+// like Picture.Slug.
 type News struct {
+	ID          uint64     `json:"id"`
+	Slug        string     `json:"slug"`
+	Title       string     `json:"title"`
+	Content     string     `json:"content"`
+	ContentHTML string     `json:"content_html"`
+	CoverURL    string     `json:"cover_url"`
+	Status      NewsStatus `json:"status"`
+	PublishAt   *time.Time `json:"publish_at"`
+	PictureIDs  []uint64   `json:"picture_ids"`
+	AuthorIDs   []uint64   `json:"author_ids"`
+	Pictures    []Picture  `json:"pictures,omitempty"`
+	Authors     []Author   `json:"authors,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// NewsMention is a short reference to news, listed on the pages of the pictures it mentions.
+type NewsMention struct {
+	ID        uint64     `json:"id"`
+	Slug      string     `json:"slug"`
+	Title     string     `json:"title"`
+	CoverURL  string     `json:"cover_url"`
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+// NewsKey is the position of news in the public listing, which goes by publication time, latest first.
+type NewsKey struct {
+	PublishAt time.Time `json:"publish_at"`
 	ID        uint64    `json:"id"`
-	Title     string    `json:"title"`
-	Content   string    `json:"content"`
-	CreatedAt time.Time `json:"created_at"`
 }
 
+// NewsListResponse is a page of the public news listing with the cursor of the next page, if it goes on.
+type NewsListResponse struct {
+	News       []News `json:"news"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Public reports whether visitors can see the news at the moment now.
+func (n News) Public(now time.Time) bool {
+	if n.Status != NewsStatusPublished && n.Status != NewsStatusScheduled {
+		return false
+	}
+	return n.PublishAt != nil && !n.PublishAt.After(now)
+}
+
+// NewsCreateRequest creates a draft unless Status says otherwise. Publishing
+// without PublishAt publishes right away; scheduling requires PublishAt.
 type NewsCreateRequest struct {
-	Title   string `json:"title" binding:"required"`
-	Content string `json:"content" binding:"required"`
+	Title      string     `json:"title" binding:"required"`
+	Content    string     `json:"content" binding:"required"`
+	Status     NewsStatus `json:"status"`
+	PublishAt  *time.Time `json:"publish_at"`
+	PictureIDs []uint64   `json:"picture_ids"`
+	AuthorIDs  []uint64   `json:"author_ids"`
 }
 
 type NewsUpdateRequest struct {
-	Title   *string `json:"title"`
-	Content *string `json:"content"`
+	Title     *string     `json:"title"`
+	Content   *string     `json:"content"`
+	Status    *NewsStatus `json:"status"`
+	PublishAt *time.Time  `json:"publish_at"`
+}
+
+// NewsLinksRequest replaces the pictures and authors linked to news; the order of pictures is kept.
+type NewsLinksRequest struct {
+	PictureIDs []uint64 `json:"picture_ids"`
+	AuthorIDs  []uint64 `json:"author_ids"`
+}
+
+type NewsCoverResponse struct {
+	URL string `json:"url"`
+}
+
+// NewsFilter is used by admins; the public only ever sees published news.
+// PictureID keeps news linked to the picture.
+type NewsFilter struct {
+	Status    NewsStatus `form:"status"`
+	PictureID uint64     `form:"picture_id"`
+}
+
+// NewsImageResponse is returned for an inline image; Markdown is ready to paste into content.
+type NewsImageResponse struct {
+	URL      string `json:"url"`
+	Markdown string `json:"markdown"`
 }
 
 var (
-	ErrNewsNotFound = errors.New("news not found")
+	ErrNewsNotFound          = errors.New("news not found")
+	ErrUnknownNewsStatus     = errors.New("unknown news status")
+	ErrNewsPublishAtRequired = errors.New("publish_at is required to schedule news")
+	ErrAuthorNotFound        = errors.New("author not found")
 )
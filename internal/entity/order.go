@@ -0,0 +1,111 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "pending"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusFailed    OrderStatus = "failed"
+	OrderStatusCancelled OrderStatus = "cancelled"
+	// OrderStatusPaidLate flags a payment that arrived after the order had been
+	// cancelled or failed: staff either hand the picture over or refund it.
+	OrderStatusPaidLate OrderStatus = "paid_late"
+)
+
+var OrderStatuses = []OrderStatus{
+	OrderStatusPending,
+	OrderStatusPaid,
+	OrderStatusFailed,
+	OrderStatusCancelled,
+	OrderStatusPaidLate,
+}
+
+func (s OrderStatus) Valid() bool {
+	for _, status := range OrderStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+type Order struct {
+	ID            uint64      `json:"id"`
+	PictureID     uint64      `json:"picture_id"`
+	PictureTitle  string      `json:"picture_title"`
+	CustomerID    *uint64     `json:"customer_id"`
+	CustomerName  string      `json:"customer_name"`
+	CustomerEmail string      `json:"customer_email"`
+	CustomerPhone string      `json:"customer_phone"`
+	Amount        int         `json:"amount"`
+	Currency      string      `json:"currency"`
+	PromoCode     string      `json:"promo_code"`
+	Status        OrderStatus `json:"status"`
+	PaymentID     string      `json:"payment_id"`
+	PaymentURL    string      `json:"payment_url"`
+	PaidAt        *time.Time  `json:"paid_at"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
+type OrderCreateRequest struct {
+	CustomerName  string `json:"customer_name" binding:"required,max=255"`
+	CustomerEmail string `json:"customer_email" binding:"required,email,max=255"`
+	CustomerPhone string `json:"customer_phone" binding:"max=64"`
+	PromoCode     string `json:"promo_code" binding:"max=64"`
+	// CustomerID is set from the session of a signed-in customer, not from the request body.
+	CustomerID *uint64 `json:"-"`
+}
+
+type OrderCreateResponse struct {
+	ID         uint64 `json:"id"`
+	PaymentURL string `json:"payment_url"`
+}
+
+type OrderFilter struct {
+	Status     OrderStatus `form:"status"`
+	PictureID  uint64      `form:"picture_id"`
+	CustomerID uint64      `form:"customer_id"`
+	Limit      uint64      `form:"limit"`
+	Offset     uint64      `form:"offset"`
+}
+
+// PaymentRequest is what a payment provider needs to start a payment.
+type PaymentRequest struct {
+	OrderID     uint64
+	Amount      int
+	Currency    string
+	Description string
+}
+
+type Payment struct {
+	ID              string
+	ConfirmationURL string
+}
+
+type PaymentEventStatus string
+
+const (
+	PaymentSucceeded PaymentEventStatus = "succeeded"
+	PaymentFailed    PaymentEventStatus = "failed"
+)
+
+// PaymentEvent is a verified payment notification from the provider.
+type PaymentEvent struct {
+	PaymentID string             `json:"payment_id"`
+	Status    PaymentEventStatus `json:"status"`
+}
+
+var (
+	ErrOrderNotFound        = errors.New("order not found")
+	ErrUnknownOrderStatus   = errors.New("unknown order status")
+	ErrOrderNotPending      = errors.New("order is not pending")
+	ErrOrderPaidLate        = errors.New("order paid after it was closed")
+	ErrInvalidSignature     = errors.New("invalid payment signature")
+	ErrUnknownPaymentStatus = errors.New("unknown payment status")
+)
@@ -0,0 +1,15 @@
+package entity
+
+import "errors"
+
+// PageQuery asks for up to Limit items following the position of Cursor, which
+// is returned with the previous page; an empty Cursor asks for the first page.
+// A cursor stays valid while items are added or removed, nothing is repeated or skipped.
+type PageQuery struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit"`
+}
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
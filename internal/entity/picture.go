@@ -5,17 +5,148 @@ import (
 	"time"
 )
 
+// Picture.Price is the base price, EffectivePrice is what the buyer pays after discounts.
+// News lists published news mentioning the picture and is filled in only for a single picture.
+// Slug is made from the title and changes with it; previous slugs keep working in URLs.
 type Picture struct {
-	ID            uint64        `json:"id"`
-	Title         string        `json:"title"`
-	Price         int           `json:"price"`
-	Author        Author        `json:"author"`
-	Dimensions    Dimension     `json:"dimensions"`
-	WorkTechnique WorkTechnique `json:"work_technique"`
-	Genre         Genre         `json:"genre"`
-	Photo         Photo         `json:"photo"`
-	Gallery       []Photo       `json:"gallery"`
-	CreatedAt     time.Time     `json:"created_at"`
+	ID             uint64           `json:"id"`
+	Slug           string           `json:"slug"`
+	Title          string           `json:"title"`
+	Description    string           `json:"description"`
+	Price          int              `json:"price"`
+	EffectivePrice int              `json:"effective_price"`
+	Discount       *AppliedDiscount `json:"discount,omitempty"`
+	Currency       string           `json:"currency"`
+	Author         Author           `json:"author"`
+	Dimensions     Dimension        `json:"dimensions"`
+	WorkTechnique  WorkTechnique    `json:"work_technique"`
+	Genre          Genre            `json:"genre"`
+	Photo          Photo            `json:"photo"`
+	Gallery        []Photo          `json:"gallery"`
+	Status         PictureStatus    `json:"status"`
+	StatusAt       time.Time        `json:"status_changed_at"`
+	CreatedAt      time.Time        `json:"created_at"`
+	News           []NewsMention    `json:"news,omitempty"`
+}
+
+type PictureStatus string
+
+const (
+	PictureStatusAvailable  PictureStatus = "available"
+	PictureStatusReserved   PictureStatus = "reserved"
+	PictureStatusSold       PictureStatus = "sold"
+	PictureStatusNotForSale PictureStatus = "not_for_sale"
+)
+
+var PictureStatuses = []PictureStatus{
+	PictureStatusAvailable,
+	PictureStatusReserved,
+	PictureStatusSold,
+	PictureStatusNotForSale,
+}
+
+func (s PictureStatus) Valid() bool {
+	for _, status := range PictureStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// PictureFilter narrows the picture listing. Empty fields don't filter.
+// Search is a full-text query over the title and description.
+// A picture matches a list of genres, authors, techniques or dimensions if it has any of them.
+// Price is in the currency of PriceQuery, the base one if it's empty.
+// Rates are set by the usecase: the price of one unit of each currency in the base
+// currency, so that pictures priced in different currencies can be compared.
+type PictureFilter struct {
+	IDs          []uint64
+	Statuses     []PictureStatus
+	Search       string
+	GenreIDs     []uint64
+	AuthorIDs    []uint64
+	TechniqueIDs []uint64
+	DimensionIDs []uint64
+	Price        PriceRange
+	Rates        map[string]float64
+	Sort         PictureSort
+	PriceQuery
+}
+
+// PictureSort orders the picture listing, pictures with equal keys go by ID.
+// Prices are compared in the base currency. Without a sort pictures go by ID.
+type PictureSort string
+
+const (
+	PictureSortDateDesc  PictureSort = "datedesc"
+	PictureSortDateAsc   PictureSort = "dateasc"
+	PictureSortPriceAsc  PictureSort = "priceasc"
+	PictureSortPriceDesc PictureSort = "pricedesc"
+)
+
+var PictureSorts = []PictureSort{
+	PictureSortDateDesc,
+	PictureSortDateAsc,
+	PictureSortPriceAsc,
+	PictureSortPriceDesc,
+}
+
+func (s PictureSort) Valid() bool {
+	for _, sort := range PictureSorts {
+		if s == sort {
+			return true
+		}
+	}
+	return false
+}
+
+// PictureKey is the position of a picture in a sorted listing. Price is in the
+// base currency; only the field of the active sort and ID are compared.
+type PictureKey struct {
+	CreatedAt time.Time `json:"created_at"`
+	Price     float64   `json:"price"`
+	ID        uint64    `json:"id"`
+}
+
+// PriceRange holds prices from Min up to but not including Max, zero Max means no upper bound.
+type PriceRange struct {
+	Min int `json:"min"`
+	Max int `json:"max,omitempty"`
+}
+
+func (r PriceRange) Empty() bool {
+	return r.Min == 0 && r.Max == 0
+}
+
+func (r PriceRange) Valid() bool {
+	return r.Min >= 0 && r.Max >= 0 && (r.Max == 0 || r.Max > r.Min)
+}
+
+// PriceQuery describes how prices should be presented to the caller.
+type PriceQuery struct {
+	// Currency converts prices into the given currency, empty keeps the stored one.
+	Currency string
+	// PromoCode additionally applies discounts available only with the code.
+	PromoCode string
+	// StrictPromo rejects an unknown or expired PromoCode with ErrPromoCodeNotFound.
+	// Otherwise such a code is ignored, so public reads can't be used to guess codes.
+	StrictPromo bool
+}
+
+type PictureStatusRequest struct {
+	Status PictureStatus `json:"status" binding:"required"`
+	// Override allows transitions that are normally forbidden, e.g. sold -> available.
+	Override bool `json:"override"`
+}
+
+type PictureStatusTransition struct {
+	ID         uint64        `json:"id"`
+	PictureID  uint64        `json:"picture_id"`
+	FromStatus PictureStatus `json:"from_status"`
+	ToStatus   PictureStatus `json:"to_status"`
+	Override   bool          `json:"override"`
+	ChangedAt  time.Time     `json:"changed_at"`
 }
 
 type Photo struct {
@@ -26,7 +157,9 @@ type Photo struct {
 
 type PictureCreateRequest struct {
 	Title           string `json:"title" binding:"required"`
+	Description     string `json:"description"`
 	Price           int    `json:"price" binding:"required"`
+	Currency        string `json:"currency"`
 	AuthorID        uint64 `json:"author_id" binding:"required"`
 	DimensionsID    uint64 `json:"dimensions_id" binding:"required"`
 	WorkTechniqueID uint64 `json:"work_technique_id" binding:"required"`
@@ -35,13 +168,25 @@ type PictureCreateRequest struct {
 
 type PictureUpdateRequest struct {
 	Title           *string `json:"title"`
+	Description     *string `json:"description"`
 	Price           *int    `json:"price"`
+	Currency        *string `json:"currency"`
 	AuthorID        *uint64 `json:"author_id"`
 	DimensionsID    *uint64 `json:"dimensions_id"`
 	WorkTechniqueID *uint64 `json:"work_technique_id"`
 	GenreID         *uint64 `json:"genre_id"`
 }
 
+type PriceChange struct {
+	ID          uint64    `json:"id"`
+	PictureID   uint64    `json:"picture_id"`
+	OldPrice    *int      `json:"old_price"`
+	OldCurrency *string   `json:"old_currency"`
+	NewPrice    int       `json:"new_price"`
+	NewCurrency string    `json:"new_currency"`
+	ChangedAt   time.Time `json:"changed_at"`
+}
+
 type PhotoUploadRequest struct {
 	PictureID uint64 `form:"picture_id" binding:"required"`
 	IsMain    bool   `form:"is_main"`
@@ -59,4 +204,13 @@ type PhotoDeleteResponse struct {
 var (
 	ErrPictureNotFound = errors.New("picture not found")
 	ErrPhotoNotFound   = errors.New("photo not found")
+
+	ErrUnknownCurrency = errors.New("unknown currency")
+
+	ErrUnknownPictureStatus    = errors.New("unknown picture status")
+	ErrUnknownPictureFacet     = errors.New("unknown picture facet")
+	ErrInvalidPriceRange       = errors.New("invalid price range")
+	ErrUnknownPictureSort      = errors.New("unknown picture sort")
+	ErrPictureStatusTransition = errors.New("picture status transition not allowed")
+	ErrPictureStatusConflict   = errors.New("picture status changed concurrently")
 )
@@ -7,6 +7,7 @@ type Genre struct {
 
 type Author struct {
 	ID       uint64 `json:"id"`
+	Slug     string `json:"slug"`
 	FullName string `json:"full_name"`
 }
 
@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+type Reservation struct {
+	ID         uint64     `json:"id"`
+	PictureID  uint64     `json:"picture_id"`
+	HoldUntil  time.Time  `json:"hold_until"`
+	Note       string     `json:"note"`
+	ReleasedAt *time.Time `json:"released_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type ReservationCreateRequest struct {
+	HoldUntil time.Time `json:"hold_until" binding:"required"`
+	Note      string    `json:"note"`
+}
+
+var (
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrPictureNotAvailable = errors.New("picture is not available")
+	ErrHoldUntilInPast     = errors.New("hold must end in the future")
+)
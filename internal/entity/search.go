@@ -0,0 +1,48 @@
+package entity
+
+import "errors"
+
+type SearchHitType string
+
+const (
+	SearchHitPicture   SearchHitType = "picture"
+	SearchHitAuthor    SearchHitType = "author"
+	SearchHitGenre     SearchHitType = "genre"
+	SearchHitTechnique SearchHitType = "technique"
+	SearchHitNews      SearchHitType = "news"
+)
+
+// SearchHit is one search result, hits of all types are ranked together.
+// Snippet is HTML: the text is escaped and matched words are wrapped in <mark>.
+// URL is the site page of the hit; authors, genres and techniques lead to the filtered gallery.
+type SearchHit struct {
+	Type    SearchHitType `json:"type"`
+	ID      uint64        `json:"id"`
+	Slug    string        `json:"slug,omitempty"`
+	Title   string        `json:"title"`
+	Snippet string        `json:"snippet"`
+	URL     string        `json:"url,omitempty"`
+	Rank    float64       `json:"rank"`
+}
+
+// SearchQuery is a web-search style query: words, "quoted phrases", -excluded words, or.
+type SearchQuery struct {
+	Q     string `form:"q"`
+	Limit int    `form:"limit"`
+}
+
+// SearchSuggestion is offered while the visitor types, before a full search:
+// picture titles, author names and genres similar to the input, typos included.
+// URL is where choosing the suggestion leads.
+type SearchSuggestion struct {
+	Type  SearchHitType `json:"type"`
+	ID    uint64        `json:"id"`
+	Slug  string        `json:"slug,omitempty"`
+	Text  string        `json:"text"`
+	URL   string        `json:"url"`
+	Score float64       `json:"score"`
+}
+
+var (
+	ErrSearchQueryEmpty = errors.New("search query is empty")
+)
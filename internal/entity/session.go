@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+type AdminSession struct {
+	ID        uint64
+	CSRFToken string
+	IP        string
+	UserAgent string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+)
@@ -0,0 +1,127 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// WebhookEvent names a catalog change delivered to webhook subscribers.
+type WebhookEvent string
+
+const (
+	WebhookPictureCreated WebhookEvent = "picture.created"
+	WebhookPictureUpdated WebhookEvent = "picture.updated"
+	WebhookPicturePriced  WebhookEvent = "picture.priced"
+	WebhookPictureSold    WebhookEvent = "picture.sold"
+	WebhookPictureDeleted WebhookEvent = "picture.deleted"
+)
+
+var WebhookEvents = []WebhookEvent{
+	WebhookPictureCreated,
+	WebhookPictureUpdated,
+	WebhookPicturePriced,
+	WebhookPictureSold,
+	WebhookPictureDeleted,
+}
+
+func (e WebhookEvent) Valid() bool {
+	for _, event := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookSubscription receives the listed events. The secret signs deliveries
+// and is never returned by the API.
+type WebhookSubscription struct {
+	ID        uint64         `json:"id"`
+	URL       string         `json:"url"`
+	Secret    string         `json:"-"`
+	Events    []WebhookEvent `json:"events"`
+	Active    bool           `json:"active"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type WebhookSubscriptionCreateRequest struct {
+	URL    string         `json:"url" binding:"required,url,max=2048"`
+	Secret string         `json:"secret" binding:"required,min=16,max=255"`
+	Events []WebhookEvent `json:"events" binding:"required,min=1"`
+}
+
+type WebhookSubscriptionUpdateRequest struct {
+	URL    *string        `json:"url" binding:"omitempty,url,max=2048"`
+	Secret *string        `json:"secret" binding:"omitempty,min=16,max=255"`
+	Events []WebhookEvent `json:"events" binding:"omitempty,min=1"`
+	Active *bool          `json:"active"`
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+func (s WebhookDeliveryStatus) Valid() bool {
+	return s == WebhookDeliveryPending || s == WebhookDeliveryDelivered || s == WebhookDeliveryFailed
+}
+
+// WebhookDelivery is one event sent to one subscription. EventID comes from the
+// outbox and is the same for every subscriber, so receivers can deduplicate.
+type WebhookDelivery struct {
+	ID             uint64                `json:"id"`
+	SubscriptionID uint64                `json:"subscription_id"`
+	EventID        uint64                `json:"event_id"`
+	Event          WebhookEvent          `json:"event"`
+	PictureID      uint64                `json:"picture_id"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	ResponseStatus *int                  `json:"response_status"`
+	Error          string                `json:"error"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at"`
+}
+
+type WebhookDeliveryFilter struct {
+	SubscriptionID uint64                `form:"subscription_id"`
+	Status         WebhookDeliveryStatus `form:"status"`
+	Event          WebhookEvent          `form:"event"`
+	Limit          uint64                `form:"limit"`
+	Offset         uint64                `form:"offset"`
+}
+
+// WebhookPayload is the JSON body of a delivery. Data is the picture as it was
+// right after the change.
+type WebhookPayload struct {
+	EventID    uint64          `json:"event_id"`
+	Event      WebhookEvent    `json:"event"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// WebhookDispatch is a claimed delivery together with everything needed to send it.
+type WebhookDispatch struct {
+	DeliveryID uint64
+	Attempts   int
+	URL        string
+	Secret     string
+	Payload    WebhookPayload
+}
+
+// WebhookResult is the outcome of one delivery attempt.
+type WebhookResult struct {
+	ResponseStatus *int
+	Err            error
+}
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook subscription not found")
+	ErrUnknownWebhookEvent     = errors.New("unknown webhook event")
+	ErrUnknownWebhookStatus    = errors.New("unknown webhook delivery status")
+	ErrWebhookDeliveryNotFound = errors.New("failed webhook delivery not found")
+)
@@ -0,0 +1,28 @@
+package entity
+
+import "errors"
+
+// WishlistOwner identifies whose wishlist is used: a signed-in customer or an
+// anonymous visitor recognised by the id stored in a cookie. The customer wins
+// when both are set.
+type WishlistOwner struct {
+	CustomerID uint64
+	VisitorID  string
+}
+
+func (o WishlistOwner) Empty() bool {
+	return o.CustomerID == 0 && o.VisitorID == ""
+}
+
+type FavouritePicture struct {
+	PictureID uint64        `json:"picture_id"`
+	Title     string        `json:"title"`
+	Status    PictureStatus `json:"status"`
+	Count     int           `json:"count"`
+}
+
+type FavouritesFilter struct {
+	Limit uint64 `form:"limit"`
+}
+
+var ErrWishlistOwnerRequired = errors.New("wishlist owner required")
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+)
+
+const (
+	_apiKeyPrefix     = "blk_"
+	_apiKeySize       = 32
+	_apiKeyPrefixSize = 12
+)
+
+type APIKeysUseCase struct {
+	repo APIKeysRepo
+}
+
+var _ APIKeys = (*APIKeysUseCase)(nil)
+
+func NewAPIKeysUseCase(repo APIKeysRepo) *APIKeysUseCase {
+	return &APIKeysUseCase{repo: repo}
+}
+
+func (uc *APIKeysUseCase) GetAPIKeys(ctx context.Context) ([]entity.APIKey, error) {
+	keys, err := uc.repo.GetAPIKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't get api keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (uc *APIKeysUseCase) CreateAPIKey(ctx context.Context, req entity.APIKeyCreateRequest) (*entity.APIKeyCreateResponse, error) {
+	for _, scope := range req.Scopes {
+		if !slices.Contains(entity.APIKeyScopes, scope) {
+			return nil, fmt.Errorf("%w: %s", entity.ErrUnknownScope, scope)
+		}
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, entity.ErrExpiryInPast
+	}
+
+	secret, err := randomToken(_apiKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("can't generate api key: %w", err)
+	}
+	raw := _apiKeyPrefix + secret
+
+	key, err := uc.repo.CreateAPIKey(ctx, entity.APIKey{
+		Name:      req.Name,
+		Prefix:    raw[:_apiKeyPrefixSize],
+		Scopes:    slices.Compact(slices.Sorted(slices.Values(req.Scopes))),
+		ExpiresAt: req.ExpiresAt,
+	}, hashToken(raw))
+	if err != nil {
+		return nil, fmt.Errorf("can't create api key: %w", err)
+	}
+
+	return &entity.APIKeyCreateResponse{APIKey: *key, Key: raw}, nil
+}
+
+func (uc *APIKeysUseCase) RevokeAPIKey(ctx context.Context, id uint64) error {
+	if err := uc.repo.RevokeAPIKey(ctx, id); err != nil {
+		return fmt.Errorf("can't revoke api key: %w", err)
+	}
+	return nil
+}
+
+// CheckAPIKey resolves a raw key into the key name and its scopes and records its usage.
+func (uc *APIKeysUseCase) CheckAPIKey(ctx context.Context, key string) (string, []string, error) {
+	apiKey, err := uc.repo.GetAPIKeyByHash(ctx, hashToken(key))
+	if err != nil {
+		return "", nil, fmt.Errorf("can't get api key: %w", err)
+	}
+
+	if apiKey.RevokedAt != nil {
+		return "", nil, entity.ErrInvalidAPIKey
+	}
+	if apiKey.ExpiresAt != nil && apiKey.ExpiresAt.Before(time.Now()) {
+		return "", nil, entity.ErrInvalidAPIKey
+	}
+
+	if err := uc.repo.TouchAPIKey(ctx, apiKey.ID); err != nil {
+		return "", nil, fmt.Errorf("can't update api key last usage: %w", err)
+	}
+
+	return apiKey.Name, apiKey.Scopes, nil
+}
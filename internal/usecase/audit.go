@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+)
+
+const (
+	_defaultAuditLimit = 50
+	_maxAuditLimit     = 500
+
+	_systemActor = "system"
+)
+
+type auditMetaKey struct{}
+
+// AuditMeta carries request details for audit records through the context.
+// Actor is resolved lazily because authentication runs after the meta is attached.
+type AuditMeta struct {
+	IP       string
+	Actor    func() string
+	recorded atomic.Bool
+}
+
+func WithAuditMeta(ctx context.Context, meta *AuditMeta) context.Context {
+	return context.WithValue(ctx, auditMetaKey{}, meta)
+}
+
+func auditMetaFrom(ctx context.Context) *AuditMeta {
+	meta, _ := ctx.Value(auditMetaKey{}).(*AuditMeta)
+	return meta
+}
+
+// Recorded reports whether a usecase already wrote an audit record for the request.
+func (m *AuditMeta) Recorded() bool {
+	return m.recorded.Load()
+}
+
+type AuditUseCase struct {
+	repo AuditRepo
+}
+
+var _ Audit = (*AuditUseCase)(nil)
+
+func NewAuditUseCase(repo AuditRepo) *AuditUseCase {
+	return &AuditUseCase{repo: repo}
+}
+
+func (uc *AuditUseCase) GetAuditLog(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditRecord, error) {
+	if filter.Limit == 0 {
+		filter.Limit = _defaultAuditLimit
+	}
+	if filter.Limit > _maxAuditLimit {
+		filter.Limit = _maxAuditLimit
+	}
+
+	records, err := uc.repo.GetAuditLog(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("can't get audit log: %w", err)
+	}
+	return records, nil
+}
+
+// Record stores who did what. For updates only the top-level fields that changed are kept in before/after.
+func (uc *AuditUseCase) Record(ctx context.Context, action, entityType, entityID string, before, after any) error {
+	record := entity.AuditRecord{
+		Actor:      _systemActor,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+	}
+
+	if meta := auditMetaFrom(ctx); meta != nil {
+		meta.recorded.Store(true)
+		record.IP = meta.IP
+		if meta.Actor != nil {
+			if actor := meta.Actor(); actor != "" {
+				record.Actor = actor
+			}
+		}
+	}
+
+	var err error
+	record.Before, record.After, err = auditDiff(before, after)
+	if err != nil {
+		return fmt.Errorf("can't build audit diff: %w", err)
+	}
+
+	if err := uc.repo.CreateAuditRecord(ctx, record); err != nil {
+		return fmt.Errorf("can't create audit record: %w", err)
+	}
+	return nil
+}
+
+func auditDiff(before, after any) (json.RawMessage, json.RawMessage, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if beforeFields != nil && afterFields != nil {
+		for key, value := range beforeFields {
+			if other, ok := afterFields[key]; ok && reflect.DeepEqual(value, other) {
+				delete(beforeFields, key)
+				delete(afterFields, key)
+			}
+		}
+	}
+
+	return marshalAuditFields(beforeFields), marshalAuditFields(afterFields), nil
+}
+
+func auditFields(v any) (map[string]any, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil()) {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func marshalAuditFields(fields map[string]any) json.RawMessage {
+	if fields == nil {
+		return nil
+	}
+	raw, _ := json.Marshal(fields)
+	return raw
+}
@@ -0,0 +1,619 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"strconv"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
+)
+
+// Audited* decorators write an audit record after every successful mutation of the wrapped usecase.
+// A failed audit write is logged and doesn't fail the mutation itself.
+
+type AuditedPicturesUseCase struct {
+	Pictures
+	audit Audit
+	l     logger.Interface
+}
+
+var _ Pictures = (*AuditedPicturesUseCase)(nil)
+
+func NewAuditedPicturesUseCase(pictures Pictures, audit Audit, l logger.Interface) *AuditedPicturesUseCase {
+	return &AuditedPicturesUseCase{Pictures: pictures, audit: audit, l: l}
+}
+
+func (uc *AuditedPicturesUseCase) CreatePicture(ctx context.Context, req entity.PictureCreateRequest) (uint64, error) {
+	id, err := uc.Pictures.CreatePicture(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionCreate, "picture", id, nil, req)
+	return id, nil
+}
+
+func (uc *AuditedPicturesUseCase) UpdatePicture(ctx context.Context, id uint64, req entity.PictureUpdateRequest) error {
+	before := uc.snapshot(ctx, id)
+
+	if err := uc.Pictures.UpdatePicture(ctx, id, req); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionUpdate, "picture", id, before, uc.snapshot(ctx, id))
+	return nil
+}
+
+func (uc *AuditedPicturesUseCase) DeletePicture(ctx context.Context, id uint64) error {
+	before := uc.snapshot(ctx, id)
+
+	if err := uc.Pictures.DeletePicture(ctx, id); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionDelete, "picture", id, before, nil)
+	return nil
+}
+
+func (uc *AuditedPicturesUseCase) ChangePictureStatus(ctx context.Context, id uint64, req entity.PictureStatusRequest) error {
+	before := uc.snapshot(ctx, id)
+
+	if err := uc.Pictures.ChangePictureStatus(ctx, id, req); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionUpdate, "picture", id, before, uc.snapshot(ctx, id))
+	return nil
+}
+
+func (uc *AuditedPicturesUseCase) ReservePicture(ctx context.Context, id uint64) error {
+	before := uc.snapshot(ctx, id)
+
+	if err := uc.Pictures.ReservePicture(ctx, id); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionUpdate, "picture", id, before, uc.snapshot(ctx, id))
+	return nil
+}
+
+func (uc *AuditedPicturesUseCase) UploadPhoto(
+	ctx context.Context,
+	fileHeader *multipart.FileHeader,
+	req entity.PhotoUploadRequest,
+) (*entity.PhotoUploadResponse, error) {
+	resp, err := uc.Pictures.UploadPhoto(ctx, fileHeader, req)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionCreate, "picture_photo", resp.ID, nil, map[string]any{
+		"picture_id": req.PictureID,
+		"is_main":    req.IsMain,
+		"url":        resp.URL,
+	})
+	return resp, nil
+}
+
+func (uc *AuditedPicturesUseCase) DeletePhoto(ctx context.Context, pictureID, photoID uint64) (*entity.PhotoDeleteResponse, error) {
+	resp, err := uc.Pictures.DeletePhoto(ctx, pictureID, photoID)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionDelete, "picture_photo", photoID, map[string]any{"picture_id": pictureID}, nil)
+	return resp, nil
+}
+
+func (uc *AuditedPicturesUseCase) snapshot(ctx context.Context, id uint64) *entity.Picture {
+	picture, err := uc.Pictures.GetPictureByID(ctx, id, entity.PriceQuery{})
+	if err != nil {
+		return nil
+	}
+	return picture
+}
+
+type AuditedNewsUseCase struct {
+	News
+	audit Audit
+	l     logger.Interface
+}
+
+var _ News = (*AuditedNewsUseCase)(nil)
+
+func NewAuditedNewsUseCase(news News, audit Audit, l logger.Interface) *AuditedNewsUseCase {
+	return &AuditedNewsUseCase{News: news, audit: audit, l: l}
+}
+
+func (uc *AuditedNewsUseCase) CreateNews(ctx context.Context, req entity.NewsCreateRequest) (uint64, error) {
+	id, err := uc.News.CreateNews(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionCreate, "news", id, nil, req)
+	return id, nil
+}
+
+func (uc *AuditedNewsUseCase) UpdateNews(ctx context.Context, id uint64, req entity.NewsUpdateRequest) error {
+	before := uc.snapshot(ctx, id)
+
+	if err := uc.News.UpdateNews(ctx, id, req); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionUpdate, "news", id, before, uc.snapshot(ctx, id))
+	return nil
+}
+
+func (uc *AuditedNewsUseCase) DeleteNews(ctx context.Context, id uint64) error {
+	before := uc.snapshot(ctx, id)
+
+	if err := uc.News.DeleteNews(ctx, id); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionDelete, "news", id, before, nil)
+	return nil
+}
+
+func (uc *AuditedNewsUseCase) SetNewsLinks(ctx context.Context, id uint64, req entity.NewsLinksRequest) error {
+	before := uc.snapshot(ctx, id)
+
+	if err := uc.News.SetNewsLinks(ctx, id, req); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionUpdate, "news", id, before, uc.snapshot(ctx, id))
+	return nil
+}
+
+func (uc *AuditedNewsUseCase) UploadNewsCover(ctx context.Context, id uint64, fileHeader *multipart.FileHeader) (string, error) {
+	url, err := uc.News.UploadNewsCover(ctx, id, fileHeader)
+	if err != nil {
+		return "", err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionUpdate, "news", id, nil, map[string]any{"cover_url": url})
+	return url, nil
+}
+
+func (uc *AuditedNewsUseCase) snapshot(ctx context.Context, id uint64) *entity.News {
+	news, err := uc.News.PreviewNews(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return news
+}
+
+type AuditedReferencesUseCase struct {
+	References
+	audit Audit
+	l     logger.Interface
+}
+
+var _ References = (*AuditedReferencesUseCase)(nil)
+
+func NewAuditedReferencesUseCase(references References, audit Audit, l logger.Interface) *AuditedReferencesUseCase {
+	return &AuditedReferencesUseCase{References: references, audit: audit, l: l}
+}
+
+func (uc *AuditedReferencesUseCase) CreateGenre(ctx context.Context, name string) (uint64, error) {
+	id, err := uc.References.CreateGenre(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionCreate, "genre", id, nil, entity.Genre{ID: id, Name: name})
+	return id, nil
+}
+
+func (uc *AuditedReferencesUseCase) DeleteGenre(ctx context.Context, id uint64) error {
+	genres, _ := uc.References.GetGenres(ctx)
+	before := findReference(genres, func(g entity.Genre) bool { return g.ID == id })
+
+	if err := uc.References.DeleteGenre(ctx, id); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionDelete, "genre", id, before, nil)
+	return nil
+}
+
+func (uc *AuditedReferencesUseCase) CreateAuthor(ctx context.Context, fullName string) (uint64, error) {
+	id, err := uc.References.CreateAuthor(ctx, fullName)
+	if err != nil {
+		return 0, err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionCreate, "author", id, nil, entity.Author{ID: id, FullName: fullName})
+	return id, nil
+}
+
+func (uc *AuditedReferencesUseCase) DeleteAuthor(ctx context.Context, id uint64) error {
+	authors, _ := uc.References.GetAuthors(ctx)
+	before := findReference(authors, func(a entity.Author) bool { return a.ID == id })
+
+	if err := uc.References.DeleteAuthor(ctx, id); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionDelete, "author", id, before, nil)
+	return nil
+}
+
+func (uc *AuditedReferencesUseCase) CreateDimension(ctx context.Context, width, height int) (uint64, error) {
+	id, err := uc.References.CreateDimension(ctx, width, height)
+	if err != nil {
+		return 0, err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionCreate, "dimension", id, nil, entity.Dimension{ID: id, Width: width, Height: height})
+	return id, nil
+}
+
+func (uc *AuditedReferencesUseCase) DeleteDimension(ctx context.Context, id uint64) error {
+	dimensions, _ := uc.References.GetDimensions(ctx)
+	before := findReference(dimensions, func(d entity.Dimension) bool { return d.ID == id })
+
+	if err := uc.References.DeleteDimension(ctx, id); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionDelete, "dimension", id, before, nil)
+	return nil
+}
+
+func (uc *AuditedReferencesUseCase) CreateWorkTechnique(ctx context.Context, name string) (uint64, error) {
+	id, err := uc.References.CreateWorkTechnique(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionCreate, "work_technique", id, nil, entity.WorkTechnique{ID: id, Name: name})
+	return id, nil
+}
+
+func (uc *AuditedReferencesUseCase) DeleteWorkTechnique(ctx context.Context, id uint64) error {
+	techniques, _ := uc.References.GetWorkTechniques(ctx)
+	before := findReference(techniques, func(t entity.WorkTechnique) bool { return t.ID == id })
+
+	if err := uc.References.DeleteWorkTechnique(ctx, id); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionDelete, "work_technique", id, before, nil)
+	return nil
+}
+
+type AuditedExhibitionsUseCase struct {
+	Exhibitions
+	audit Audit
+	l     logger.Interface
+}
+
+var _ Exhibitions = (*AuditedExhibitionsUseCase)(nil)
+
+func NewAuditedExhibitionsUseCase(exhibitions Exhibitions, audit Audit, l logger.Interface) *AuditedExhibitionsUseCase {
+	return &AuditedExhibitionsUseCase{Exhibitions: exhibitions, audit: audit, l: l}
+}
+
+func (uc *AuditedExhibitionsUseCase) CreateExhibition(ctx context.Context, req entity.ExhibitionCreateRequest) (uint64, error) {
+	id, err := uc.Exhibitions.CreateExhibition(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionCreate, "exhibition", id, nil, req)
+	return id, nil
+}
+
+func (uc *AuditedExhibitionsUseCase) UpdateExhibition(ctx context.Context, id uint64, req entity.ExhibitionUpdateRequest) error {
+	before := uc.snapshot(ctx, id)
+
+	if err := uc.Exhibitions.UpdateExhibition(ctx, id, req); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionUpdate, "exhibition", id, before, uc.snapshot(ctx, id))
+	return nil
+}
+
+func (uc *AuditedExhibitionsUseCase) SetExhibitionPictures(ctx context.Context, id uint64, req entity.ExhibitionPicturesRequest) error {
+	before := uc.snapshot(ctx, id)
+
+	if err := uc.Exhibitions.SetExhibitionPictures(ctx, id, req); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionUpdate, "exhibition", id, before, uc.snapshot(ctx, id))
+	return nil
+}
+
+func (uc *AuditedExhibitionsUseCase) UploadCover(ctx context.Context, id uint64, fileHeader *multipart.FileHeader) (string, error) {
+	url, err := uc.Exhibitions.UploadCover(ctx, id, fileHeader)
+	if err != nil {
+		return "", err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionUpdate, "exhibition", id, nil, map[string]any{"cover_url": url})
+	return url, nil
+}
+
+func (uc *AuditedExhibitionsUseCase) DeleteExhibition(ctx context.Context, id uint64) error {
+	before := uc.snapshot(ctx, id)
+
+	if err := uc.Exhibitions.DeleteExhibition(ctx, id); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionDelete, "exhibition", id, before, nil)
+	return nil
+}
+
+func (uc *AuditedExhibitionsUseCase) snapshot(ctx context.Context, id uint64) *entity.Exhibition {
+	exhibition, err := uc.Exhibitions.GetExhibitionByID(ctx, id, entity.PriceQuery{})
+	if err != nil {
+		return nil
+	}
+	return exhibition
+}
+
+type AuditedReservationsUseCase struct {
+	Reservations
+	audit Audit
+	l     logger.Interface
+}
+
+var _ Reservations = (*AuditedReservationsUseCase)(nil)
+
+func NewAuditedReservationsUseCase(reservations Reservations, audit Audit, l logger.Interface) *AuditedReservationsUseCase {
+	return &AuditedReservationsUseCase{Reservations: reservations, audit: audit, l: l}
+}
+
+func (uc *AuditedReservationsUseCase) CreateReservation(
+	ctx context.Context,
+	pictureID uint64,
+	req entity.ReservationCreateRequest,
+) (uint64, error) {
+	id, err := uc.Reservations.CreateReservation(ctx, pictureID, req)
+	if err != nil {
+		return 0, err
+	}
+
+	reservations, _ := uc.Reservations.GetReservations(ctx, pictureID)
+	after := findReference(reservations, func(r entity.Reservation) bool { return r.ID == id })
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionCreate, "reservation", id, nil, after)
+	return id, nil
+}
+
+type AuditedOrdersUseCase struct {
+	Orders
+	audit Audit
+	l     logger.Interface
+}
+
+var _ Orders = (*AuditedOrdersUseCase)(nil)
+
+// NewAuditedOrdersUseCase records the admin actions on orders only: orders placed
+// by customers, payment notifications and expiry are not admin mutations.
+func NewAuditedOrdersUseCase(orders Orders, audit Audit, l logger.Interface) *AuditedOrdersUseCase {
+	return &AuditedOrdersUseCase{Orders: orders, audit: audit, l: l}
+}
+
+func (uc *AuditedOrdersUseCase) CancelOrder(ctx context.Context, id uint64) error {
+	before := uc.snapshot(ctx, id)
+
+	if err := uc.Orders.CancelOrder(ctx, id); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionUpdate, "order", id, before, uc.snapshot(ctx, id))
+	return nil
+}
+
+func (uc *AuditedOrdersUseCase) snapshot(ctx context.Context, id uint64) *entity.Order {
+	order, err := uc.Orders.GetOrderByID(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return order
+}
+
+type AuditedDiscountsUseCase struct {
+	Discounts
+	audit Audit
+	l     logger.Interface
+}
+
+var _ Discounts = (*AuditedDiscountsUseCase)(nil)
+
+func NewAuditedDiscountsUseCase(discounts Discounts, audit Audit, l logger.Interface) *AuditedDiscountsUseCase {
+	return &AuditedDiscountsUseCase{Discounts: discounts, audit: audit, l: l}
+}
+
+func (uc *AuditedDiscountsUseCase) CreateDiscount(ctx context.Context, req entity.DiscountCreateRequest) (uint64, error) {
+	id, err := uc.Discounts.CreateDiscount(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionCreate, "discount", id, nil, uc.snapshot(ctx, id))
+	return id, nil
+}
+
+func (uc *AuditedDiscountsUseCase) DeleteDiscount(ctx context.Context, id uint64) error {
+	before := uc.snapshot(ctx, id)
+
+	if err := uc.Discounts.DeleteDiscount(ctx, id); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionDelete, "discount", id, before, nil)
+	return nil
+}
+
+func (uc *AuditedDiscountsUseCase) snapshot(ctx context.Context, id uint64) *entity.DiscountRule {
+	discounts, _ := uc.Discounts.GetDiscounts(ctx)
+	return findReference(discounts, func(d entity.DiscountRule) bool { return d.ID == id })
+}
+
+type auditedSubscription struct {
+	*entity.WebhookSubscription
+	SecretRotated bool `json:"secret_rotated,omitempty"`
+}
+
+type AuditedWebhooksUseCase struct {
+	Webhooks
+	audit Audit
+	l     logger.Interface
+}
+
+var _ Webhooks = (*AuditedWebhooksUseCase)(nil)
+
+// NewAuditedWebhooksUseCase records subscription changes. Snapshots come from
+// entity.WebhookSubscription, which never serializes the signing secret.
+func NewAuditedWebhooksUseCase(webhooks Webhooks, audit Audit, l logger.Interface) *AuditedWebhooksUseCase {
+	return &AuditedWebhooksUseCase{Webhooks: webhooks, audit: audit, l: l}
+}
+
+func (uc *AuditedWebhooksUseCase) CreateSubscription(ctx context.Context, req entity.WebhookSubscriptionCreateRequest) (uint64, error) {
+	id, err := uc.Webhooks.CreateSubscription(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionCreate, "webhook", id, nil, uc.snapshot(ctx, id))
+	return id, nil
+}
+
+func (uc *AuditedWebhooksUseCase) UpdateSubscription(ctx context.Context, id uint64, req entity.WebhookSubscriptionUpdateRequest) error {
+	before := uc.snapshot(ctx, id)
+
+	if err := uc.Webhooks.UpdateSubscription(ctx, id, req); err != nil {
+		return err
+	}
+
+	// the secret itself stays out of the log, only the fact it was rotated
+	after := auditedSubscription{WebhookSubscription: uc.snapshot(ctx, id), SecretRotated: req.Secret != nil}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionUpdate, "webhook", id, before, after)
+	return nil
+}
+
+func (uc *AuditedWebhooksUseCase) DeleteSubscription(ctx context.Context, id uint64) error {
+	before := uc.snapshot(ctx, id)
+
+	if err := uc.Webhooks.DeleteSubscription(ctx, id); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionDelete, "webhook", id, before, nil)
+	return nil
+}
+
+func (uc *AuditedWebhooksUseCase) snapshot(ctx context.Context, id uint64) *entity.WebhookSubscription {
+	subscriptions, _ := uc.Webhooks.GetSubscriptions(ctx)
+	return findReference(subscriptions, func(s entity.WebhookSubscription) bool { return s.ID == id })
+}
+
+type AuditedInquiriesUseCase struct {
+	Inquiries
+	audit Audit
+	l     logger.Interface
+}
+
+var _ Inquiries = (*AuditedInquiriesUseCase)(nil)
+
+func NewAuditedInquiriesUseCase(inquiries Inquiries, audit Audit, l logger.Interface) *AuditedInquiriesUseCase {
+	return &AuditedInquiriesUseCase{Inquiries: inquiries, audit: audit, l: l}
+}
+
+func (uc *AuditedInquiriesUseCase) AssignInquiry(ctx context.Context, id uint64, req entity.InquiryAssignRequest) error {
+	before := uc.snapshot(ctx, id)
+
+	if err := uc.Inquiries.AssignInquiry(ctx, id, req); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionUpdate, "inquiry", id, before, uc.snapshot(ctx, id))
+	return nil
+}
+
+func (uc *AuditedInquiriesUseCase) ChangeInquiryStatus(ctx context.Context, id uint64, req entity.InquiryStatusRequest) error {
+	before := uc.snapshot(ctx, id)
+
+	if err := uc.Inquiries.ChangeInquiryStatus(ctx, id, req); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionUpdate, "inquiry", id, before, uc.snapshot(ctx, id))
+	return nil
+}
+
+func (uc *AuditedInquiriesUseCase) snapshot(ctx context.Context, id uint64) *entity.Inquiry {
+	inquiry, err := uc.Inquiries.GetInquiryByID(ctx, id)
+	if err != nil {
+		return nil
+	}
+	return inquiry
+}
+
+type AuditedAPIKeysUseCase struct {
+	APIKeys
+	audit Audit
+	l     logger.Interface
+}
+
+var _ APIKeys = (*AuditedAPIKeysUseCase)(nil)
+
+func NewAuditedAPIKeysUseCase(apiKeys APIKeys, audit Audit, l logger.Interface) *AuditedAPIKeysUseCase {
+	return &AuditedAPIKeysUseCase{APIKeys: apiKeys, audit: audit, l: l}
+}
+
+func (uc *AuditedAPIKeysUseCase) CreateAPIKey(ctx context.Context, req entity.APIKeyCreateRequest) (*entity.APIKeyCreateResponse, error) {
+	resp, err := uc.APIKeys.CreateAPIKey(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// the raw key is shown once to the admin and never stored, only its metadata is recorded
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionCreate, "api_key", resp.ID, nil, resp.APIKey)
+	return resp, nil
+}
+
+func (uc *AuditedAPIKeysUseCase) RevokeAPIKey(ctx context.Context, id uint64) error {
+	before := uc.snapshot(ctx, id)
+
+	if err := uc.APIKeys.RevokeAPIKey(ctx, id); err != nil {
+		return err
+	}
+
+	// a revoked key stays listed, so the record keeps both states
+	recordAudit(ctx, uc.audit, uc.l, entity.AuditActionDelete, "api_key", id, before, uc.snapshot(ctx, id))
+	return nil
+}
+
+func (uc *AuditedAPIKeysUseCase) snapshot(ctx context.Context, id uint64) *entity.APIKey {
+	keys, _ := uc.APIKeys.GetAPIKeys(ctx)
+	return findReference(keys, func(k entity.APIKey) bool { return k.ID == id })
+}
+
+func findReference[T any](items []T, match func(T) bool) *T {
+	for i := range items {
+		if match(items[i]) {
+			return &items[i]
+		}
+	}
+	return nil
+}
+
+func recordAudit(ctx context.Context, audit Audit, l logger.Interface, action, entityType string, id uint64, before, after any) {
+	err := audit.Record(ctx, action, entityType, strconv.FormatUint(id, 10), before, after)
+	if err != nil {
+		l.Error(fmt.Errorf("usecase - audit - %s %s %d: %w", action, entityType, id, err))
+	}
+}
@@ -2,30 +2,316 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/alexKudryavtsev-web/beyond-limits-app/config"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/totp"
 	"github.com/golang-jwt/jwt"
 )
 
+const (
+	_challengeTokenTTL  = 5 * time.Minute
+	_challengeAttempts  = 5
+	_recoveryCodesCount = 10
+)
+
 type AuthUseCase struct {
 	adminCfg config.Admin
+	repo     AuthRepo
+
+	mu sync.Mutex
+	// failures counts wrong codes per challenge ID until the challenge expires
+	failures map[string]challengeFailures
 }
 
-func NewAuthUseCase(adminCfg config.Admin) *AuthUseCase {
-	return &AuthUseCase{adminCfg: adminCfg}
+type challengeFailures struct {
+	count   int
+	expires time.Time
+}
+
+func NewAuthUseCase(adminCfg config.Admin, repo AuthRepo) *AuthUseCase {
+	return &AuthUseCase{adminCfg: adminCfg, repo: repo, failures: make(map[string]challengeFailures)}
 }
 
 var _ Auth = (*AuthUseCase)(nil)
 
-func (a *AuthUseCase) Login(ctx context.Context, login, password string) (string, error) {
+func (a *AuthUseCase) Login(ctx context.Context, login, password string) (*entity.AuthResponse, error) {
 	if login != a.adminCfg.Login || password != a.adminCfg.Password {
-		return "", errors.New("invalid credentials")
+		return nil, entity.ErrInvalidCredentials
+	}
+
+	enabled, err := a.twoFactorEnabled(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !enabled {
+		return a.issueAccessToken()
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("can't generate challenge id: %w", err)
+	}
+	challengeID := hex.EncodeToString(buf)
+
+	challenge := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"jti":           challengeID,
+		"sub":           login,
+		"mfa_challenge": true,
+		"exp":           time.Now().Add(_challengeTokenTTL).Unix(),
+	})
+
+	challengeToken, err := challenge.SignedString([]byte(a.adminCfg.JWTSecret))
+	if err != nil {
+		return nil, fmt.Errorf("can't sign challenge token: %w", err)
+	}
+
+	return &entity.AuthResponse{TwoFactorRequired: true, ChallengeToken: challengeToken}, nil
+}
+
+// CompleteLogin rejects the challenge after _challengeAttempts wrong codes,
+// so the admin has to enter the password again instead of guessing further.
+func (a *AuthUseCase) CompleteLogin(ctx context.Context, challengeToken, code string) (*entity.AuthResponse, error) {
+	challengeID, expires, err := a.parseChallenge(challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if a.challengeFailures(challengeID) >= _challengeAttempts {
+		return nil, fmt.Errorf("%w: too many wrong codes", entity.ErrInvalidChallenge)
+	}
+
+	if err := a.verifyCode(ctx, code, true); err != nil {
+		if errors.Is(err, entity.ErrInvalidTwoFactorCode) {
+			a.recordChallengeFailure(challengeID, expires)
+		}
+		return nil, err
+	}
+
+	return a.issueAccessToken()
+}
+
+func (a *AuthUseCase) EnrollTOTP(ctx context.Context) (*entity.TOTPEnrollResponse, error) {
+	enabled, err := a.twoFactorEnabled(ctx)
+	if err != nil {
+		return nil, err
 	}
+	if enabled {
+		return nil, entity.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("can't generate totp secret: %w", err)
+	}
+
+	if err := a.repo.SaveTOTPSecret(ctx, a.adminCfg.Login, secret); err != nil {
+		return nil, fmt.Errorf("can't save totp secret: %w", err)
+	}
+
+	return &entity.TOTPEnrollResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(a.adminCfg.TOTPIssuer, a.adminCfg.Login, secret),
+	}, nil
+}
+
+func (a *AuthUseCase) ConfirmTOTP(ctx context.Context, code string) (*entity.RecoveryCodesResponse, error) {
+	t, err := a.repo.GetTOTP(ctx, a.adminCfg.Login)
+	if err != nil {
+		return nil, fmt.Errorf("can't get totp: %w", err)
+	}
+	if t.Enabled {
+		return nil, entity.ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := totp.Validate(t.Secret, code, time.Now())
+	if !ok {
+		return nil, entity.ErrInvalidTwoFactorCode
+	}
+
+	if err := a.repo.EnableTOTP(ctx, a.adminCfg.Login, step); err != nil {
+		return nil, fmt.Errorf("can't enable totp: %w", err)
+	}
+
+	return a.resetRecoveryCodes(ctx)
+}
+
+func (a *AuthUseCase) RegenerateRecoveryCodes(ctx context.Context, code string) (*entity.RecoveryCodesResponse, error) {
+	if err := a.verifyCode(ctx, code, false); err != nil {
+		return nil, err
+	}
+
+	return a.resetRecoveryCodes(ctx)
+}
+
+func (a *AuthUseCase) DisableTOTP(ctx context.Context, code string) error {
+	if err := a.verifyCode(ctx, code, true); err != nil {
+		return err
+	}
+
+	if err := a.repo.DeleteTOTP(ctx, a.adminCfg.Login); err != nil {
+		return fmt.Errorf("can't delete totp: %w", err)
+	}
+
+	return nil
+}
 
+func (a *AuthUseCase) issueAccessToken() (*entity.AuthResponse, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"admin": true,
 	})
 
-	return token.SignedString([]byte(a.adminCfg.JWTSecret))
+	signed, err := token.SignedString([]byte(a.adminCfg.JWTSecret))
+	if err != nil {
+		return nil, fmt.Errorf("can't sign token: %w", err)
+	}
+
+	return &entity.AuthResponse{Token: signed}, nil
+}
+
+// parseChallenge checks the challenge token and returns its ID and expiry.
+func (a *AuthUseCase) parseChallenge(challengeToken string) (string, time.Time, error) {
+	token, err := jwt.Parse(challengeToken, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(a.adminCfg.JWTSecret), nil
+	})
+	if err != nil || !token.Valid {
+		return "", time.Time{}, entity.ErrInvalidChallenge
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", time.Time{}, entity.ErrInvalidChallenge
+	}
+
+	// exp is optional for jwt.MapClaims, so require it explicitly
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return "", time.Time{}, entity.ErrInvalidChallenge
+	}
+
+	if challenge, _ := claims["mfa_challenge"].(bool); !challenge {
+		return "", time.Time{}, entity.ErrInvalidChallenge
+	}
+	if sub, _ := claims["sub"].(string); sub != a.adminCfg.Login {
+		return "", time.Time{}, entity.ErrInvalidChallenge
+	}
+
+	id, _ := claims["jti"].(string)
+	if id == "" {
+		return "", time.Time{}, entity.ErrInvalidChallenge
+	}
+
+	return id, time.Unix(int64(exp), 0), nil
+}
+
+func (a *AuthUseCase) challengeFailures(id string) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.failures[id].count
+}
+
+// recordChallengeFailure also drops the counters of expired challenges, which can't be used anyway.
+func (a *AuthUseCase) recordChallengeFailure(id string, expires time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	for key, f := range a.failures {
+		if now.After(f.expires) {
+			delete(a.failures, key)
+		}
+	}
+
+	f := a.failures[id]
+	a.failures[id] = challengeFailures{count: f.count + 1, expires: expires}
+}
+
+// verifyCode accepts a current TOTP code, and a recovery code when allowRecovery is set.
+// Every accepted code is consumed so it can't be replayed.
+func (a *AuthUseCase) verifyCode(ctx context.Context, code string, allowRecovery bool) error {
+	t, err := a.repo.GetTOTP(ctx, a.adminCfg.Login)
+	if err != nil {
+		return fmt.Errorf("can't get totp: %w", err)
+	}
+	if !t.Enabled {
+		return entity.ErrTwoFactorNotEnrolled
+	}
+
+	if step, ok := totp.Validate(t.Secret, code, time.Now()); ok {
+		used, err := a.repo.UseTOTPStep(ctx, a.adminCfg.Login, step)
+		if err != nil {
+			return fmt.Errorf("can't use totp step: %w", err)
+		}
+		if !used {
+			return entity.ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	if !allowRecovery {
+		return entity.ErrInvalidTwoFactorCode
+	}
+
+	used, err := a.repo.UseRecoveryCode(ctx, a.adminCfg.Login, hashRecoveryCode(code))
+	if err != nil {
+		return fmt.Errorf("can't use recovery code: %w", err)
+	}
+	if !used {
+		return entity.ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+func (a *AuthUseCase) twoFactorEnabled(ctx context.Context) (bool, error) {
+	t, err := a.repo.GetTOTP(ctx, a.adminCfg.Login)
+	if err != nil {
+		if errors.Is(err, entity.ErrTwoFactorNotEnrolled) {
+			return false, nil
+		}
+		return false, fmt.Errorf("can't get totp: %w", err)
+	}
+
+	return t.Enabled, nil
+}
+
+func (a *AuthUseCase) resetRecoveryCodes(ctx context.Context) (*entity.RecoveryCodesResponse, error) {
+	codes := make([]string, 0, _recoveryCodesCount)
+	hashes := make([]string, 0, _recoveryCodesCount)
+
+	for i := 0; i < _recoveryCodesCount; i++ {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("can't generate recovery code: %w", err)
+		}
+
+		raw := hex.EncodeToString(buf)
+		code := raw[:5] + "-" + raw[5:]
+
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	if err := a.repo.ReplaceRecoveryCodes(ctx, a.adminCfg.Login, hashes); err != nil {
+		return nil, fmt.Errorf("can't save recovery codes: %w", err)
+	}
+
+	return &entity.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
 }
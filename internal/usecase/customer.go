@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/config"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const _customerTokenSize = 32
+
+type CustomersUseCase struct {
+	cfg       config.Account
+	repo      CustomersRepo
+	mailer    Mailer
+	wishlists Wishlists
+}
+
+var _ Customers = (*CustomersUseCase)(nil)
+
+func NewCustomersUseCase(cfg config.Account, repo CustomersRepo, mailer Mailer, wishlists Wishlists) *CustomersUseCase {
+	return &CustomersUseCase{cfg: cfg, repo: repo, mailer: mailer, wishlists: wishlists}
+}
+
+// Register creates an unverified customer and emails a verification link.
+func (uc *CustomersUseCase) Register(ctx context.Context, req entity.CustomerRegisterRequest) (uint64, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return 0, fmt.Errorf("can't hash password: %w", err)
+	}
+
+	customer := entity.Customer{
+		Email: normalizeEmail(req.Email),
+		Name:  strings.TrimSpace(req.Name),
+	}
+
+	id, err := uc.repo.CreateCustomer(ctx, customer, string(hash))
+	if err != nil {
+		return 0, fmt.Errorf("can't create customer: %w", err)
+	}
+	customer.ID = id
+
+	if err := uc.sendVerification(ctx, customer); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (uc *CustomersUseCase) VerifyEmail(ctx context.Context, token string) error {
+	customerID, err := uc.repo.UseCustomerToken(ctx, entity.CustomerTokenVerifyEmail, hashToken(token))
+	if err != nil {
+		return fmt.Errorf("can't use verification token: %w", err)
+	}
+
+	if err := uc.repo.VerifyCustomer(ctx, customerID); err != nil {
+		return fmt.Errorf("can't verify customer: %w", err)
+	}
+	return nil
+}
+
+// ResendVerification emails a new verification link. Unknown and already
+// verified addresses are silently ignored so the endpoint can't be used to probe emails.
+func (uc *CustomersUseCase) ResendVerification(ctx context.Context, email string) error {
+	customer, _, err := uc.repo.GetCustomerByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, entity.ErrCustomerNotFound) {
+			return nil
+		}
+		return fmt.Errorf("can't get customer by email: %w", err)
+	}
+	if customer.VerifiedAt != nil {
+		return nil
+	}
+
+	return uc.sendVerification(ctx, *customer)
+}
+
+func (uc *CustomersUseCase) Login(
+	ctx context.Context,
+	req entity.CustomerLoginRequest,
+	visitorID string,
+) (*entity.CustomerLoginResponse, error) {
+	customer, hash, err := uc.repo.GetCustomerByEmail(ctx, normalizeEmail(req.Email))
+	if err != nil {
+		if errors.Is(err, entity.ErrCustomerNotFound) {
+			return nil, entity.ErrInvalidCustomerLogin
+		}
+		return nil, fmt.Errorf("can't get customer by email: %w", err)
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(req.Password)) != nil {
+		return nil, entity.ErrInvalidCustomerLogin
+	}
+	if customer.VerifiedAt == nil {
+		return nil, entity.ErrEmailNotVerified
+	}
+
+	token, err := randomToken(_customerTokenSize)
+	if err != nil {
+		return nil, fmt.Errorf("can't generate session token: %w", err)
+	}
+
+	expiresAt := time.Now().Add(uc.cfg.SessionTTL)
+	if err := uc.repo.CreateCustomerSession(ctx, customer.ID, hashToken(token), expiresAt); err != nil {
+		return nil, fmt.Errorf("can't create customer session: %w", err)
+	}
+
+	if err := uc.wishlists.MergeWishlist(ctx, visitorID, customer.ID); err != nil {
+		return nil, err
+	}
+
+	return &entity.CustomerLoginResponse{Token: token, ExpiresAt: expiresAt, Customer: *customer}, nil
+}
+
+func (uc *CustomersUseCase) Logout(ctx context.Context, token string) error {
+	if err := uc.repo.DeleteCustomerSession(ctx, hashToken(token)); err != nil {
+		return fmt.Errorf("can't delete customer session: %w", err)
+	}
+	return nil
+}
+
+func (uc *CustomersUseCase) Authenticate(ctx context.Context, token string) (*entity.Customer, error) {
+	customer, err := uc.repo.GetCustomerBySession(ctx, hashToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("can't get customer session: %w", err)
+	}
+	return customer, nil
+}
+
+// RequestPasswordReset emails a reset link. Like ResendVerification it
+// reports success for unknown addresses.
+func (uc *CustomersUseCase) RequestPasswordReset(ctx context.Context, email string) error {
+	customer, _, err := uc.repo.GetCustomerByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, entity.ErrCustomerNotFound) {
+			return nil
+		}
+		return fmt.Errorf("can't get customer by email: %w", err)
+	}
+
+	link, err := uc.issueToken(ctx, customer.ID, entity.CustomerTokenResetPassword, uc.cfg.ResetTTL, "/account/reset-password")
+	if err != nil {
+		return err
+	}
+
+	return uc.send(ctx, entity.Email{
+		To:      customer.Email,
+		Subject: "Восстановление пароля",
+		Body: fmt.Sprintf("Здравствуйте, %s!\n\nЧтобы задать новый пароль, перейдите по ссылке:\n%s\n\n"+
+			"Ссылка действует %s. Если вы не запрашивали восстановление, просто проигнорируйте это письмо.\n",
+			customer.Name, link, uc.cfg.ResetTTL),
+	})
+}
+
+// ResetPassword sets a new password and signs the customer out everywhere.
+// Following the emailed link also proves the address, so the customer becomes verified.
+func (uc *CustomersUseCase) ResetPassword(ctx context.Context, req entity.PasswordResetConfirmRequest) error {
+	customerID, err := uc.repo.UseCustomerToken(ctx, entity.CustomerTokenResetPassword, hashToken(req.Token))
+	if err != nil {
+		return fmt.Errorf("can't use reset token: %w", err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("can't hash password: %w", err)
+	}
+
+	if err := uc.repo.SetCustomerPassword(ctx, customerID, string(hash)); err != nil {
+		return fmt.Errorf("can't set customer password: %w", err)
+	}
+	if err := uc.repo.VerifyCustomer(ctx, customerID); err != nil {
+		return fmt.Errorf("can't verify customer: %w", err)
+	}
+	if err := uc.repo.DeleteCustomerSessions(ctx, customerID); err != nil {
+		return fmt.Errorf("can't delete customer sessions: %w", err)
+	}
+	return nil
+}
+
+func (uc *CustomersUseCase) sendVerification(ctx context.Context, customer entity.Customer) error {
+	link, err := uc.issueToken(ctx, customer.ID, entity.CustomerTokenVerifyEmail, uc.cfg.VerifyTTL, "/account/verify")
+	if err != nil {
+		return err
+	}
+
+	return uc.send(ctx, entity.Email{
+		To:      customer.Email,
+		Subject: "Подтверждение почты",
+		Body: fmt.Sprintf("Здравствуйте, %s!\n\nПодтвердите адрес почты, перейдя по ссылке:\n%s\n\nСсылка действует %s.\n",
+			customer.Name, link, uc.cfg.VerifyTTL),
+	})
+}
+
+// issueToken stores a one-time token and returns the site link that carries it.
+func (uc *CustomersUseCase) issueToken(
+	ctx context.Context,
+	customerID uint64,
+	purpose entity.CustomerTokenPurpose,
+	ttl time.Duration,
+	path string,
+) (string, error) {
+	token, err := randomToken(_customerTokenSize)
+	if err != nil {
+		return "", fmt.Errorf("can't generate %s token: %w", purpose, err)
+	}
+
+	if err := uc.repo.CreateCustomerToken(ctx, customerID, purpose, hashToken(token), time.Now().Add(ttl)); err != nil {
+		return "", fmt.Errorf("can't create %s token: %w", purpose, err)
+	}
+
+	return strings.TrimRight(uc.cfg.PublicURL, "/") + path + "?token=" + url.QueryEscape(token), nil
+}
+
+func (uc *CustomersUseCase) send(ctx context.Context, email entity.Email) error {
+	if err := uc.mailer.Send(ctx, email); err != nil {
+		return fmt.Errorf("can't send email: %w", err)
+	}
+	return nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
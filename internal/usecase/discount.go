@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+)
+
+type DiscountsUseCase struct {
+	repo  DiscountsRepo
+	rates *ExchangeRates
+}
+
+var _ Discounts = (*DiscountsUseCase)(nil)
+
+func NewDiscountsUseCase(repo DiscountsRepo, rates *ExchangeRates) *DiscountsUseCase {
+	return &DiscountsUseCase{repo: repo, rates: rates}
+}
+
+func (uc *DiscountsUseCase) GetDiscounts(ctx context.Context) ([]entity.DiscountRule, error) {
+	discounts, err := uc.repo.GetDiscounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't get discounts: %w", err)
+	}
+	return discounts, nil
+}
+
+func (uc *DiscountsUseCase) CreateDiscount(ctx context.Context, req entity.DiscountCreateRequest) (uint64, error) {
+	if !req.Kind.Valid() {
+		return 0, entity.ErrUnknownDiscountKind
+	}
+	if req.Kind == entity.DiscountKindPercent && req.Value > 100 {
+		return 0, entity.ErrDiscountValue
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		return 0, entity.ErrDiscountPeriod
+	}
+
+	currency, err := uc.rates.Normalize(req.Currency)
+	if err != nil {
+		return 0, err
+	}
+
+	discount := entity.DiscountRule{
+		Name:            strings.TrimSpace(req.Name),
+		Kind:            req.Kind,
+		Value:           req.Value,
+		Currency:        currency,
+		GenreID:         req.GenreID,
+		AuthorID:        req.AuthorID,
+		WorkTechniqueID: req.WorkTechniqueID,
+		PictureIDs:      req.PictureIDs,
+		StartsAt:        req.StartsAt,
+		EndsAt:          req.EndsAt,
+	}
+	if code := normalizePromoCode(req.PromoCode); code != "" {
+		discount.PromoCode = &code
+	}
+
+	id, err := uc.repo.CreateDiscount(ctx, discount)
+	if err != nil {
+		return 0, fmt.Errorf("can't create discount: %w", err)
+	}
+	return id, nil
+}
+
+func (uc *DiscountsUseCase) DeleteDiscount(ctx context.Context, id uint64) error {
+	if err := uc.repo.DeleteDiscount(ctx, id); err != nil {
+		return fmt.Errorf("can't delete discount: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+)
+
+const (
+	_defaultExhibitionsLimit = 50
+	_maxExhibitionsLimit     = 200
+)
+
+type ExhibitionsUseCase struct {
+	repo     ExhibitionsRepo
+	pictures Pictures
+}
+
+var _ Exhibitions = (*ExhibitionsUseCase)(nil)
+
+func NewExhibitionsUseCase(repo ExhibitionsRepo, pictures Pictures) *ExhibitionsUseCase {
+	return &ExhibitionsUseCase{repo: repo, pictures: pictures}
+}
+
+func (uc *ExhibitionsUseCase) GetExhibitions(ctx context.Context, filter entity.ExhibitionFilter) ([]entity.Exhibition, error) {
+	if filter.Period != "" && !filter.Period.Valid() {
+		return nil, entity.ErrUnknownPeriod
+	}
+	if filter.Limit == 0 {
+		filter.Limit = _defaultExhibitionsLimit
+	}
+	if filter.Limit > _maxExhibitionsLimit {
+		filter.Limit = _maxExhibitionsLimit
+	}
+
+	exhibitions, err := uc.repo.GetExhibitions(ctx, filter, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("can't get exhibitions: %w", err)
+	}
+	return exhibitions, nil
+}
+
+// GetExhibitionByID returns the exhibition with its pictures in curator order.
+func (uc *ExhibitionsUseCase) GetExhibitionByID(
+	ctx context.Context,
+	id uint64,
+	query entity.PriceQuery,
+) (*entity.Exhibition, error) {
+	exhibition, err := uc.repo.GetExhibitionByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("can't get exhibition by id: %w", err)
+	}
+
+	exhibition.Pictures = []entity.Picture{}
+	if len(exhibition.PictureIDs) == 0 {
+		return exhibition, nil
+	}
+
+	pictures, err := uc.pictures.GetPictures(ctx, entity.PictureFilter{IDs: exhibition.PictureIDs, PriceQuery: query})
+	if err != nil {
+		return nil, fmt.Errorf("can't get exhibition pictures: %w", err)
+	}
+
+	byID := make(map[uint64]entity.Picture, len(pictures))
+	for _, picture := range pictures {
+		byID[picture.ID] = picture
+	}
+	for _, pictureID := range exhibition.PictureIDs {
+		if picture, ok := byID[pictureID]; ok {
+			exhibition.Pictures = append(exhibition.Pictures, picture)
+		}
+	}
+
+	return exhibition, nil
+}
+
+func (uc *ExhibitionsUseCase) CreateExhibition(ctx context.Context, req entity.ExhibitionCreateRequest) (uint64, error) {
+	if !req.EndsAt.After(req.StartsAt) {
+		return 0, entity.ErrExhibitionPeriod
+	}
+
+	id, err := uc.repo.CreateExhibition(ctx, entity.Exhibition{
+		Title:       strings.TrimSpace(req.Title),
+		Description: strings.TrimSpace(req.Description),
+		Venue:       strings.TrimSpace(req.Venue),
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+		PictureIDs:  uniqueIDs(req.PictureIDs),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("can't create exhibition: %w", err)
+	}
+	return id, nil
+}
+
+func (uc *ExhibitionsUseCase) UpdateExhibition(ctx context.Context, id uint64, req entity.ExhibitionUpdateRequest) error {
+	if req.StartsAt != nil || req.EndsAt != nil {
+		exhibition, err := uc.repo.GetExhibitionByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("can't get exhibition by id: %w", err)
+		}
+
+		startsAt, endsAt := exhibition.StartsAt, exhibition.EndsAt
+		if req.StartsAt != nil {
+			startsAt = *req.StartsAt
+		}
+		if req.EndsAt != nil {
+			endsAt = *req.EndsAt
+		}
+		if !endsAt.After(startsAt) {
+			return entity.ErrExhibitionPeriod
+		}
+	}
+
+	if err := uc.repo.UpdateExhibition(ctx, id, req); err != nil {
+		return fmt.Errorf("can't update exhibition: %w", err)
+	}
+	return nil
+}
+
+func (uc *ExhibitionsUseCase) SetExhibitionPictures(ctx context.Context, id uint64, req entity.ExhibitionPicturesRequest) error {
+	if err := uc.repo.SetExhibitionPictures(ctx, id, uniqueIDs(req.PictureIDs)); err != nil {
+		return fmt.Errorf("can't set exhibition pictures: %w", err)
+	}
+	return nil
+}
+
+func (uc *ExhibitionsUseCase) UploadCover(ctx context.Context, id uint64, fileHeader *multipart.FileHeader) (string, error) {
+	url, err := saveUpload(fileHeader)
+	if err != nil {
+		return "", err
+	}
+
+	old, err := uc.repo.SetExhibitionCover(ctx, id, url)
+	if err != nil {
+		_ = removeUpload(url)
+		return "", fmt.Errorf("can't set exhibition cover: %w", err)
+	}
+
+	if old != "" {
+		if err := removeUpload(old); err != nil {
+			return "", fmt.Errorf("can't delete old cover file: %w", err)
+		}
+	}
+	return url, nil
+}
+
+func (uc *ExhibitionsUseCase) DeleteExhibition(ctx context.Context, id uint64) error {
+	cover, err := uc.repo.DeleteExhibition(ctx, id)
+	if err != nil {
+		return fmt.Errorf("can't delete exhibition: %w", err)
+	}
+
+	if cover != "" {
+		if err := removeUpload(cover); err != nil {
+			return fmt.Errorf("can't delete cover file: %w", err)
+		}
+	}
+	return nil
+}
+
+// uniqueIDs drops repeated IDs, keeping the first position of each.
+func uniqueIDs(ids []uint64) []uint64 {
+	seen := make(map[uint64]bool, len(ids))
+	unique := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
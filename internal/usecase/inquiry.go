@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/notify"
+)
+
+const (
+	_defaultInquiriesLimit = 50
+	_maxInquiriesLimit     = 500
+)
+
+type InquiriesUseCase struct {
+	repo     InquiriesRepo
+	pictures Pictures
+	notifier notify.Notifier
+}
+
+var _ Inquiries = (*InquiriesUseCase)(nil)
+
+func NewInquiriesUseCase(repo InquiriesRepo, pictures Pictures, notifier notify.Notifier) *InquiriesUseCase {
+	return &InquiriesUseCase{repo: repo, pictures: pictures, notifier: notifier}
+}
+
+// CreateInquiry stores a purchase request for the picture.
+// Requests with the honeypot filled in are rejected with entity.ErrInquirySpam.
+func (uc *InquiriesUseCase) CreateInquiry(
+	ctx context.Context,
+	pictureID uint64,
+	ip string,
+	req entity.InquiryCreateRequest,
+) (uint64, error) {
+	if req.Website != "" {
+		return 0, entity.ErrInquirySpam
+	}
+
+	picture, err := uc.pictures.GetPictureByID(ctx, pictureID, entity.PriceQuery{})
+	if err != nil {
+		return 0, fmt.Errorf("can't get picture by id: %w", err)
+	}
+
+	inquiry := entity.Inquiry{
+		PictureID:  pictureID,
+		CustomerID: req.CustomerID,
+		Name:       strings.TrimSpace(req.Name),
+		Contact:    strings.TrimSpace(req.Contact),
+		Message:    strings.TrimSpace(req.Message),
+		Status:     entity.InquiryStatusNew,
+		IP:         ip,
+	}
+
+	id, err := uc.repo.CreateInquiry(ctx, inquiry)
+	if err != nil {
+		return 0, fmt.Errorf("can't create inquiry: %w", err)
+	}
+
+	// delivery problems are reported by the notifier and must not fail the request
+	_ = uc.notifier.Notify(ctx, notify.Message{
+		Event: "inquiry.created",
+		Title: fmt.Sprintf("Новая заявка №%d", id),
+		Text: fmt.Sprintf("Картина: %s\nИмя: %s\nКонтакт: %s\n\n%s",
+			picture.Title, inquiry.Name, inquiry.Contact, inquiry.Message),
+		Data: map[string]any{"inquiry_id": id, "picture_id": pictureID},
+	})
+
+	return id, nil
+}
+
+func (uc *InquiriesUseCase) GetInquiries(ctx context.Context, filter entity.InquiryFilter) ([]entity.Inquiry, error) {
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, entity.ErrUnknownInquiryStatus
+	}
+	if filter.Limit == 0 {
+		filter.Limit = _defaultInquiriesLimit
+	}
+	if filter.Limit > _maxInquiriesLimit {
+		filter.Limit = _maxInquiriesLimit
+	}
+
+	inquiries, err := uc.repo.GetInquiries(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("can't get inquiries: %w", err)
+	}
+	return inquiries, nil
+}
+
+func (uc *InquiriesUseCase) GetInquiryByID(ctx context.Context, id uint64) (*entity.Inquiry, error) {
+	inquiry, err := uc.repo.GetInquiryByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("can't get inquiry by id: %w", err)
+	}
+	return inquiry, nil
+}
+
+func (uc *InquiriesUseCase) AssignInquiry(ctx context.Context, id uint64, req entity.InquiryAssignRequest) error {
+	if err := uc.repo.AssignInquiry(ctx, id, strings.TrimSpace(req.AssignedTo)); err != nil {
+		return fmt.Errorf("can't assign inquiry: %w", err)
+	}
+	return nil
+}
+
+func (uc *InquiriesUseCase) ChangeInquiryStatus(ctx context.Context, id uint64, req entity.InquiryStatusRequest) error {
+	if !req.Status.Valid() {
+		return entity.ErrUnknownInquiryStatus
+	}
+
+	if err := uc.repo.UpdateInquiryStatus(ctx, id, req.Status); err != nil {
+		return fmt.Errorf("can't update inquiry status: %w", err)
+	}
+	return nil
+}
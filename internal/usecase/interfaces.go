@@ -3,13 +3,67 @@ package usecase
 import (
 	"context"
 	"mime/multipart"
+	"time"
 
 	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
 )
 
 type (
 	Auth interface {
-		Login(ctx context.Context, login, password string) (string, error)
+		Login(ctx context.Context, login, password string) (*entity.AuthResponse, error)
+		CompleteLogin(ctx context.Context, challengeToken, code string) (*entity.AuthResponse, error)
+		EnrollTOTP(ctx context.Context) (*entity.TOTPEnrollResponse, error)
+		ConfirmTOTP(ctx context.Context, code string) (*entity.RecoveryCodesResponse, error)
+		RegenerateRecoveryCodes(ctx context.Context, code string) (*entity.RecoveryCodesResponse, error)
+		DisableTOTP(ctx context.Context, code string) error
+	}
+
+	AuthRepo interface {
+		GetTOTP(ctx context.Context, login string) (*entity.TOTP, error)
+		SaveTOTPSecret(ctx context.Context, login, secret string) error
+		EnableTOTP(ctx context.Context, login string, step int64) error
+		UseTOTPStep(ctx context.Context, login string, step int64) (bool, error)
+		DeleteTOTP(ctx context.Context, login string) error
+		ReplaceRecoveryCodes(ctx context.Context, login string, codeHashes []string) error
+		UseRecoveryCode(ctx context.Context, login, codeHash string) (bool, error)
+	}
+
+	Sessions interface {
+		CreateSession(ctx context.Context, ip, userAgent string) (string, *entity.AdminSession, error)
+		GetSession(ctx context.Context, token string) (*entity.AdminSession, error)
+		DeleteSession(ctx context.Context, token string) error
+	}
+
+	SessionsRepo interface {
+		CreateSession(ctx context.Context, session entity.AdminSession, tokenHash string) (*entity.AdminSession, error)
+		GetSessionByHash(ctx context.Context, tokenHash string) (*entity.AdminSession, error)
+		DeleteSessionByHash(ctx context.Context, tokenHash string) error
+		DeleteExpiredSessions(ctx context.Context) error
+	}
+
+	APIKeys interface {
+		GetAPIKeys(ctx context.Context) ([]entity.APIKey, error)
+		CreateAPIKey(ctx context.Context, req entity.APIKeyCreateRequest) (*entity.APIKeyCreateResponse, error)
+		RevokeAPIKey(ctx context.Context, id uint64) error
+		CheckAPIKey(ctx context.Context, key string) (string, []string, error)
+	}
+
+	APIKeysRepo interface {
+		GetAPIKeys(ctx context.Context) ([]entity.APIKey, error)
+		GetAPIKeyByHash(ctx context.Context, keyHash string) (*entity.APIKey, error)
+		CreateAPIKey(ctx context.Context, key entity.APIKey, keyHash string) (*entity.APIKey, error)
+		RevokeAPIKey(ctx context.Context, id uint64) error
+		TouchAPIKey(ctx context.Context, id uint64) error
+	}
+
+	Audit interface {
+		GetAuditLog(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditRecord, error)
+		Record(ctx context.Context, action, entityType, entityID string, before, after any) error
+	}
+
+	AuditRepo interface {
+		GetAuditLog(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditRecord, error)
+		CreateAuditRecord(ctx context.Context, record entity.AuditRecord) error
 	}
 
 	References interface {
@@ -21,80 +75,354 @@ type (
 
 	Genres interface {
 		GetGenres(ctx context.Context) ([]entity.Genre, error)
-		CreateGenre(ctx context.Context, name string) error
+		CreateGenre(ctx context.Context, name string) (uint64, error)
 		DeleteGenre(ctx context.Context, id uint64) error
 	}
 
 	Authors interface {
 		GetAuthors(ctx context.Context) ([]entity.Author, error)
-		CreateAuthor(ctx context.Context, fullName string) error
+		GetAuthorByID(ctx context.Context, id uint64) (*entity.Author, error)
+		// GetAuthorBySlug also accepts a slug the author had before.
+		GetAuthorBySlug(ctx context.Context, slug string) (*entity.Author, error)
+		CreateAuthor(ctx context.Context, fullName string) (uint64, error)
 		DeleteAuthor(ctx context.Context, id uint64) error
 	}
 
 	Dimensions interface {
 		GetDimensions(ctx context.Context) ([]entity.Dimension, error)
-		CreateDimension(ctx context.Context, width, height int) error
+		CreateDimension(ctx context.Context, width, height int) (uint64, error)
 		DeleteDimension(ctx context.Context, id uint64) error
 	}
 
 	WorkTechniques interface {
 		GetWorkTechniques(ctx context.Context) ([]entity.WorkTechnique, error)
-		CreateWorkTechnique(ctx context.Context, name string) error
+		CreateWorkTechnique(ctx context.Context, name string) (uint64, error)
 		DeleteWorkTechnique(ctx context.Context, id uint64) error
 	}
 
 	ReferencesRepo interface {
 		GetGenres(ctx context.Context) ([]entity.Genre, error)
-		CreateGenre(ctx context.Context, name string) error
+		CreateGenre(ctx context.Context, name string) (uint64, error)
 		DeleteGenre(ctx context.Context, id uint64) error
 
 		GetAuthors(ctx context.Context) ([]entity.Author, error)
-		CreateAuthor(ctx context.Context, fullName string) error
+		GetAuthorByID(ctx context.Context, id uint64) (*entity.Author, error)
+		GetAuthorBySlug(ctx context.Context, slug string) (*entity.Author, error)
+		CreateAuthor(ctx context.Context, fullName string) (uint64, error)
 		DeleteAuthor(ctx context.Context, id uint64) error
 
 		GetDimensions(ctx context.Context) ([]entity.Dimension, error)
-		CreateDimension(ctx context.Context, width, height int) error
+		CreateDimension(ctx context.Context, width, height int) (uint64, error)
 		DeleteDimension(ctx context.Context, id uint64) error
 
 		GetWorkTechniques(ctx context.Context) ([]entity.WorkTechnique, error)
-		CreateWorkTechnique(ctx context.Context, name string) error
+		CreateWorkTechnique(ctx context.Context, name string) (uint64, error)
 		DeleteWorkTechnique(ctx context.Context, id uint64) error
 	}
 
 	Pictures interface {
-		GetPictures(ctx context.Context) ([]entity.Picture, error)
-		GetPictureByID(ctx context.Context, id uint64) (*entity.Picture, error)
-		CreatePicture(ctx context.Context, req entity.PictureCreateRequest) error
+		GetPictures(ctx context.Context, filter entity.PictureFilter) ([]entity.Picture, error)
+		// GetPicturesPage returns a page of pictures in filter.Sort order, latest first by
+		// default, and the cursor of the next page, empty on the last one.
+		GetPicturesPage(ctx context.Context, filter entity.PictureFilter, page entity.PageQuery) ([]entity.Picture, string, error)
+		// GetPictureFacets counts pictures matching the filter for each value of the facets.
+		GetPictureFacets(ctx context.Context, filter entity.PictureFilter, facets []entity.PictureFacet) (*entity.PictureFacetCounts, error)
+		GetPictureByID(ctx context.Context, id uint64, query entity.PriceQuery) (*entity.Picture, error)
+		// GetPictureBySlug also accepts a slug the picture had before; compare
+		// the result's Slug to tell whether to redirect.
+		GetPictureBySlug(ctx context.Context, slug string, query entity.PriceQuery) (*entity.Picture, error)
+		CreatePicture(ctx context.Context, req entity.PictureCreateRequest) (uint64, error)
 		UpdatePicture(ctx context.Context, id uint64, req entity.PictureUpdateRequest) error
 		DeletePicture(ctx context.Context, id uint64) error
+		ChangePictureStatus(ctx context.Context, id uint64, req entity.PictureStatusRequest) error
+		// ReservePicture switches an available picture to reserved in one step,
+		// returning entity.ErrPictureNotAvailable if it isn't available.
+		ReservePicture(ctx context.Context, id uint64) error
+		GetPictureStatusHistory(ctx context.Context, id uint64) ([]entity.PictureStatusTransition, error)
+		GetPriceHistory(ctx context.Context, id uint64) ([]entity.PriceChange, error)
 		UploadPhoto(ctx context.Context, fileHeader *multipart.FileHeader, req entity.PhotoUploadRequest) (*entity.PhotoUploadResponse, error)
 		DeletePhoto(ctx context.Context, pictureID, photoID uint64) (*entity.PhotoDeleteResponse, error)
 	}
 
 	PicturesRepo interface {
-		GetPictures(ctx context.Context) ([]entity.Picture, error)
+		GetPictures(ctx context.Context, filter entity.PictureFilter) ([]entity.Picture, error)
+		// GetPicturesPage returns up to limit pictures following after in filter.Sort
+		// order, which must be set, and the key of the last one if more follow.
+		GetPicturesPage(
+			ctx context.Context,
+			filter entity.PictureFilter,
+			after *entity.PictureKey,
+			limit int,
+		) ([]entity.Picture, *entity.PictureKey, error)
+		// GetPictureFacets expects the filter price in the base currency and splits
+		// prices into ranges at priceBounds, which are in the base currency too.
+		GetPictureFacets(
+			ctx context.Context,
+			filter entity.PictureFilter,
+			facets []entity.PictureFacet,
+			priceBounds []int,
+		) (map[entity.PictureFacet][]entity.FacetValue, error)
 		GetPictureByID(ctx context.Context, id uint64) (*entity.Picture, error)
-		CreatePicture(ctx context.Context, req entity.PictureCreateRequest) error
-		UpdatePicture(ctx context.Context, id uint64, req entity.PictureUpdateRequest) error
-		DeletePicture(ctx context.Context, id uint64) error
+		GetPictureBySlug(ctx context.Context, slug string) (*entity.Picture, error)
+		// The write methods queue the given webhook events in the same transaction as the change.
+		CreatePicture(ctx context.Context, req entity.PictureCreateRequest, events ...entity.WebhookEvent) (uint64, error)
+		UpdatePicture(ctx context.Context, id uint64, req entity.PictureUpdateRequest, events ...entity.WebhookEvent) error
+		DeletePicture(ctx context.Context, id uint64, events ...entity.WebhookEvent) error
+		// UpdatePictureStatus switches the status only if it still equals from,
+		// returning entity.ErrPictureStatusConflict otherwise.
+		UpdatePictureStatus(
+			ctx context.Context,
+			id uint64,
+			from, to entity.PictureStatus,
+			override bool,
+			events ...entity.WebhookEvent,
+		) error
+		GetPictureStatusHistory(ctx context.Context, id uint64) ([]entity.PictureStatusTransition, error)
+		GetPriceHistory(ctx context.Context, id uint64) ([]entity.PriceChange, error)
 		SavePhoto(ctx context.Context, pictureID uint64, url, mime string, isMain bool) (uint64, error)
 		DeletePhoto(ctx context.Context, photoID uint64) error
 		GetPhoto(ctx context.Context, photoID uint64) (*entity.Photo, error)
 	}
 
+	Pricing interface {
+		NormalizeCurrency(currency string) (string, error)
+		// ConvertPrice converts amount between currencies, empty ones mean the base currency.
+		ConvertPrice(amount int, from, to string) (int, error)
+		// Rates returns the price of one unit of each known currency in the base currency.
+		Rates() map[string]float64
+		// ApplyPrices sets effective prices of pictures and converts them into the requested currency.
+		ApplyPrices(ctx context.Context, pictures []entity.Picture, query entity.PriceQuery) error
+	}
+
+	Discounts interface {
+		GetDiscounts(ctx context.Context) ([]entity.DiscountRule, error)
+		CreateDiscount(ctx context.Context, req entity.DiscountCreateRequest) (uint64, error)
+		DeleteDiscount(ctx context.Context, id uint64) error
+	}
+
+	DiscountsRepo interface {
+		GetDiscounts(ctx context.Context) ([]entity.DiscountRule, error)
+		GetActiveDiscounts(ctx context.Context, now time.Time) ([]entity.DiscountRule, error)
+		CreateDiscount(ctx context.Context, discount entity.DiscountRule) (uint64, error)
+		DeleteDiscount(ctx context.Context, id uint64) error
+	}
+
+	Customers interface {
+		Register(ctx context.Context, req entity.CustomerRegisterRequest) (uint64, error)
+		VerifyEmail(ctx context.Context, token string) error
+		ResendVerification(ctx context.Context, email string) error
+		// Login starts a session and moves the anonymous wishlist of visitorID, if any, to the customer.
+		Login(ctx context.Context, req entity.CustomerLoginRequest, visitorID string) (*entity.CustomerLoginResponse, error)
+		Logout(ctx context.Context, token string) error
+		Authenticate(ctx context.Context, token string) (*entity.Customer, error)
+		RequestPasswordReset(ctx context.Context, email string) error
+		ResetPassword(ctx context.Context, req entity.PasswordResetConfirmRequest) error
+	}
+
+	CustomersRepo interface {
+		CreateCustomer(ctx context.Context, customer entity.Customer, passwordHash string) (uint64, error)
+		GetCustomerByID(ctx context.Context, id uint64) (*entity.Customer, error)
+		// GetCustomerByEmail returns the customer together with the password hash.
+		GetCustomerByEmail(ctx context.Context, email string) (*entity.Customer, string, error)
+		VerifyCustomer(ctx context.Context, id uint64) error
+		SetCustomerPassword(ctx context.Context, id uint64, passwordHash string) error
+		CreateCustomerToken(ctx context.Context, customerID uint64, purpose entity.CustomerTokenPurpose, tokenHash string, expiresAt time.Time) error
+		// UseCustomerToken deletes an unexpired token and returns its customer, entity.ErrInvalidCustomerToken otherwise.
+		UseCustomerToken(ctx context.Context, purpose entity.CustomerTokenPurpose, tokenHash string) (uint64, error)
+		CreateCustomerSession(ctx context.Context, customerID uint64, tokenHash string, expiresAt time.Time) error
+		GetCustomerBySession(ctx context.Context, tokenHash string) (*entity.Customer, error)
+		DeleteCustomerSession(ctx context.Context, tokenHash string) error
+		DeleteCustomerSessions(ctx context.Context, customerID uint64) error
+	}
+
+	Mailer interface {
+		Send(ctx context.Context, email entity.Email) error
+	}
+
+	Wishlists interface {
+		NewVisitorID() (string, error)
+		GetWishlist(ctx context.Context, owner entity.WishlistOwner, query entity.PriceQuery) ([]entity.Picture, error)
+		AddToWishlist(ctx context.Context, owner entity.WishlistOwner, pictureID uint64) error
+		RemoveFromWishlist(ctx context.Context, owner entity.WishlistOwner, pictureID uint64) error
+		GetFavourites(ctx context.Context, filter entity.FavouritesFilter) ([]entity.FavouritePicture, error)
+		// MergeWishlist moves the anonymous visitor's items to the customer.
+		MergeWishlist(ctx context.Context, visitorID string, customerID uint64) error
+	}
+
+	WishlistsRepo interface {
+		GetWishlistPictureIDs(ctx context.Context, owner entity.WishlistOwner) ([]uint64, error)
+		AddWishlistItem(ctx context.Context, owner entity.WishlistOwner, pictureID uint64) error
+		RemoveWishlistItem(ctx context.Context, owner entity.WishlistOwner, pictureID uint64) error
+		GetFavourites(ctx context.Context, filter entity.FavouritesFilter) ([]entity.FavouritePicture, error)
+		MergeWishlist(ctx context.Context, visitorID string, customerID uint64) error
+	}
+
+	Search interface {
+		Search(ctx context.Context, query entity.SearchQuery) ([]entity.SearchHit, error)
+		Suggest(ctx context.Context, query entity.SearchQuery) ([]entity.SearchSuggestion, error)
+	}
+
+	SearchRepo interface {
+		Search(ctx context.Context, q string, limit int, now time.Time) ([]entity.SearchHit, error)
+		Suggest(ctx context.Context, q string, limit int) ([]entity.SearchSuggestion, error)
+	}
+
 	News interface {
+		// GetNews, GetNewsByID and GetNewsBySlug return only news visible to the public.
 		GetNews(ctx context.Context) ([]entity.News, error)
+		// GetNewsPage returns a page of public news and the cursor of the next page, empty on the last one.
+		GetNewsPage(ctx context.Context, page entity.PageQuery) ([]entity.News, string, error)
 		GetNewsByID(ctx context.Context, id uint64) (*entity.News, error)
-		CreateNews(ctx context.Context, req entity.NewsCreateRequest) error
+		GetNewsBySlug(ctx context.Context, slug string) (*entity.News, error)
+		GetAdminNews(ctx context.Context, filter entity.NewsFilter) ([]entity.News, error)
+		PreviewNews(ctx context.Context, id uint64) (*entity.News, error)
+		CreateNews(ctx context.Context, req entity.NewsCreateRequest) (uint64, error)
 		UpdateNews(ctx context.Context, id uint64, req entity.NewsUpdateRequest) error
 		DeleteNews(ctx context.Context, id uint64) error
+		UploadNewsImage(ctx context.Context, fileHeader *multipart.FileHeader) (*entity.NewsImageResponse, error)
+		UploadNewsCover(ctx context.Context, id uint64, fileHeader *multipart.FileHeader) (string, error)
+		SetNewsLinks(ctx context.Context, id uint64, req entity.NewsLinksRequest) error
+		// GetPictureNews lists published news mentioning the picture.
+		GetPictureNews(ctx context.Context, pictureID uint64) ([]entity.NewsMention, error)
+		PublishScheduledNews(ctx context.Context) ([]uint64, error)
 	}
 
 	NewsRepo interface {
-		GetNews(ctx context.Context) ([]entity.News, error)
+		GetNews(ctx context.Context, filter entity.NewsFilter) ([]entity.News, error)
+		GetPublicNews(ctx context.Context, filter entity.NewsFilter, now time.Time) ([]entity.News, error)
+		// GetPublicNewsPage returns up to limit public news following after and the key of the last one if more follow.
+		GetPublicNewsPage(
+			ctx context.Context,
+			filter entity.NewsFilter,
+			now time.Time,
+			after *entity.NewsKey,
+			limit int,
+		) ([]entity.News, *entity.NewsKey, error)
 		GetNewsByID(ctx context.Context, id uint64) (*entity.News, error)
-		CreateNews(ctx context.Context, req entity.NewsCreateRequest) error
+		GetNewsBySlug(ctx context.Context, slug string) (*entity.News, error)
+		CreateNews(ctx context.Context, req entity.NewsCreateRequest) (uint64, error)
 		UpdateNews(ctx context.Context, id uint64, req entity.NewsUpdateRequest) error
-		DeleteNews(ctx context.Context, id uint64) error
+		DeleteNews(ctx context.Context, id uint64) (string, error)
+		SetNewsLinks(ctx context.Context, id uint64, pictureIDs, authorIDs []uint64) error
+		SetNewsCover(ctx context.Context, id uint64, url string) (string, error)
+		PublishScheduledNews(ctx context.Context, now time.Time) ([]uint64, error)
+	}
+
+	Inquiries interface {
+		CreateInquiry(ctx context.Context, pictureID uint64, ip string, req entity.InquiryCreateRequest) (uint64, error)
+		GetInquiries(ctx context.Context, filter entity.InquiryFilter) ([]entity.Inquiry, error)
+		GetInquiryByID(ctx context.Context, id uint64) (*entity.Inquiry, error)
+		AssignInquiry(ctx context.Context, id uint64, req entity.InquiryAssignRequest) error
+		ChangeInquiryStatus(ctx context.Context, id uint64, req entity.InquiryStatusRequest) error
+	}
+
+	InquiriesRepo interface {
+		CreateInquiry(ctx context.Context, inquiry entity.Inquiry) (uint64, error)
+		GetInquiries(ctx context.Context, filter entity.InquiryFilter) ([]entity.Inquiry, error)
+		GetInquiryByID(ctx context.Context, id uint64) (*entity.Inquiry, error)
+		AssignInquiry(ctx context.Context, id uint64, assignee string) error
+		UpdateInquiryStatus(ctx context.Context, id uint64, status entity.InquiryStatus) error
+	}
+
+	Exhibitions interface {
+		GetExhibitions(ctx context.Context, filter entity.ExhibitionFilter) ([]entity.Exhibition, error)
+		GetExhibitionByID(ctx context.Context, id uint64, query entity.PriceQuery) (*entity.Exhibition, error)
+		CreateExhibition(ctx context.Context, req entity.ExhibitionCreateRequest) (uint64, error)
+		UpdateExhibition(ctx context.Context, id uint64, req entity.ExhibitionUpdateRequest) error
+		SetExhibitionPictures(ctx context.Context, id uint64, req entity.ExhibitionPicturesRequest) error
+		// UploadCover replaces the cover photo and returns its URL.
+		UploadCover(ctx context.Context, id uint64, fileHeader *multipart.FileHeader) (string, error)
+		DeleteExhibition(ctx context.Context, id uint64) error
+	}
+
+	ExhibitionsRepo interface {
+		GetExhibitions(ctx context.Context, filter entity.ExhibitionFilter, now time.Time) ([]entity.Exhibition, error)
+		GetExhibitionByID(ctx context.Context, id uint64) (*entity.Exhibition, error)
+		CreateExhibition(ctx context.Context, exhibition entity.Exhibition) (uint64, error)
+		UpdateExhibition(ctx context.Context, id uint64, req entity.ExhibitionUpdateRequest) error
+		SetExhibitionPictures(ctx context.Context, id uint64, pictureIDs []uint64) error
+		SetExhibitionCover(ctx context.Context, id uint64, url string) (string, error)
+		DeleteExhibition(ctx context.Context, id uint64) (string, error)
+	}
+
+	Webhooks interface {
+		GetSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error)
+		CreateSubscription(ctx context.Context, req entity.WebhookSubscriptionCreateRequest) (uint64, error)
+		UpdateSubscription(ctx context.Context, id uint64, req entity.WebhookSubscriptionUpdateRequest) error
+		DeleteSubscription(ctx context.Context, id uint64) error
+		GetDeliveries(ctx context.Context, filter entity.WebhookDeliveryFilter) ([]entity.WebhookDelivery, error)
+		// RetryDelivery puts a failed delivery back into the queue.
+		RetryDelivery(ctx context.Context, id uint64) error
+		// DispatchWebhooks turns new outbox events into deliveries and sends the ones that are due.
+		DispatchWebhooks(ctx context.Context) error
+	}
+
+	WebhooksRepo interface {
+		GetSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error)
+		CreateSubscription(ctx context.Context, subscription entity.WebhookSubscription) (uint64, error)
+		UpdateSubscription(ctx context.Context, id uint64, req entity.WebhookSubscriptionUpdateRequest) error
+		DeleteSubscription(ctx context.Context, id uint64) error
+		GetDeliveries(ctx context.Context, filter entity.WebhookDeliveryFilter) ([]entity.WebhookDelivery, error)
+		// FanOutOutbox creates deliveries for up to limit unprocessed outbox events and
+		// marks them processed, returning how many events were taken.
+		FanOutOutbox(ctx context.Context, limit int) (int, error)
+		// ClaimDueDeliveries takes up to limit pending deliveries due by now and postpones
+		// them by lease, so a crashed or concurrent dispatcher doesn't send them twice in a row.
+		ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entity.WebhookDispatch, error)
+		// RecordDeliveryAttempt stores the result; a failed attempt without nextAttemptAt fails the delivery.
+		RecordDeliveryAttempt(ctx context.Context, id uint64, result entity.WebhookResult, nextAttemptAt *time.Time) error
+		RetryDelivery(ctx context.Context, id uint64) error
+	}
+
+	WebhookSender interface {
+		Send(ctx context.Context, dispatch entity.WebhookDispatch) entity.WebhookResult
+	}
+
+	Reservations interface {
+		CreateReservation(ctx context.Context, pictureID uint64, req entity.ReservationCreateRequest) (uint64, error)
+		GetReservations(ctx context.Context, pictureID uint64) ([]entity.Reservation, error)
+		ReleaseExpiredReservations(ctx context.Context) ([]entity.Reservation, error)
+	}
+
+	ReservationsRepo interface {
+		CreateReservation(ctx context.Context, reservation entity.Reservation) (uint64, error)
+		GetReservations(ctx context.Context, pictureID uint64) ([]entity.Reservation, error)
+		GetExpiredReservations(ctx context.Context, now time.Time) ([]entity.Reservation, error)
+		ReleaseReservation(ctx context.Context, id uint64) error
+		CountActiveReservations(ctx context.Context, pictureID uint64) (int, error)
+	}
+
+	Orders interface {
+		CreateOrder(ctx context.Context, pictureID uint64, req entity.OrderCreateRequest) (*entity.OrderCreateResponse, error)
+		GetOrders(ctx context.Context, filter entity.OrderFilter) ([]entity.Order, error)
+		GetOrderByID(ctx context.Context, id uint64) (*entity.Order, error)
+		CancelOrder(ctx context.Context, id uint64) error
+		CancelExpiredOrders(ctx context.Context) ([]entity.Order, error)
+		HandlePaymentWebhook(ctx context.Context, body []byte, signature string) error
+	}
+
+	OrdersRepo interface {
+		CreateOrder(ctx context.Context, order entity.Order) (uint64, error)
+		SetOrderPayment(ctx context.Context, id uint64, payment entity.Payment) error
+		GetOrders(ctx context.Context, filter entity.OrderFilter) ([]entity.Order, error)
+		GetOrderByID(ctx context.Context, id uint64) (*entity.Order, error)
+		GetOrderByPaymentID(ctx context.Context, paymentID string) (*entity.Order, error)
+		GetExpiredOrders(ctx context.Context, createdBefore time.Time) ([]entity.Order, error)
+		CountPendingOrders(ctx context.Context, pictureID uint64) (int, error)
+		// FinishOrder moves a pending order to status, returning entity.ErrOrderNotPending otherwise.
+		FinishOrder(ctx context.Context, id uint64, status entity.OrderStatus) error
+		// MarkOrderPaidLate moves a cancelled or failed order to paid_late and reports whether it did.
+		MarkOrderPaidLate(ctx context.Context, id uint64) (bool, error)
+	}
+
+	PaymentProvider interface {
+		CreatePayment(ctx context.Context, req entity.PaymentRequest) (*entity.Payment, error)
+		// ParseWebhook verifies the signature of a provider notification and decodes it.
+		ParseWebhook(body []byte, signature string) (*entity.PaymentEvent, error)
+	}
+
+	// PaymentSimulator is implemented by test gateways that can produce signed notifications themselves.
+	PaymentSimulator interface {
+		SimulatePayment(paymentID string, status entity.PaymentEventStatus) ([]byte, string, error)
 	}
 )
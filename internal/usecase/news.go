@@ -3,53 +3,332 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/cursor"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/markdown"
 )
 
 type NewsUseCase struct {
-	repo NewsRepo
+	repo     NewsRepo
+	markdown *markdown.Renderer
+	pictures Pictures
+	authors  Authors
+	cursors  *cursor.Codec
 }
 
 var _ News = (*NewsUseCase)(nil)
 
-func NewNewsUseCase(repo NewsRepo) *NewsUseCase {
-	return &NewsUseCase{repo: repo}
+func NewNewsUseCase(repo NewsRepo, renderer *markdown.Renderer, pictures Pictures, authors Authors, cursors *cursor.Codec) *NewsUseCase {
+	return &NewsUseCase{repo: repo, markdown: renderer, pictures: pictures, authors: authors, cursors: cursors}
 }
 
 func (uc *NewsUseCase) GetNews(ctx context.Context) ([]entity.News, error) {
-	news, err := uc.repo.GetNews(ctx)
+	news, err := uc.repo.GetPublicNews(ctx, entity.NewsFilter{}, time.Now())
 	if err != nil {
 		return nil, fmt.Errorf("can't get news: %w", err)
 	}
-	return news, nil
+	return uc.renderAll(news)
+}
+
+func (uc *NewsUseCase) GetNewsPage(ctx context.Context, page entity.PageQuery) ([]entity.News, string, error) {
+	var after *entity.NewsKey
+	if page.Cursor != "" {
+		after = &entity.NewsKey{}
+		if err := decodeCursor(uc.cursors, page.Cursor, after); err != nil {
+			return nil, "", err
+		}
+	}
+
+	news, next, err := uc.repo.GetPublicNewsPage(ctx, entity.NewsFilter{}, time.Now(), after, pageLimit(page.Limit))
+	if err != nil {
+		return nil, "", fmt.Errorf("can't get news page: %w", err)
+	}
+
+	if news, err = uc.renderAll(news); err != nil {
+		return nil, "", err
+	}
+
+	if next == nil {
+		return news, "", nil
+	}
+	token, err := uc.cursors.Encode(next)
+	if err != nil {
+		return nil, "", err
+	}
+	return news, token, nil
 }
 
+// GetNewsByID hides drafts, archived and not yet published news as if they didn't exist.
 func (uc *NewsUseCase) GetNewsByID(ctx context.Context, id uint64) (*entity.News, error) {
+	news, err := uc.PreviewNews(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !news.Public(time.Now()) {
+		return nil, fmt.Errorf("can't get news by id: %w", entity.ErrNewsNotFound)
+	}
+	return news, nil
+}
+
+// GetNewsBySlug also finds news by a slug it had before, see entity.News.Slug.
+func (uc *NewsUseCase) GetNewsBySlug(ctx context.Context, slug string) (*entity.News, error) {
+	news, err := uc.repo.GetNewsBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("can't get news by slug: %w", err)
+	}
+	if !news.Public(time.Now()) {
+		return nil, fmt.Errorf("can't get news by slug: %w", entity.ErrNewsNotFound)
+	}
+
+	if err := uc.render(news); err != nil {
+		return nil, err
+	}
+	if err := uc.embedLinks(ctx, news); err != nil {
+		return nil, err
+	}
+	return news, nil
+}
+
+func (uc *NewsUseCase) GetAdminNews(ctx context.Context, filter entity.NewsFilter) ([]entity.News, error) {
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, entity.ErrUnknownNewsStatus
+	}
+
+	news, err := uc.repo.GetNews(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("can't get news: %w", err)
+	}
+	return uc.renderAll(news)
+}
+
+// PreviewNews returns news in any status, so admins can see drafts as visitors will.
+// Linked pictures and authors are embedded.
+func (uc *NewsUseCase) PreviewNews(ctx context.Context, id uint64) (*entity.News, error) {
 	news, err := uc.repo.GetNewsByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("can't get news by id: %w", err)
 	}
+
+	if err := uc.render(news); err != nil {
+		return nil, err
+	}
+	if err := uc.embedLinks(ctx, news); err != nil {
+		return nil, err
+	}
 	return news, nil
 }
 
-func (uc *NewsUseCase) CreateNews(ctx context.Context, req entity.NewsCreateRequest) error {
-	if err := uc.repo.CreateNews(ctx, req); err != nil {
-		return fmt.Errorf("can't create news: %w", err)
+func (uc *NewsUseCase) GetPictureNews(ctx context.Context, pictureID uint64) ([]entity.NewsMention, error) {
+	news, err := uc.repo.GetPublicNews(ctx, entity.NewsFilter{PictureID: pictureID}, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("can't get picture news: %w", err)
 	}
+
+	mentions := make([]entity.NewsMention, 0, len(news))
+	for _, n := range news {
+		mentions = append(mentions, entity.NewsMention{ID: n.ID, Slug: n.Slug, Title: n.Title, CoverURL: n.CoverURL, PublishAt: n.PublishAt})
+	}
+	return mentions, nil
+}
+
+// embedLinks loads linked pictures in their order and linked authors.
+func (uc *NewsUseCase) embedLinks(ctx context.Context, news *entity.News) error {
+	news.Pictures = []entity.Picture{}
+	if len(news.PictureIDs) > 0 {
+		pictures, err := uc.pictures.GetPictures(ctx, entity.PictureFilter{IDs: news.PictureIDs})
+		if err != nil {
+			return fmt.Errorf("can't get news pictures: %w", err)
+		}
+
+		byID := make(map[uint64]entity.Picture, len(pictures))
+		for _, picture := range pictures {
+			byID[picture.ID] = picture
+		}
+		for _, pictureID := range news.PictureIDs {
+			if picture, ok := byID[pictureID]; ok {
+				news.Pictures = append(news.Pictures, picture)
+			}
+		}
+	}
+
+	news.Authors = []entity.Author{}
+	if len(news.AuthorIDs) > 0 {
+		authors, err := uc.authors.GetAuthors(ctx)
+		if err != nil {
+			return fmt.Errorf("can't get news authors: %w", err)
+		}
+
+		linked := make(map[uint64]bool, len(news.AuthorIDs))
+		for _, authorID := range news.AuthorIDs {
+			linked[authorID] = true
+		}
+		for _, author := range authors {
+			if linked[author.ID] {
+				news.Authors = append(news.Authors, author)
+			}
+		}
+	}
+
 	return nil
 }
 
+func (uc *NewsUseCase) CreateNews(ctx context.Context, req entity.NewsCreateRequest) (uint64, error) {
+	if req.Status == "" {
+		req.Status = entity.NewsStatusDraft
+	}
+
+	publishAt, err := publication(req.Status, req.PublishAt)
+	if err != nil {
+		return 0, err
+	}
+	req.PublishAt = publishAt
+	req.PictureIDs = uniqueIDs(req.PictureIDs)
+	req.AuthorIDs = uniqueIDs(req.AuthorIDs)
+
+	id, err := uc.repo.CreateNews(ctx, req)
+	if err != nil {
+		return 0, fmt.Errorf("can't create news: %w", err)
+	}
+	return id, nil
+}
+
 func (uc *NewsUseCase) UpdateNews(ctx context.Context, id uint64, req entity.NewsUpdateRequest) error {
+	if req.Status != nil || req.PublishAt != nil {
+		current, err := uc.repo.GetNewsByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("can't get news by id: %w", err)
+		}
+
+		status, publishAt := current.Status, current.PublishAt
+		if req.Status != nil {
+			status = *req.Status
+		}
+		if req.PublishAt != nil {
+			publishAt = req.PublishAt
+		} else if status == entity.NewsStatusPublished && publishAt != nil && publishAt.After(time.Now()) {
+			// "publish now" on scheduled news: the stored future time would keep it hidden
+			publishAt = nil
+		}
+
+		publishAt, err = publication(status, publishAt)
+		if err != nil {
+			return err
+		}
+		req.PublishAt = publishAt
+	}
+
 	if err := uc.repo.UpdateNews(ctx, id, req); err != nil {
 		return fmt.Errorf("can't update news: %w", err)
 	}
 	return nil
 }
 
+func (uc *NewsUseCase) SetNewsLinks(ctx context.Context, id uint64, req entity.NewsLinksRequest) error {
+	if err := uc.repo.SetNewsLinks(ctx, id, uniqueIDs(req.PictureIDs), uniqueIDs(req.AuthorIDs)); err != nil {
+		return fmt.Errorf("can't set news links: %w", err)
+	}
+	return nil
+}
+
+func (uc *NewsUseCase) UploadNewsCover(ctx context.Context, id uint64, fileHeader *multipart.FileHeader) (string, error) {
+	url, err := saveUpload(fileHeader)
+	if err != nil {
+		return "", err
+	}
+
+	old, err := uc.repo.SetNewsCover(ctx, id, url)
+	if err != nil {
+		_ = removeUpload(url)
+		return "", fmt.Errorf("can't set news cover: %w", err)
+	}
+
+	if old != "" {
+		if err := removeUpload(old); err != nil {
+			return "", fmt.Errorf("can't delete old cover file: %w", err)
+		}
+	}
+	return url, nil
+}
+
+// PublishScheduledNews marks scheduled news whose time has come as published.
+func (uc *NewsUseCase) PublishScheduledNews(ctx context.Context) ([]uint64, error) {
+	ids, err := uc.repo.PublishScheduledNews(ctx, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("can't publish scheduled news: %w", err)
+	}
+	return ids, nil
+}
+
+// publication checks the status and returns the publication time to store:
+// scheduling needs an explicit time, publishing without one means now.
+func publication(status entity.NewsStatus, publishAt *time.Time) (*time.Time, error) {
+	if !status.Valid() {
+		return nil, entity.ErrUnknownNewsStatus
+	}
+
+	switch status {
+	case entity.NewsStatusScheduled:
+		if publishAt == nil {
+			return nil, entity.ErrNewsPublishAtRequired
+		}
+	case entity.NewsStatusPublished:
+		if publishAt == nil {
+			now := time.Now()
+			publishAt = &now
+		}
+	}
+	return publishAt, nil
+}
+
 func (uc *NewsUseCase) DeleteNews(ctx context.Context, id uint64) error {
-	if err := uc.repo.DeleteNews(ctx, id); err != nil {
+	cover, err := uc.repo.DeleteNews(ctx, id)
+	if err != nil {
 		return fmt.Errorf("can't delete news: %w", err)
 	}
+
+	if cover != "" {
+		if err := removeUpload(cover); err != nil {
+			return fmt.Errorf("can't delete cover file: %w", err)
+		}
+	}
+	return nil
+}
+
+// UploadNewsImage stores an image for use inside news content.
+func (uc *NewsUseCase) UploadNewsImage(ctx context.Context, fileHeader *multipart.FileHeader) (*entity.NewsImageResponse, error) {
+	url, err := saveUpload(fileHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	alt := strings.TrimSuffix(filepath.Base(fileHeader.Filename), filepath.Ext(fileHeader.Filename))
+	alt = strings.NewReplacer("[", "", "]", "").Replace(alt)
+
+	return &entity.NewsImageResponse{
+		URL:      url,
+		Markdown: fmt.Sprintf("![%s](%s)", alt, url),
+	}, nil
+}
+
+func (uc *NewsUseCase) renderAll(news []entity.News) ([]entity.News, error) {
+	for i := range news {
+		if err := uc.render(&news[i]); err != nil {
+			return nil, err
+		}
+	}
+	return news, nil
+}
+
+func (uc *NewsUseCase) render(news *entity.News) error {
+	html, err := uc.markdown.Render(news.Content)
+	if err != nil {
+		return fmt.Errorf("can't render news %d: %w", news.ID, err)
+	}
+	news.ContentHTML = html
 	return nil
 }
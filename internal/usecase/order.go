@@ -0,0 +1,284 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/notify"
+)
+
+const (
+	_defaultOrdersLimit = 50
+	_maxOrdersLimit     = 500
+)
+
+type OrdersUseCase struct {
+	repo       OrdersRepo
+	pictures   Pictures
+	holds      ReservationsRepo
+	payments   PaymentProvider
+	notifier   notify.Notifier
+	pendingTTL time.Duration
+}
+
+var _ Orders = (*OrdersUseCase)(nil)
+
+// NewOrdersUseCase creates the orders usecase. A pending order older than pendingTTL
+// is cancelled by CancelExpiredOrders, so unpaid orders can't hold pictures forever.
+// payments is nil when checkout is disabled: CreateOrder and HandlePaymentWebhook
+// must not be called then, the rest works on existing orders.
+func NewOrdersUseCase(
+	repo OrdersRepo,
+	pictures Pictures,
+	holds ReservationsRepo,
+	payments PaymentProvider,
+	notifier notify.Notifier,
+	pendingTTL time.Duration,
+) *OrdersUseCase {
+	return &OrdersUseCase{repo: repo, pictures: pictures, holds: holds, payments: payments, notifier: notifier, pendingTTL: pendingTTL}
+}
+
+// CreateOrder reserves an available picture for the buyer and starts a payment.
+// The picture becomes sold once the provider confirms the payment.
+func (uc *OrdersUseCase) CreateOrder(
+	ctx context.Context,
+	pictureID uint64,
+	req entity.OrderCreateRequest,
+) (*entity.OrderCreateResponse, error) {
+	picture, err := uc.pictures.GetPictureByID(ctx, pictureID, entity.PriceQuery{PromoCode: req.PromoCode, StrictPromo: true})
+	if err != nil {
+		return nil, fmt.Errorf("can't get picture by id: %w", err)
+	}
+
+	if err := uc.pictures.ReservePicture(ctx, pictureID); err != nil {
+		return nil, fmt.Errorf("can't reserve picture: %w", err)
+	}
+
+	id, err := uc.repo.CreateOrder(ctx, entity.Order{
+		PictureID:     pictureID,
+		CustomerID:    req.CustomerID,
+		CustomerName:  strings.TrimSpace(req.CustomerName),
+		CustomerEmail: strings.TrimSpace(req.CustomerEmail),
+		CustomerPhone: strings.TrimSpace(req.CustomerPhone),
+		Amount:        picture.EffectivePrice,
+		Currency:      picture.Currency,
+		PromoCode:     normalizePromoCode(req.PromoCode),
+		Status:        entity.OrderStatusPending,
+	})
+	if err != nil {
+		uc.releasePicture(ctx, pictureID)
+		return nil, fmt.Errorf("can't create order: %w", err)
+	}
+
+	payment, err := uc.payments.CreatePayment(ctx, entity.PaymentRequest{
+		OrderID:     id,
+		Amount:      picture.EffectivePrice,
+		Currency:    picture.Currency,
+		Description: fmt.Sprintf("Заказ №%d: %s", id, picture.Title),
+	})
+	if err != nil {
+		uc.fail(ctx, id, pictureID)
+		return nil, fmt.Errorf("can't create payment: %w", err)
+	}
+
+	if err := uc.repo.SetOrderPayment(ctx, id, *payment); err != nil {
+		uc.fail(ctx, id, pictureID)
+		return nil, fmt.Errorf("can't save order payment: %w", err)
+	}
+
+	_ = uc.notifier.Notify(ctx, notify.Message{
+		Event: "order.created",
+		Title: fmt.Sprintf("Новый заказ №%d", id),
+		Text: fmt.Sprintf("Картина: %s\nСумма: %d %s\nПокупатель: %s, %s, %s\n\nКартина зарезервирована до оплаты.",
+			picture.Title, picture.EffectivePrice, picture.Currency,
+			strings.TrimSpace(req.CustomerName), strings.TrimSpace(req.CustomerEmail), strings.TrimSpace(req.CustomerPhone)),
+		Data: map[string]any{"order_id": id, "picture_id": pictureID, "amount": picture.EffectivePrice, "currency": picture.Currency},
+	})
+
+	return &entity.OrderCreateResponse{ID: id, PaymentURL: payment.ConfirmationURL}, nil
+}
+
+func (uc *OrdersUseCase) GetOrders(ctx context.Context, filter entity.OrderFilter) ([]entity.Order, error) {
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, entity.ErrUnknownOrderStatus
+	}
+	if filter.Limit == 0 {
+		filter.Limit = _defaultOrdersLimit
+	}
+	if filter.Limit > _maxOrdersLimit {
+		filter.Limit = _maxOrdersLimit
+	}
+
+	orders, err := uc.repo.GetOrders(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("can't get orders: %w", err)
+	}
+	return orders, nil
+}
+
+func (uc *OrdersUseCase) GetOrderByID(ctx context.Context, id uint64) (*entity.Order, error) {
+	order, err := uc.repo.GetOrderByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("can't get order by id: %w", err)
+	}
+	return order, nil
+}
+
+// CancelOrder drops a pending order and puts the picture back on sale.
+func (uc *OrdersUseCase) CancelOrder(ctx context.Context, id uint64) error {
+	order, err := uc.repo.GetOrderByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("can't get order by id: %w", err)
+	}
+
+	if err := uc.repo.FinishOrder(ctx, id, entity.OrderStatusCancelled); err != nil {
+		return fmt.Errorf("can't cancel order: %w", err)
+	}
+
+	uc.releasePicture(ctx, order.PictureID)
+	return nil
+}
+
+// CancelExpiredOrders cancels orders left unpaid longer than the pending TTL,
+// puts their pictures back on sale and returns the cancelled ones.
+func (uc *OrdersUseCase) CancelExpiredOrders(ctx context.Context) ([]entity.Order, error) {
+	expired, err := uc.repo.GetExpiredOrders(ctx, time.Now().Add(-uc.pendingTTL))
+	if err != nil {
+		return nil, fmt.Errorf("can't get expired orders: %w", err)
+	}
+
+	cancelled := make([]entity.Order, 0, len(expired))
+	for _, order := range expired {
+		if err := uc.repo.FinishOrder(ctx, order.ID, entity.OrderStatusCancelled); err != nil {
+			if errors.Is(err, entity.ErrOrderNotPending) {
+				// paid or cancelled meanwhile
+				continue
+			}
+			return cancelled, fmt.Errorf("can't cancel order %d: %w", order.ID, err)
+		}
+
+		uc.releasePicture(ctx, order.PictureID)
+		cancelled = append(cancelled, order)
+	}
+	return cancelled, nil
+}
+
+// HandlePaymentWebhook applies a payment notification. Repeated notifications for
+// finished orders are ignored, so provider retries are harmless. A successful payment
+// for a cancelled or failed order is recorded and returned as entity.ErrOrderPaidLate.
+func (uc *OrdersUseCase) HandlePaymentWebhook(ctx context.Context, body []byte, signature string) error {
+	event, err := uc.payments.ParseWebhook(body, signature)
+	if err != nil {
+		return fmt.Errorf("can't parse payment webhook: %w", err)
+	}
+
+	var status entity.OrderStatus
+	switch event.Status {
+	case entity.PaymentSucceeded:
+		status = entity.OrderStatusPaid
+	case entity.PaymentFailed:
+		status = entity.OrderStatusFailed
+	default:
+		return fmt.Errorf("%w: %s", entity.ErrUnknownPaymentStatus, event.Status)
+	}
+
+	order, err := uc.repo.GetOrderByPaymentID(ctx, event.PaymentID)
+	if err != nil {
+		return fmt.Errorf("can't get order by payment id: %w", err)
+	}
+
+	if err := uc.repo.FinishOrder(ctx, order.ID, status); err != nil {
+		if errors.Is(err, entity.ErrOrderNotPending) {
+			if status == entity.OrderStatusPaid {
+				return uc.latePayment(ctx, order)
+			}
+			return nil
+		}
+		return fmt.Errorf("can't finish order: %w", err)
+	}
+
+	if status == entity.OrderStatusFailed {
+		uc.releasePicture(ctx, order.PictureID)
+		return nil
+	}
+
+	// the money is already taken, so the picture is sold even if it was released meanwhile
+	err = uc.pictures.ChangePictureStatus(ctx, order.PictureID, entity.PictureStatusRequest{
+		Status:   entity.PictureStatusSold,
+		Override: true,
+	})
+	if err != nil {
+		return fmt.Errorf("can't mark picture sold: %w", err)
+	}
+	return nil
+}
+
+// latePayment handles money taken for an order that was cancelled or failed meanwhile.
+// The picture is sold to the buyer unless someone else has bought it, and staff are told
+// which of the two happened, since the second case needs a refund.
+func (uc *OrdersUseCase) latePayment(ctx context.Context, order *entity.Order) error {
+	flagged, err := uc.repo.MarkOrderPaidLate(ctx, order.ID)
+	if err != nil {
+		return fmt.Errorf("can't mark order paid late: %w", err)
+	}
+	if !flagged {
+		// paid already, or flagged by an earlier notification
+		return nil
+	}
+
+	picture, err := uc.pictures.GetPictureByID(ctx, order.PictureID, entity.PriceQuery{})
+	if err != nil {
+		return fmt.Errorf("%w: order %d: can't get picture by id: %w", entity.ErrOrderPaidLate, order.ID, err)
+	}
+
+	action := "Картина отмечена проданной этому покупателю."
+	if picture.Status == entity.PictureStatusSold {
+		action = "Картина уже продана, покупателю нужно вернуть деньги."
+	} else {
+		err := uc.pictures.ChangePictureStatus(ctx, order.PictureID, entity.PictureStatusRequest{
+			Status:   entity.PictureStatusSold,
+			Override: true,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: order %d: can't mark picture sold: %w", entity.ErrOrderPaidLate, order.ID, err)
+		}
+	}
+
+	_ = uc.notifier.Notify(ctx, notify.Message{
+		Event: "order.paid_late",
+		Title: fmt.Sprintf("Оплата отменённого заказа №%d", order.ID),
+		Text: fmt.Sprintf("Картина: %s\nСумма: %d %s\nПокупатель: %s, %s, %s\n\nОплата пришла после отмены заказа. %s",
+			order.PictureTitle, order.Amount, order.Currency,
+			order.CustomerName, order.CustomerEmail, order.CustomerPhone, action),
+		Data: map[string]any{"order_id": order.ID, "picture_id": order.PictureID, "amount": order.Amount, "currency": order.Currency},
+	})
+
+	return fmt.Errorf("%w: order %d", entity.ErrOrderPaidLate, order.ID)
+}
+
+func (uc *OrdersUseCase) fail(ctx context.Context, orderID, pictureID uint64) {
+	_ = uc.repo.FinishOrder(ctx, orderID, entity.OrderStatusFailed)
+	uc.releasePicture(ctx, pictureID)
+}
+
+// releasePicture puts a picture reserved for an unpaid order back on sale.
+// An active admin hold means the reservation no longer belongs to the order,
+// so the picture stays reserved. It's best effort: the admin can always fix
+// the status by hand.
+func (uc *OrdersUseCase) releasePicture(ctx context.Context, pictureID uint64) {
+	active, err := uc.holds.CountActiveReservations(ctx, pictureID)
+	if err != nil || active > 0 {
+		return
+	}
+
+	picture, err := uc.pictures.GetPictureByID(ctx, pictureID, entity.PriceQuery{})
+	if err != nil || picture.Status != entity.PictureStatusReserved {
+		return
+	}
+
+	_ = uc.pictures.ChangePictureStatus(ctx, pictureID, entity.PictureStatusRequest{Status: entity.PictureStatusAvailable})
+}
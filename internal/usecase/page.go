@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/cursor"
+)
+
+const (
+	_defaultPageLimit = 24
+	_maxPageLimit     = 100
+)
+
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return _defaultPageLimit
+	}
+	return min(limit, _maxPageLimit)
+}
+
+// decodeCursor reads the position of a page cursor into key; a cursor that wasn't
+// issued by us or doesn't decode into key is entity.ErrInvalidCursor.
+func decodeCursor(cursors *cursor.Codec, token string, key any) error {
+	if err := cursors.Decode(token, key); err != nil {
+		return entity.ErrInvalidCursor
+	}
+	return nil
+}
@@ -2,104 +2,392 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"io"
 	"mime/multipart"
-	"os"
-	"path/filepath"
-	"strings"
-	"time"
+	"slices"
 
 	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
-	"github.com/google/uuid"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/cursor"
 )
 
+// PicturesUseCase splits prices into the ranges offered as filters at priceBounds,
+// which are in the base currency, and signs page cursors with cursors.
 type PicturesUseCase struct {
-	repo PicturesRepo
+	repo        PicturesRepo
+	pricing     Pricing
+	priceBounds []int
+	cursors     *cursor.Codec
 }
 
 var _ Pictures = (*PicturesUseCase)(nil)
 
-func NewPicturesUseCase(repo PicturesRepo) *PicturesUseCase {
-	return &PicturesUseCase{repo: repo}
+// pictureCursor remembers the sort, so that a cursor can't continue a listing in another order.
+type pictureCursor struct {
+	Sort entity.PictureSort `json:"sort"`
+	Key  entity.PictureKey  `json:"key"`
 }
 
-func (uc *PicturesUseCase) GetPictures(ctx context.Context) ([]entity.Picture, error) {
-	pictures, err := uc.repo.GetPictures(ctx)
+func NewPicturesUseCase(repo PicturesRepo, pricing Pricing, priceBounds []int, cursors *cursor.Codec) *PicturesUseCase {
+	bounds := slices.DeleteFunc(slices.Clone(priceBounds), func(bound int) bool { return bound <= 0 })
+	slices.Sort(bounds)
+
+	return &PicturesUseCase{repo: repo, pricing: pricing, priceBounds: slices.Compact(bounds), cursors: cursors}
+}
+
+func (uc *PicturesUseCase) GetPictures(ctx context.Context, filter entity.PictureFilter) ([]entity.Picture, error) {
+	filter, err := uc.baseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	pictures, err := uc.repo.GetPictures(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("can't get pictures: %w", err)
 	}
+
+	if err := uc.pricing.ApplyPrices(ctx, pictures, filter.PriceQuery); err != nil {
+		return nil, err
+	}
 	return pictures, nil
 }
 
-func (uc *PicturesUseCase) GetPictureByID(ctx context.Context, id uint64) (*entity.Picture, error) {
+func (uc *PicturesUseCase) GetPicturesPage(
+	ctx context.Context,
+	filter entity.PictureFilter,
+	page entity.PageQuery,
+) ([]entity.Picture, string, error) {
+	if filter.Sort == "" {
+		filter.Sort = entity.PictureSortDateDesc
+	}
+
+	filter, err := uc.baseFilter(filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var after *entity.PictureKey
+	if page.Cursor != "" {
+		var position pictureCursor
+		if err := decodeCursor(uc.cursors, page.Cursor, &position); err != nil {
+			return nil, "", err
+		}
+		if position.Sort != filter.Sort {
+			return nil, "", entity.ErrInvalidCursor
+		}
+		after = &position.Key
+	}
+
+	pictures, next, err := uc.repo.GetPicturesPage(ctx, filter, after, pageLimit(page.Limit))
+	if err != nil {
+		return nil, "", fmt.Errorf("can't get pictures page: %w", err)
+	}
+
+	if err := uc.pricing.ApplyPrices(ctx, pictures, filter.PriceQuery); err != nil {
+		return nil, "", err
+	}
+
+	if next == nil {
+		return pictures, "", nil
+	}
+	token, err := uc.cursors.Encode(pictureCursor{Sort: filter.Sort, Key: *next})
+	if err != nil {
+		return nil, "", err
+	}
+	return pictures, token, nil
+}
+
+// GetPictureFacets counts pictures for each value of the facets. Price ranges are
+// converted into the requested currency, so they may come out as uneven numbers.
+func (uc *PicturesUseCase) GetPictureFacets(
+	ctx context.Context,
+	filter entity.PictureFilter,
+	facets []entity.PictureFacet,
+) (*entity.PictureFacetCounts, error) {
+	for _, facet := range facets {
+		if !facet.Valid() {
+			return nil, entity.ErrUnknownPictureFacet
+		}
+	}
+
+	currency, err := uc.pricing.NormalizeCurrency(filter.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err = uc.baseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := uc.repo.GetPictureFacets(ctx, filter, facets, uc.priceBounds)
+	if err != nil {
+		return nil, fmt.Errorf("can't get picture facets: %w", err)
+	}
+
+	for facet, facetValues := range values {
+		for i := range facetValues {
+			value := &facetValues[i]
+			switch facet {
+			case entity.PictureFacetGenre:
+				value.Selected = slices.Contains(filter.GenreIDs, value.ID)
+			case entity.PictureFacetAuthor:
+				value.Selected = slices.Contains(filter.AuthorIDs, value.ID)
+			case entity.PictureFacetTechnique:
+				value.Selected = slices.Contains(filter.TechniqueIDs, value.ID)
+			case entity.PictureFacetDimension:
+				value.Selected = slices.Contains(filter.DimensionIDs, value.ID)
+			case entity.PictureFacetPrice:
+				value.Selected = *value.Price == filter.Price
+				if value.Price, err = uc.convertRange(*value.Price, "", currency); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return &entity.PictureFacetCounts{Currency: currency, Values: values}, nil
+}
+
+// baseFilter checks the filter and converts its price range into the base currency.
+func (uc *PicturesUseCase) baseFilter(filter entity.PictureFilter) (entity.PictureFilter, error) {
+	for _, status := range filter.Statuses {
+		if !status.Valid() {
+			return filter, entity.ErrUnknownPictureStatus
+		}
+	}
+
+	if filter.Sort != "" && !filter.Sort.Valid() {
+		return filter, entity.ErrUnknownPictureSort
+	}
+
+	if !filter.Price.Valid() {
+		return filter, entity.ErrInvalidPriceRange
+	}
+
+	currency, err := uc.pricing.NormalizeCurrency(filter.Currency)
+	if err != nil {
+		return filter, err
+	}
+
+	price, err := uc.convertRange(filter.Price, currency, "")
+	if err != nil {
+		return filter, err
+	}
+	filter.Price = *price
+	filter.Rates = uc.pricing.Rates()
+	return filter, nil
+}
+
+func (uc *PicturesUseCase) convertRange(r entity.PriceRange, from, to string) (*entity.PriceRange, error) {
+	lower, err := uc.pricing.ConvertPrice(r.Min, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	upper := 0
+	if r.Max > 0 {
+		if upper, err = uc.pricing.ConvertPrice(r.Max, from, to); err != nil {
+			return nil, err
+		}
+	}
+	return &entity.PriceRange{Min: lower, Max: upper}, nil
+}
+
+func (uc *PicturesUseCase) GetPictureByID(ctx context.Context, id uint64, query entity.PriceQuery) (*entity.Picture, error) {
+	if _, err := uc.pricing.NormalizeCurrency(query.Currency); err != nil {
+		return nil, err
+	}
+
 	picture, err := uc.repo.GetPictureByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("can't get picture by id: %w", err)
 	}
-	return picture, nil
+
+	return uc.price(ctx, picture, query)
 }
 
-func (uc *PicturesUseCase) CreatePicture(ctx context.Context, req entity.PictureCreateRequest) error {
-	if err := uc.repo.CreatePicture(ctx, req); err != nil {
-		return fmt.Errorf("can't create picture: %w", err)
+func (uc *PicturesUseCase) GetPictureBySlug(ctx context.Context, slug string, query entity.PriceQuery) (*entity.Picture, error) {
+	if _, err := uc.pricing.NormalizeCurrency(query.Currency); err != nil {
+		return nil, err
 	}
-	return nil
+
+	picture, err := uc.repo.GetPictureBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("can't get picture by slug: %w", err)
+	}
+
+	return uc.price(ctx, picture, query)
+}
+
+func (uc *PicturesUseCase) price(ctx context.Context, picture *entity.Picture, query entity.PriceQuery) (*entity.Picture, error) {
+	pictures := []entity.Picture{*picture}
+	if err := uc.pricing.ApplyPrices(ctx, pictures, query); err != nil {
+		return nil, err
+	}
+	return &pictures[0], nil
+}
+
+func (uc *PicturesUseCase) CreatePicture(ctx context.Context, req entity.PictureCreateRequest) (uint64, error) {
+	currency, err := uc.pricing.NormalizeCurrency(req.Currency)
+	if err != nil {
+		return 0, err
+	}
+	req.Currency = currency
+
+	id, err := uc.repo.CreatePicture(ctx, req, entity.WebhookPictureCreated)
+	if err != nil {
+		return 0, fmt.Errorf("can't create picture: %w", err)
+	}
+	return id, nil
 }
 
 func (uc *PicturesUseCase) UpdatePicture(ctx context.Context, id uint64, req entity.PictureUpdateRequest) error {
-	if err := uc.repo.UpdatePicture(ctx, id, req); err != nil {
+	if req.Currency != nil {
+		currency, err := uc.pricing.NormalizeCurrency(*req.Currency)
+		if err != nil {
+			return err
+		}
+		req.Currency = &currency
+	}
+
+	events := []entity.WebhookEvent{entity.WebhookPictureUpdated}
+	if req.Price != nil || req.Currency != nil {
+		picture, err := uc.repo.GetPictureByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("can't get picture by id: %w", err)
+		}
+		if (req.Price != nil && *req.Price != picture.Price) || (req.Currency != nil && *req.Currency != picture.Currency) {
+			events = append(events, entity.WebhookPicturePriced)
+		}
+	}
+
+	if err := uc.repo.UpdatePicture(ctx, id, req, events...); err != nil {
 		return fmt.Errorf("can't update picture: %w", err)
 	}
 	return nil
 }
 
 func (uc *PicturesUseCase) DeletePicture(ctx context.Context, id uint64) error {
-	if err := uc.repo.DeletePicture(ctx, id); err != nil {
+	if err := uc.repo.DeletePicture(ctx, id, entity.WebhookPictureDeleted); err != nil {
 		return fmt.Errorf("can't delete picture: %w", err)
 	}
 	return nil
 }
 
-func (uc *PicturesUseCase) UploadPhoto(
-	ctx context.Context,
-	fileHeader *multipart.FileHeader,
-	req entity.PhotoUploadRequest,
-) (*entity.PhotoUploadResponse, error) {
-	file, err := fileHeader.Open()
+// _pictureStatusTransitions lists the status changes allowed without an override.
+// A sold picture stays sold unless the admin explicitly overrides it.
+var _pictureStatusTransitions = map[entity.PictureStatus][]entity.PictureStatus{
+	entity.PictureStatusAvailable: {
+		entity.PictureStatusReserved,
+		entity.PictureStatusSold,
+		entity.PictureStatusNotForSale,
+	},
+	entity.PictureStatusReserved: {
+		entity.PictureStatusAvailable,
+		entity.PictureStatusSold,
+		entity.PictureStatusNotForSale,
+	},
+	entity.PictureStatusNotForSale: {
+		entity.PictureStatusAvailable,
+	},
+}
+
+func canChangePictureStatus(from, to entity.PictureStatus) bool {
+	for _, allowed := range _pictureStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (uc *PicturesUseCase) ChangePictureStatus(ctx context.Context, id uint64, req entity.PictureStatusRequest) error {
+	if !req.Status.Valid() {
+		return entity.ErrUnknownPictureStatus
+	}
+
+	picture, err := uc.repo.GetPictureByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("can't open uploaded file: %w", err)
+		return fmt.Errorf("can't get picture by id: %w", err)
+	}
+
+	if picture.Status == req.Status {
+		return nil
+	}
+	if !req.Override && !canChangePictureStatus(picture.Status, req.Status) {
+		return fmt.Errorf("%w: %s -> %s", entity.ErrPictureStatusTransition, picture.Status, req.Status)
 	}
-	defer file.Close()
 
-	ext := filepath.Ext(fileHeader.Filename)
-	filename := fmt.Sprintf("%d_%s%s", time.Now().UnixNano(), uuid.New().String(), ext)
-	filePath := filepath.Join("uploads", filename)
+	event := entity.WebhookPictureUpdated
+	if req.Status == entity.PictureStatusSold {
+		event = entity.WebhookPictureSold
+	}
 
-	if err := os.MkdirAll("uploads", 0755); err != nil {
-		return nil, fmt.Errorf("can't create uploads directory: %w", err)
+	if err := uc.repo.UpdatePictureStatus(ctx, id, picture.Status, req.Status, req.Override, event); err != nil {
+		return fmt.Errorf("can't update picture status: %w", err)
 	}
+	return nil
+}
 
-	dst, err := os.Create(filePath)
+// ReservePicture relies on the compare-and-set in the repo rather than a read
+// beforehand, so of two concurrent holds or orders only one gets the picture.
+func (uc *PicturesUseCase) ReservePicture(ctx context.Context, id uint64) error {
+	err := uc.repo.UpdatePictureStatus(ctx, id, entity.PictureStatusAvailable, entity.PictureStatusReserved, false,
+		entity.WebhookPictureUpdated)
 	if err != nil {
-		return nil, fmt.Errorf("can't create file: %w", err)
+		if errors.Is(err, entity.ErrPictureStatusConflict) {
+			return entity.ErrPictureNotAvailable
+		}
+		return fmt.Errorf("can't update picture status: %w", err)
+	}
+	return nil
+}
+
+func (uc *PicturesUseCase) GetPriceHistory(ctx context.Context, id uint64) ([]entity.PriceChange, error) {
+	if _, err := uc.repo.GetPictureByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("can't get picture by id: %w", err)
 	}
-	defer dst.Close()
 
-	if _, err := io.Copy(dst, file); err != nil {
-		return nil, fmt.Errorf("can't save file: %w", err)
+	history, err := uc.repo.GetPriceHistory(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("can't get price history: %w", err)
+	}
+	return history, nil
+}
+
+func (uc *PicturesUseCase) GetPictureStatusHistory(ctx context.Context, id uint64) ([]entity.PictureStatusTransition, error) {
+	if _, err := uc.repo.GetPictureByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("can't get picture by id: %w", err)
+	}
+
+	history, err := uc.repo.GetPictureStatusHistory(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("can't get picture status history: %w", err)
+	}
+	return history, nil
+}
+
+func (uc *PicturesUseCase) UploadPhoto(
+	ctx context.Context,
+	fileHeader *multipart.FileHeader,
+	req entity.PhotoUploadRequest,
+) (*entity.PhotoUploadResponse, error) {
+	url, err := saveUpload(fileHeader)
+	if err != nil {
+		return nil, err
 	}
 
 	mime := fileHeader.Header.Get("Content-Type")
-	photoID, err := uc.repo.SavePhoto(ctx, req.PictureID, "/"+filePath, mime, req.IsMain)
+	photoID, err := uc.repo.SavePhoto(ctx, req.PictureID, url, mime, req.IsMain)
 	if err != nil {
-		os.Remove(filePath)
+		_ = removeUpload(url)
 		return nil, fmt.Errorf("can't save photo info: %w", err)
 	}
 
 	return &entity.PhotoUploadResponse{
 		ID:  photoID,
-		URL: "/" + filePath,
+		URL: url,
 	}, nil
 }
 
@@ -116,7 +404,7 @@ func (uc *PicturesUseCase) DeletePhoto(
 		return nil, fmt.Errorf("can't delete photo from db: %w", err)
 	}
 
-	if err := os.Remove(strings.TrimPrefix(photo.URL, "/")); err != nil && !os.IsNotExist(err) {
+	if err := removeUpload(photo.URL); err != nil {
 		return nil, fmt.Errorf("can't delete photo file: %w", err)
 	}
 
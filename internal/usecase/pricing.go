@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+)
+
+// ExchangeRates converts amounts between currencies. Each rate is the price
+// of one unit of the currency expressed in the base currency.
+type ExchangeRates struct {
+	base  string
+	rates map[string]float64
+}
+
+func NewExchangeRates(base string, rates map[string]float64) *ExchangeRates {
+	base = strings.ToUpper(base)
+
+	normalized := make(map[string]float64, len(rates)+1)
+	for currency, rate := range rates {
+		normalized[strings.ToUpper(currency)] = rate
+	}
+	normalized[base] = 1
+
+	return &ExchangeRates{base: base, rates: normalized}
+}
+
+// Base returns the currency new pictures are priced in by default.
+func (r *ExchangeRates) Base() string {
+	return r.base
+}
+
+// Normalize upper-cases a currency code and checks that it is known, empty means the base currency.
+func (r *ExchangeRates) Normalize(currency string) (string, error) {
+	if currency == "" {
+		return r.base, nil
+	}
+
+	currency = strings.ToUpper(currency)
+	if rate, ok := r.rates[currency]; !ok || rate <= 0 {
+		return "", entity.ErrUnknownCurrency
+	}
+	return currency, nil
+}
+
+// Convert returns amount in currency from expressed in currency to, rounded to whole units.
+func (r *ExchangeRates) Convert(amount int, from, to string) (int, error) {
+	from, err := r.Normalize(from)
+	if err != nil {
+		return 0, err
+	}
+	to, err = r.Normalize(to)
+	if err != nil {
+		return 0, err
+	}
+	if from == to {
+		return amount, nil
+	}
+
+	return int(math.Round(float64(amount) * r.rates[from] / r.rates[to])), nil
+}
+
+// PricingUseCase computes what buyers pay: the best active discount rule
+// matching a picture is applied to its base price, then both prices are
+// converted into the requested currency. Discounts don't stack.
+type PricingUseCase struct {
+	rates     *ExchangeRates
+	discounts DiscountsRepo
+}
+
+var _ Pricing = (*PricingUseCase)(nil)
+
+func NewPricingUseCase(rates *ExchangeRates, discounts DiscountsRepo) *PricingUseCase {
+	return &PricingUseCase{rates: rates, discounts: discounts}
+}
+
+func (uc *PricingUseCase) NormalizeCurrency(currency string) (string, error) {
+	return uc.rates.Normalize(currency)
+}
+
+func (uc *PricingUseCase) ConvertPrice(amount int, from, to string) (int, error) {
+	return uc.rates.Convert(amount, from, to)
+}
+
+func (uc *PricingUseCase) Rates() map[string]float64 {
+	return maps.Clone(uc.rates.rates)
+}
+
+func (uc *PricingUseCase) ApplyPrices(ctx context.Context, pictures []entity.Picture, query entity.PriceQuery) error {
+	currency := ""
+	if query.Currency != "" {
+		var err error
+		if currency, err = uc.rates.Normalize(query.Currency); err != nil {
+			return err
+		}
+	}
+
+	rules, err := uc.discounts.GetActiveDiscounts(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("can't get active discounts: %w", err)
+	}
+
+	code := normalizePromoCode(query.PromoCode)
+	if code != "" && !hasPromoCode(rules, code) {
+		if query.StrictPromo {
+			return entity.ErrPromoCodeNotFound
+		}
+		code = ""
+	}
+
+	for i := range pictures {
+		if err := uc.applyPrice(&pictures[i], rules, code, currency); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (uc *PricingUseCase) applyPrice(picture *entity.Picture, rules []entity.DiscountRule, code, currency string) error {
+	picture.EffectivePrice = picture.Price
+	picture.Discount = nil
+
+	best := 0
+	for _, rule := range rules {
+		if rule.PromoCode != nil && *rule.PromoCode != code {
+			continue
+		}
+		if !discountMatches(rule, picture) {
+			continue
+		}
+
+		amount, err := uc.discountAmount(rule, picture)
+		if err != nil {
+			return err
+		}
+		if amount > best {
+			best = amount
+			picture.Discount = &entity.AppliedDiscount{ID: rule.ID, Name: rule.Name}
+			if rule.PromoCode != nil {
+				picture.Discount.PromoCode = *rule.PromoCode
+			}
+		}
+	}
+	picture.EffectivePrice = max(picture.Price-best, 0)
+
+	if currency == "" {
+		return nil
+	}
+
+	price, err := uc.rates.Convert(picture.Price, picture.Currency, currency)
+	if err != nil {
+		return fmt.Errorf("can't convert price of picture %d: %w", picture.ID, err)
+	}
+	effectivePrice, err := uc.rates.Convert(picture.EffectivePrice, picture.Currency, currency)
+	if err != nil {
+		return fmt.Errorf("can't convert price of picture %d: %w", picture.ID, err)
+	}
+
+	picture.Price, picture.EffectivePrice, picture.Currency = price, effectivePrice, currency
+	return nil
+}
+
+// discountAmount returns how much the rule takes off the picture price, in the picture currency.
+func (uc *PricingUseCase) discountAmount(rule entity.DiscountRule, picture *entity.Picture) (int, error) {
+	if rule.Kind == entity.DiscountKindPercent {
+		return int(math.Round(float64(picture.Price) * float64(rule.Value) / 100)), nil
+	}
+
+	amount, err := uc.rates.Convert(rule.Value, rule.Currency, picture.Currency)
+	if err != nil {
+		return 0, fmt.Errorf("can't convert discount %d: %w", rule.ID, err)
+	}
+	return amount, nil
+}
+
+func discountMatches(rule entity.DiscountRule, picture *entity.Picture) bool {
+	if rule.GenreID != nil && *rule.GenreID != picture.Genre.ID {
+		return false
+	}
+	if rule.AuthorID != nil && *rule.AuthorID != picture.Author.ID {
+		return false
+	}
+	if rule.WorkTechniqueID != nil && *rule.WorkTechniqueID != picture.WorkTechnique.ID {
+		return false
+	}
+	if len(rule.PictureIDs) > 0 && !slices.Contains(rule.PictureIDs, picture.ID) {
+		return false
+	}
+	return true
+}
+
+func hasPromoCode(rules []entity.DiscountRule, code string) bool {
+	for _, rule := range rules {
+		if rule.PromoCode != nil && *rule.PromoCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -25,11 +25,12 @@ func (r *ReferencesUseCase) GetGenres(ctx context.Context) ([]entity.Genre, erro
 	return genres, nil
 }
 
-func (r *ReferencesUseCase) CreateGenre(ctx context.Context, name string) error {
-	if err := r.repo.CreateGenre(ctx, name); err != nil {
-		return fmt.Errorf("can't create genre: %w", err)
+func (r *ReferencesUseCase) CreateGenre(ctx context.Context, name string) (uint64, error) {
+	id, err := r.repo.CreateGenre(ctx, name)
+	if err != nil {
+		return 0, fmt.Errorf("can't create genre: %w", err)
 	}
-	return nil
+	return id, nil
 }
 
 func (r *ReferencesUseCase) DeleteGenre(ctx context.Context, id uint64) error {
@@ -47,11 +48,28 @@ func (r *ReferencesUseCase) GetAuthors(ctx context.Context) ([]entity.Author, er
 	return authors, nil
 }
 
-func (r *ReferencesUseCase) CreateAuthor(ctx context.Context, fullName string) error {
-	if err := r.repo.CreateAuthor(ctx, fullName); err != nil {
-		return fmt.Errorf("can't create author: %w", err)
+func (r *ReferencesUseCase) GetAuthorByID(ctx context.Context, id uint64) (*entity.Author, error) {
+	author, err := r.repo.GetAuthorByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("can't get author by id: %w", err)
 	}
-	return nil
+	return author, nil
+}
+
+func (r *ReferencesUseCase) GetAuthorBySlug(ctx context.Context, slug string) (*entity.Author, error) {
+	author, err := r.repo.GetAuthorBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("can't get author by slug: %w", err)
+	}
+	return author, nil
+}
+
+func (r *ReferencesUseCase) CreateAuthor(ctx context.Context, fullName string) (uint64, error) {
+	id, err := r.repo.CreateAuthor(ctx, fullName)
+	if err != nil {
+		return 0, fmt.Errorf("can't create author: %w", err)
+	}
+	return id, nil
 }
 
 func (r *ReferencesUseCase) DeleteAuthor(ctx context.Context, id uint64) error {
@@ -69,11 +87,12 @@ func (r *ReferencesUseCase) GetDimensions(ctx context.Context) ([]entity.Dimensi
 	return dimensions, nil
 }
 
-func (r *ReferencesUseCase) CreateDimension(ctx context.Context, width, height int) error {
-	if err := r.repo.CreateDimension(ctx, width, height); err != nil {
-		return fmt.Errorf("can't create dimension: %w", err)
+func (r *ReferencesUseCase) CreateDimension(ctx context.Context, width, height int) (uint64, error) {
+	id, err := r.repo.CreateDimension(ctx, width, height)
+	if err != nil {
+		return 0, fmt.Errorf("can't create dimension: %w", err)
 	}
-	return nil
+	return id, nil
 }
 
 func (r *ReferencesUseCase) DeleteDimension(ctx context.Context, id uint64) error {
@@ -91,11 +110,12 @@ func (r *ReferencesUseCase) GetWorkTechniques(ctx context.Context) ([]entity.Wor
 	return techniques, nil
 }
 
-func (r *ReferencesUseCase) CreateWorkTechnique(ctx context.Context, name string) error {
-	if err := r.repo.CreateWorkTechnique(ctx, name); err != nil {
-		return fmt.Errorf("can't create work technique: %w", err)
+func (r *ReferencesUseCase) CreateWorkTechnique(ctx context.Context, name string) (uint64, error) {
+	id, err := r.repo.CreateWorkTechnique(ctx, name)
+	if err != nil {
+		return 0, fmt.Errorf("can't create work technique: %w", err)
 	}
-	return nil
+	return id, nil
 }
 
 func (r *ReferencesUseCase) DeleteWorkTechnique(ctx context.Context, id uint64) error {
@@ -0,0 +1,126 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type APIKeysRepo struct {
+	*postgres.Postgres
+}
+
+func NewAPIKeysRepo(pg *postgres.Postgres) *APIKeysRepo {
+	return &APIKeysRepo{pg}
+}
+
+var _apiKeyColumns = []string{
+	"id", "name", "prefix", "scopes", "expires_at", "last_used_at", "revoked_at", "created_at",
+}
+
+func scanAPIKey(row pgx.Row) (*entity.APIKey, error) {
+	var key entity.APIKey
+	err := row.Scan(
+		&key.ID, &key.Name, &key.Prefix, &key.Scopes,
+		&key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt, &key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *APIKeysRepo) GetAPIKeys(ctx context.Context) ([]entity.APIKey, error) {
+	query, _, err := r.Builder.
+		Select(_apiKeyColumns...).
+		From("api_keys").
+		OrderBy("created_at DESC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can't create sql query: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("can't query request: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make([]entity.APIKey, 0, _defaultListCap)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("can't scan row: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+
+	return keys, nil
+}
+
+func (r *APIKeysRepo) GetAPIKeyByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	query, args, err := r.Builder.
+		Select(_apiKeyColumns...).
+		From("api_keys").
+		Where(squirrel.Eq{"key_hash": keyHash}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can't create sql query: %w", err)
+	}
+
+	key, err := scanAPIKey(r.Pool.QueryRow(ctx, query, args...))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, entity.ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("can't scan row: %w", err)
+	}
+
+	return key, nil
+}
+
+func (r *APIKeysRepo) CreateAPIKey(ctx context.Context, key entity.APIKey, keyHash string) (*entity.APIKey, error) {
+	query, args, err := r.Builder.
+		Insert("api_keys").
+		Columns("name", "prefix", "key_hash", "scopes", "expires_at").
+		Values(key.Name, key.Prefix, keyHash, key.Scopes, key.ExpiresAt).
+		Suffix("RETURNING id, created_at").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can't create sql query: %w", err)
+	}
+
+	if err := r.Pool.QueryRow(ctx, query, args...).Scan(&key.ID, &key.CreatedAt); err != nil {
+		return nil, fmt.Errorf("can't insert api key: %w", err)
+	}
+
+	return &key, nil
+}
+
+func (r *APIKeysRepo) RevokeAPIKey(ctx context.Context, id uint64) error {
+	sql := "UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL"
+
+	tag, err := r.Pool.Exec(ctx, sql, id)
+	if err != nil {
+		return fmt.Errorf("can't revoke api key: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *APIKeysRepo) TouchAPIKey(ctx context.Context, id uint64) error {
+	sql := "UPDATE api_keys SET last_used_at = NOW() WHERE id = $1"
+
+	if _, err := r.Pool.Exec(ctx, sql, id); err != nil {
+		return fmt.Errorf("can't update api key: %w", err)
+	}
+
+	return nil
+}
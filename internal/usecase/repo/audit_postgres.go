@@ -0,0 +1,97 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/postgres"
+)
+
+type AuditRepo struct {
+	*postgres.Postgres
+}
+
+func NewAuditRepo(pg *postgres.Postgres) *AuditRepo {
+	return &AuditRepo{pg}
+}
+
+func (r *AuditRepo) GetAuditLog(ctx context.Context, filter entity.AuditFilter) ([]entity.AuditRecord, error) {
+	builder := r.Builder.
+		Select("id", "actor", "action", "entity_type", "entity_id", "before", "after", "ip", "created_at").
+		From("audit_log").
+		OrderBy("created_at DESC", "id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset)
+
+	if filter.Actor != "" {
+		builder = builder.Where(squirrel.Eq{"actor": filter.Actor})
+	}
+	if filter.Action != "" {
+		builder = builder.Where(squirrel.Eq{"action": filter.Action})
+	}
+	if filter.EntityType != "" {
+		builder = builder.Where(squirrel.Eq{"entity_type": filter.EntityType})
+	}
+	if filter.EntityID != "" {
+		builder = builder.Where(squirrel.Eq{"entity_id": filter.EntityID})
+	}
+	if filter.From != nil {
+		builder = builder.Where(squirrel.GtOrEq{"created_at": *filter.From})
+	}
+	if filter.To != nil {
+		builder = builder.Where(squirrel.Lt{"created_at": *filter.To})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can't create sql query: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("can't query request: %w", err)
+	}
+	defer rows.Close()
+
+	records := make([]entity.AuditRecord, 0, _defaultListCap)
+	for rows.Next() {
+		var rec entity.AuditRecord
+		err := rows.Scan(
+			&rec.ID, &rec.Actor, &rec.Action, &rec.EntityType, &rec.EntityID,
+			&rec.Before, &rec.After, &rec.IP, &rec.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("can't scan row: %w", err)
+		}
+		records = append(records, rec)
+	}
+
+	return records, nil
+}
+
+func (r *AuditRepo) CreateAuditRecord(ctx context.Context, record entity.AuditRecord) error {
+	query, args, err := r.Builder.
+		Insert("audit_log").
+		Columns("actor", "action", "entity_type", "entity_id", "before", "after", "ip").
+		Values(record.Actor, record.Action, record.EntityType, record.EntityID, nullJSON(record.Before), nullJSON(record.After), record.IP).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("can't create sql query: %w", err)
+	}
+
+	if _, err = r.Pool.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("can't insert audit record: %w", err)
+	}
+
+	return nil
+}
+
+// nullJSON keeps empty snapshots as SQL NULL instead of an empty JSONB value.
+func nullJSON(raw []byte) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
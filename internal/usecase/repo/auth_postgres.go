@@ -0,0 +1,146 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type AuthRepo struct {
+	*postgres.Postgres
+}
+
+func NewAuthRepo(pg *postgres.Postgres) *AuthRepo {
+	return &AuthRepo{pg}
+}
+
+func (r *AuthRepo) GetTOTP(ctx context.Context, login string) (*entity.TOTP, error) {
+	query, args, err := r.Builder.
+		Select("login", "secret", "enabled", "last_used_step").
+		From("admin_totp").
+		Where(squirrel.Eq{"login": login}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can't create sql query: %w", err)
+	}
+
+	var t entity.TOTP
+	err = r.Pool.QueryRow(ctx, query, args...).Scan(&t.Login, &t.Secret, &t.Enabled, &t.LastUsedStep)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, entity.ErrTwoFactorNotEnrolled
+		}
+		return nil, fmt.Errorf("can't scan row: %w", err)
+	}
+
+	return &t, nil
+}
+
+func (r *AuthRepo) SaveTOTPSecret(ctx context.Context, login, secret string) error {
+	sql := `
+	INSERT INTO admin_totp (login, secret, enabled, last_used_step)
+	VALUES ($1, $2, false, 0)
+	ON CONFLICT (login) DO UPDATE
+	SET secret = EXCLUDED.secret, enabled = false, last_used_step = 0, confirmed_at = NULL
+	`
+
+	if _, err := r.Pool.Exec(ctx, sql, login, secret); err != nil {
+		return fmt.Errorf("can't save totp secret: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepo) EnableTOTP(ctx context.Context, login string, step int64) error {
+	sql := `
+	UPDATE admin_totp
+	SET enabled = true, last_used_step = $2, confirmed_at = NOW()
+	WHERE login = $1
+	`
+
+	if _, err := r.Pool.Exec(ctx, sql, login, step); err != nil {
+		return fmt.Errorf("can't enable totp: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuthRepo) UseTOTPStep(ctx context.Context, login string, step int64) (bool, error) {
+	sql := `
+	UPDATE admin_totp
+	SET last_used_step = $2
+	WHERE login = $1 AND enabled = true AND last_used_step < $2
+	`
+
+	tag, err := r.Pool.Exec(ctx, sql, login, step)
+	if err != nil {
+		return false, fmt.Errorf("can't update totp step: %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *AuthRepo) DeleteTOTP(ctx context.Context, login string) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("can't begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DELETE FROM admin_recovery_codes WHERE login = $1", login); err != nil {
+		return fmt.Errorf("can't delete recovery codes: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM admin_totp WHERE login = $1", login); err != nil {
+		return fmt.Errorf("can't delete totp: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *AuthRepo) ReplaceRecoveryCodes(ctx context.Context, login string, codeHashes []string) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("can't begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DELETE FROM admin_recovery_codes WHERE login = $1", login); err != nil {
+		return fmt.Errorf("can't delete recovery codes: %w", err)
+	}
+
+	builder := r.Builder.Insert("admin_recovery_codes").Columns("login", "code_hash")
+	for _, hash := range codeHashes {
+		builder = builder.Values(login, hash)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("can't create sql query: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("can't insert recovery codes: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *AuthRepo) UseRecoveryCode(ctx context.Context, login, codeHash string) (bool, error) {
+	sql := `
+	UPDATE admin_recovery_codes
+	SET used_at = NOW()
+	WHERE login = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	tag, err := r.Pool.Exec(ctx, sql, login, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("can't use recovery code: %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
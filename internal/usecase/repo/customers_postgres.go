@@ -0,0 +1,184 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type CustomersRepo struct {
+	*postgres.Postgres
+}
+
+func NewCustomersRepo(pg *postgres.Postgres) *CustomersRepo {
+	return &CustomersRepo{pg}
+}
+
+func (r *CustomersRepo) CreateCustomer(ctx context.Context, customer entity.Customer, passwordHash string) (uint64, error) {
+	sql := `
+	INSERT INTO customers (email, name, password_hash)
+	VALUES ($1, $2, $3)
+	RETURNING id
+	`
+
+	var id uint64
+	if err := r.Pool.QueryRow(ctx, sql, customer.Email, customer.Name, passwordHash).Scan(&id); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == _uniqueViolation {
+			return 0, entity.ErrCustomerExists
+		}
+		return 0, fmt.Errorf("can't create customer: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *CustomersRepo) GetCustomerByID(ctx context.Context, id uint64) (*entity.Customer, error) {
+	sql := "SELECT id, email, name, verified_at, created_at FROM customers WHERE id = $1"
+
+	var c entity.Customer
+	if err := r.Pool.QueryRow(ctx, sql, id).Scan(&c.ID, &c.Email, &c.Name, &c.VerifiedAt, &c.CreatedAt); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, entity.ErrCustomerNotFound
+		}
+		return nil, fmt.Errorf("can't get customer: %w", err)
+	}
+
+	return &c, nil
+}
+
+func (r *CustomersRepo) GetCustomerByEmail(ctx context.Context, email string) (*entity.Customer, string, error) {
+	sql := "SELECT id, email, name, verified_at, created_at, password_hash FROM customers WHERE email = $1"
+
+	var c entity.Customer
+	var passwordHash string
+	err := r.Pool.QueryRow(ctx, sql, email).Scan(&c.ID, &c.Email, &c.Name, &c.VerifiedAt, &c.CreatedAt, &passwordHash)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, "", entity.ErrCustomerNotFound
+		}
+		return nil, "", fmt.Errorf("can't get customer: %w", err)
+	}
+
+	return &c, passwordHash, nil
+}
+
+func (r *CustomersRepo) VerifyCustomer(ctx context.Context, id uint64) error {
+	sql := "UPDATE customers SET verified_at = COALESCE(verified_at, NOW()) WHERE id = $1"
+
+	tag, err := r.Pool.Exec(ctx, sql, id)
+	if err != nil {
+		return fmt.Errorf("can't verify customer: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrCustomerNotFound
+	}
+
+	return nil
+}
+
+func (r *CustomersRepo) SetCustomerPassword(ctx context.Context, id uint64, passwordHash string) error {
+	tag, err := r.Pool.Exec(ctx, "UPDATE customers SET password_hash = $1 WHERE id = $2", passwordHash, id)
+	if err != nil {
+		return fmt.Errorf("can't set customer password: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrCustomerNotFound
+	}
+
+	return nil
+}
+
+func (r *CustomersRepo) CreateCustomerToken(
+	ctx context.Context,
+	customerID uint64,
+	purpose entity.CustomerTokenPurpose,
+	tokenHash string,
+	expiresAt time.Time,
+) error {
+	sql := `
+	INSERT INTO customer_tokens (token_hash, customer_id, purpose, expires_at)
+	VALUES ($1, $2, $3, $4)
+	`
+
+	if _, err := r.Pool.Exec(ctx, sql, tokenHash, customerID, purpose, expiresAt); err != nil {
+		return fmt.Errorf("can't create customer token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *CustomersRepo) UseCustomerToken(ctx context.Context, purpose entity.CustomerTokenPurpose, tokenHash string) (uint64, error) {
+	sql := `
+	DELETE FROM customer_tokens
+	WHERE token_hash = $1 AND purpose = $2
+	RETURNING customer_id, expires_at
+	`
+
+	var customerID uint64
+	var expiresAt time.Time
+	if err := r.Pool.QueryRow(ctx, sql, tokenHash, purpose).Scan(&customerID, &expiresAt); err != nil {
+		if err == pgx.ErrNoRows {
+			return 0, entity.ErrInvalidCustomerToken
+		}
+		return 0, fmt.Errorf("can't use customer token: %w", err)
+	}
+
+	if expiresAt.Before(time.Now()) {
+		return 0, entity.ErrInvalidCustomerToken
+	}
+
+	return customerID, nil
+}
+
+func (r *CustomersRepo) CreateCustomerSession(ctx context.Context, customerID uint64, tokenHash string, expiresAt time.Time) error {
+	sql := `
+	INSERT INTO customer_sessions (token_hash, customer_id, expires_at)
+	VALUES ($1, $2, $3)
+	`
+
+	if _, err := r.Pool.Exec(ctx, sql, tokenHash, customerID, expiresAt); err != nil {
+		return fmt.Errorf("can't create customer session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *CustomersRepo) GetCustomerBySession(ctx context.Context, tokenHash string) (*entity.Customer, error) {
+	sql := `
+	SELECT c.id, c.email, c.name, c.verified_at, c.created_at
+	FROM customer_sessions s
+	JOIN customers c ON s.customer_id = c.id
+	WHERE s.token_hash = $1 AND s.expires_at > NOW()
+	`
+
+	var c entity.Customer
+	if err := r.Pool.QueryRow(ctx, sql, tokenHash).Scan(&c.ID, &c.Email, &c.Name, &c.VerifiedAt, &c.CreatedAt); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, entity.ErrCustomerSessionExpired
+		}
+		return nil, fmt.Errorf("can't get customer session: %w", err)
+	}
+
+	return &c, nil
+}
+
+func (r *CustomersRepo) DeleteCustomerSession(ctx context.Context, tokenHash string) error {
+	if _, err := r.Pool.Exec(ctx, "DELETE FROM customer_sessions WHERE token_hash = $1", tokenHash); err != nil {
+		return fmt.Errorf("can't delete customer session: %w", err)
+	}
+	return nil
+}
+
+func (r *CustomersRepo) DeleteCustomerSessions(ctx context.Context, customerID uint64) error {
+	if _, err := r.Pool.Exec(ctx, "DELETE FROM customer_sessions WHERE customer_id = $1", customerID); err != nil {
+		return fmt.Errorf("can't delete customer sessions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const _uniqueViolation = "23505"
+
+type DiscountsRepo struct {
+	*postgres.Postgres
+}
+
+func NewDiscountsRepo(pg *postgres.Postgres) *DiscountsRepo {
+	return &DiscountsRepo{pg}
+}
+
+const _discountColumns = `
+	id, name, kind, value, currency, genre_id, author_id, work_technique_id,
+	picture_ids, promo_code, starts_at, ends_at, created_at
+`
+
+func (r *DiscountsRepo) GetDiscounts(ctx context.Context) ([]entity.DiscountRule, error) {
+	sql := "SELECT " + _discountColumns + " FROM discount_rules ORDER BY starts_at DESC, id DESC"
+
+	rows, err := r.Pool.Query(ctx, sql)
+	if err != nil {
+		return nil, fmt.Errorf("can't query discounts: %w", err)
+	}
+
+	return scanDiscounts(rows)
+}
+
+func (r *DiscountsRepo) GetActiveDiscounts(ctx context.Context, now time.Time) ([]entity.DiscountRule, error) {
+	sql := "SELECT " + _discountColumns + " FROM discount_rules WHERE starts_at <= $1 AND ends_at > $1 ORDER BY id"
+
+	rows, err := r.Pool.Query(ctx, sql, now)
+	if err != nil {
+		return nil, fmt.Errorf("can't query active discounts: %w", err)
+	}
+
+	return scanDiscounts(rows)
+}
+
+func scanDiscounts(rows pgx.Rows) ([]entity.DiscountRule, error) {
+	defer rows.Close()
+
+	discounts := make([]entity.DiscountRule, 0, _defaultListCap)
+	for rows.Next() {
+		var d entity.DiscountRule
+		err := rows.Scan(
+			&d.ID, &d.Name, &d.Kind, &d.Value, &d.Currency, &d.GenreID, &d.AuthorID, &d.WorkTechniqueID,
+			&d.PictureIDs, &d.PromoCode, &d.StartsAt, &d.EndsAt, &d.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("can't scan discount: %w", err)
+		}
+		discounts = append(discounts, d)
+	}
+
+	return discounts, rows.Err()
+}
+
+func (r *DiscountsRepo) CreateDiscount(ctx context.Context, d entity.DiscountRule) (uint64, error) {
+	sql := `
+	INSERT INTO discount_rules (name, kind, value, currency, genre_id, author_id, work_technique_id,
+		picture_ids, promo_code, starts_at, ends_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	RETURNING id
+	`
+
+	pictureIDs := d.PictureIDs
+	if pictureIDs == nil {
+		pictureIDs = []uint64{}
+	}
+
+	var id uint64
+	err := r.Pool.QueryRow(ctx, sql,
+		d.Name, d.Kind, d.Value, d.Currency, d.GenreID, d.AuthorID, d.WorkTechniqueID,
+		pictureIDs, d.PromoCode, d.StartsAt, d.EndsAt,
+	).Scan(&id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == _uniqueViolation {
+			return 0, entity.ErrPromoCodeExists
+		}
+		return 0, fmt.Errorf("can't create discount: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *DiscountsRepo) DeleteDiscount(ctx context.Context, id uint64) error {
+	tag, err := r.Pool.Exec(ctx, "DELETE FROM discount_rules WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("can't delete discount: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrDiscountNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,253 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const _foreignKeyViolation = "23503"
+
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == _foreignKeyViolation
+}
+
+type ExhibitionsRepo struct {
+	*postgres.Postgres
+}
+
+func NewExhibitionsRepo(pg *postgres.Postgres) *ExhibitionsRepo {
+	return &ExhibitionsRepo{pg}
+}
+
+// selectExhibitions also collects picture IDs in curator order.
+func (r *ExhibitionsRepo) selectExhibitions() squirrel.SelectBuilder {
+	return r.Builder.
+		Select(
+			"e.id", "e.title", "e.description", "e.venue", "e.starts_at", "e.ends_at", "e.cover_url",
+			"e.created_at", "e.updated_at",
+			"COALESCE((SELECT array_agg(ep.picture_id ORDER BY ep.position) "+
+				"FROM exhibition_pictures ep WHERE ep.exhibition_id = e.id), '{}')",
+		).
+		From("exhibitions e")
+}
+
+func scanExhibition(row pgx.Row, e *entity.Exhibition) error {
+	return row.Scan(
+		&e.ID, &e.Title, &e.Description, &e.Venue, &e.StartsAt, &e.EndsAt, &e.CoverURL,
+		&e.CreatedAt, &e.UpdatedAt, &e.PictureIDs,
+	)
+}
+
+func (r *ExhibitionsRepo) GetExhibitions(
+	ctx context.Context,
+	filter entity.ExhibitionFilter,
+	now time.Time,
+) ([]entity.Exhibition, error) {
+	builder := r.selectExhibitions().Limit(filter.Limit).Offset(filter.Offset)
+
+	switch filter.Period {
+	case entity.ExhibitionPeriodCurrent:
+		builder = builder.Where("e.starts_at <= ? AND e.ends_at > ?", now, now).OrderBy("e.ends_at", "e.id")
+	case entity.ExhibitionPeriodUpcoming:
+		builder = builder.Where("e.starts_at > ?", now).OrderBy("e.starts_at", "e.id")
+	case entity.ExhibitionPeriodPast:
+		builder = builder.Where("e.ends_at <= ?", now).OrderBy("e.ends_at DESC", "e.id DESC")
+	default:
+		builder = builder.OrderBy("e.starts_at DESC", "e.id DESC")
+	}
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can't create sql query: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("can't query exhibitions: %w", err)
+	}
+	defer rows.Close()
+
+	exhibitions := make([]entity.Exhibition, 0, _defaultListCap)
+	for rows.Next() {
+		var e entity.Exhibition
+		if err := scanExhibition(rows, &e); err != nil {
+			return nil, fmt.Errorf("can't scan exhibition: %w", err)
+		}
+		exhibitions = append(exhibitions, e)
+	}
+
+	return exhibitions, rows.Err()
+}
+
+func (r *ExhibitionsRepo) GetExhibitionByID(ctx context.Context, id uint64) (*entity.Exhibition, error) {
+	sql, args, err := r.selectExhibitions().Where(squirrel.Eq{"e.id": id}).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can't create sql query: %w", err)
+	}
+
+	var e entity.Exhibition
+	if err := scanExhibition(r.Pool.QueryRow(ctx, sql, args...), &e); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, entity.ErrExhibitionNotFound
+		}
+		return nil, fmt.Errorf("can't scan exhibition: %w", err)
+	}
+
+	return &e, nil
+}
+
+func (r *ExhibitionsRepo) CreateExhibition(ctx context.Context, e entity.Exhibition) (uint64, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("can't begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `
+	INSERT INTO exhibitions (title, description, venue, starts_at, ends_at)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id
+	`
+
+	var id uint64
+	if err := tx.QueryRow(ctx, sql, e.Title, e.Description, e.Venue, e.StartsAt, e.EndsAt).Scan(&id); err != nil {
+		return 0, fmt.Errorf("can't create exhibition: %w", err)
+	}
+
+	if err := insertExhibitionPictures(ctx, tx, id, e.PictureIDs); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("can't commit exhibition: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *ExhibitionsRepo) UpdateExhibition(ctx context.Context, id uint64, req entity.ExhibitionUpdateRequest) error {
+	builder := r.Builder.Update("exhibitions").Set("updated_at", squirrel.Expr("NOW()"))
+
+	if req.Title != nil {
+		builder = builder.Set("title", *req.Title)
+	}
+	if req.Description != nil {
+		builder = builder.Set("description", *req.Description)
+	}
+	if req.Venue != nil {
+		builder = builder.Set("venue", *req.Venue)
+	}
+	if req.StartsAt != nil {
+		builder = builder.Set("starts_at", *req.StartsAt)
+	}
+	if req.EndsAt != nil {
+		builder = builder.Set("ends_at", *req.EndsAt)
+	}
+
+	sql, args, err := builder.Where(squirrel.Eq{"id": id}).ToSql()
+	if err != nil {
+		return fmt.Errorf("can't build update query: %w", err)
+	}
+
+	tag, err := r.Pool.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("can't update exhibition: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrExhibitionNotFound
+	}
+
+	return nil
+}
+
+func (r *ExhibitionsRepo) SetExhibitionPictures(ctx context.Context, id uint64, pictureIDs []uint64) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("can't begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, "UPDATE exhibitions SET updated_at = NOW() WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("can't update exhibition: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrExhibitionNotFound
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM exhibition_pictures WHERE exhibition_id = $1", id); err != nil {
+		return fmt.Errorf("can't clear exhibition pictures: %w", err)
+	}
+	if err := insertExhibitionPictures(ctx, tx, id, pictureIDs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("can't commit exhibition pictures: %w", err)
+	}
+
+	return nil
+}
+
+func insertExhibitionPictures(ctx context.Context, tx pgx.Tx, exhibitionID uint64, pictureIDs []uint64) error {
+	if len(pictureIDs) == 0 {
+		return nil
+	}
+
+	sql := `
+	INSERT INTO exhibition_pictures (exhibition_id, picture_id, position)
+	SELECT $1, p.id, p.position
+	FROM unnest($2::integer[]) WITH ORDINALITY AS p(id, position)
+	`
+
+	if _, err := tx.Exec(ctx, sql, exhibitionID, pictureIDs); err != nil {
+		if isForeignKeyViolation(err) {
+			return entity.ErrPictureNotFound
+		}
+		return fmt.Errorf("can't save exhibition pictures: %w", err)
+	}
+
+	return nil
+}
+
+// SetExhibitionCover stores the cover URL and returns the previous one so its file can be removed.
+func (r *ExhibitionsRepo) SetExhibitionCover(ctx context.Context, id uint64, url string) (string, error) {
+	sql := `
+	UPDATE exhibitions e SET cover_url = $2, updated_at = NOW()
+	FROM (SELECT cover_url FROM exhibitions WHERE id = $1 FOR UPDATE) old
+	WHERE e.id = $1
+	RETURNING old.cover_url
+	`
+
+	var old string
+	if err := r.Pool.QueryRow(ctx, sql, id, url).Scan(&old); err != nil {
+		if err == pgx.ErrNoRows {
+			return "", entity.ErrExhibitionNotFound
+		}
+		return "", fmt.Errorf("can't set exhibition cover: %w", err)
+	}
+
+	return old, nil
+}
+
+// DeleteExhibition returns the cover URL of the deleted exhibition.
+func (r *ExhibitionsRepo) DeleteExhibition(ctx context.Context, id uint64) (string, error) {
+	var cover string
+	if err := r.Pool.QueryRow(ctx, "DELETE FROM exhibitions WHERE id = $1 RETURNING cover_url", id).Scan(&cover); err != nil {
+		if err == pgx.ErrNoRows {
+			return "", entity.ErrExhibitionNotFound
+		}
+		return "", fmt.Errorf("can't delete exhibition: %w", err)
+	}
+
+	return cover, nil
+}
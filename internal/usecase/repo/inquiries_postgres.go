@@ -0,0 +1,147 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type InquiriesRepo struct {
+	*postgres.Postgres
+}
+
+func NewInquiriesRepo(pg *postgres.Postgres) *InquiriesRepo {
+	return &InquiriesRepo{pg}
+}
+
+func (r *InquiriesRepo) CreateInquiry(ctx context.Context, inquiry entity.Inquiry) (uint64, error) {
+	sql := `
+	INSERT INTO inquiries (picture_id, customer_id, name, contact, message, status, ip)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING id
+	`
+
+	var id uint64
+	err := r.Pool.QueryRow(ctx, sql,
+		inquiry.PictureID,
+		inquiry.CustomerID,
+		inquiry.Name,
+		inquiry.Contact,
+		inquiry.Message,
+		inquiry.Status,
+		inquiry.IP,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("can't create inquiry: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *InquiriesRepo) selectInquiries() squirrel.SelectBuilder {
+	return r.Builder.
+		Select(
+			"i.id", "i.picture_id", "p.title", "i.customer_id", "i.name", "i.contact", "i.message",
+			"i.status", "i.assigned_to", "i.ip", "i.created_at", "i.updated_at",
+		).
+		From("inquiries i").
+		Join("pictures p ON i.picture_id = p.id")
+}
+
+func scanInquiry(row pgx.Row, i *entity.Inquiry) error {
+	return row.Scan(
+		&i.ID, &i.PictureID, &i.PictureTitle, &i.CustomerID, &i.Name, &i.Contact, &i.Message,
+		&i.Status, &i.AssignedTo, &i.IP, &i.CreatedAt, &i.UpdatedAt,
+	)
+}
+
+func (r *InquiriesRepo) GetInquiries(ctx context.Context, filter entity.InquiryFilter) ([]entity.Inquiry, error) {
+	builder := r.selectInquiries().
+		OrderBy("i.created_at DESC", "i.id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset)
+
+	if filter.Status != "" {
+		builder = builder.Where(squirrel.Eq{"i.status": filter.Status})
+	}
+	if filter.AssignedTo != "" {
+		builder = builder.Where(squirrel.Eq{"i.assigned_to": filter.AssignedTo})
+	}
+	if filter.PictureID != 0 {
+		builder = builder.Where(squirrel.Eq{"i.picture_id": filter.PictureID})
+	}
+	if filter.CustomerID != 0 {
+		builder = builder.Where(squirrel.Eq{"i.customer_id": filter.CustomerID})
+	}
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can't create sql query: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("can't query inquiries: %w", err)
+	}
+	defer rows.Close()
+
+	inquiries := make([]entity.Inquiry, 0, _defaultListCap)
+	for rows.Next() {
+		var i entity.Inquiry
+		if err := scanInquiry(rows, &i); err != nil {
+			return nil, fmt.Errorf("can't scan inquiry: %w", err)
+		}
+		inquiries = append(inquiries, i)
+	}
+
+	return inquiries, nil
+}
+
+func (r *InquiriesRepo) GetInquiryByID(ctx context.Context, id uint64) (*entity.Inquiry, error) {
+	sql, args, err := r.selectInquiries().Where(squirrel.Eq{"i.id": id}).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can't create sql query: %w", err)
+	}
+
+	var i entity.Inquiry
+	if err := scanInquiry(r.Pool.QueryRow(ctx, sql, args...), &i); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, entity.ErrInquiryNotFound
+		}
+		return nil, fmt.Errorf("can't get inquiry: %w", err)
+	}
+
+	return &i, nil
+}
+
+func (r *InquiriesRepo) AssignInquiry(ctx context.Context, id uint64, assignee string) error {
+	sql := "UPDATE inquiries SET assigned_to = $1, updated_at = NOW() WHERE id = $2"
+
+	tag, err := r.Pool.Exec(ctx, sql, assignee, id)
+	if err != nil {
+		return fmt.Errorf("can't assign inquiry: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrInquiryNotFound
+	}
+
+	return nil
+}
+
+func (r *InquiriesRepo) UpdateInquiryStatus(ctx context.Context, id uint64, status entity.InquiryStatus) error {
+	sql := "UPDATE inquiries SET status = $1, updated_at = NOW() WHERE id = $2"
+
+	tag, err := r.Pool.Exec(ctx, sql, status, id)
+	if err != nil {
+		return fmt.Errorf("can't update inquiry status: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrInquiryNotFound
+	}
+
+	return nil
+}
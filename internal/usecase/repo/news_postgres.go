@@ -3,6 +3,7 @@ package repo
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
@@ -22,17 +23,92 @@ func NewNewsRepo(pg *postgres.Postgres) *NewsRepo {
 	return &NewsRepo{pg}
 }
 
-func (r *NewsRepo) GetNews(ctx context.Context) ([]entity.News, error) {
-	query, _, err := r.Builder.
-		Select("id", "title", "content", "created_at").
-		From("news").
-		OrderBy("created_at DESC").
-		ToSql()
+// selectNews also collects linked picture IDs in their order and author IDs.
+func (r *NewsRepo) selectNews(filter entity.NewsFilter) squirrel.SelectBuilder {
+	builder := r.Builder.
+		Select(
+			"n.id", "n.slug", "n.title", "n.content", "n.cover_url", "n.status", "n.publish_at", "n.created_at", "n.updated_at",
+			"COALESCE((SELECT array_agg(np.picture_id ORDER BY np.position) "+
+				"FROM news_pictures np WHERE np.news_id = n.id), '{}')",
+			"COALESCE((SELECT array_agg(na.author_id ORDER BY na.author_id) "+
+				"FROM news_authors na WHERE na.news_id = n.id), '{}')",
+		).
+		From("news n")
+
+	if filter.Status != "" {
+		builder = builder.Where(squirrel.Eq{"n.status": filter.Status})
+	}
+	if filter.PictureID != 0 {
+		builder = builder.Where("n.id IN (SELECT news_id FROM news_pictures WHERE picture_id = ?)", filter.PictureID)
+	}
+	return builder
+}
+
+func scanNews(row pgx.Row, n *entity.News) error {
+	return row.Scan(
+		&n.ID, &n.Slug, &n.Title, &n.Content, &n.CoverURL, &n.Status, &n.PublishAt, &n.CreatedAt, &n.UpdatedAt,
+		&n.PictureIDs, &n.AuthorIDs,
+	)
+}
+
+// GetNews returns news for admins, newest first.
+func (r *NewsRepo) GetNews(ctx context.Context, filter entity.NewsFilter) ([]entity.News, error) {
+	return r.queryNews(ctx, r.selectNews(filter).OrderBy("n.created_at DESC", "n.id DESC"))
+}
+
+// GetPublicNews returns news visible at the moment now, latest publication first.
+// The condition matches entity.News.Public, so scheduled news show up on time
+// even before the publisher marks them published. filter.Status is ignored.
+func (r *NewsRepo) GetPublicNews(ctx context.Context, filter entity.NewsFilter, now time.Time) ([]entity.News, error) {
+	filter.Status = ""
+	builder := r.selectNews(filter).
+		Where(squirrel.Eq{"n.status": []entity.NewsStatus{entity.NewsStatusPublished, entity.NewsStatusScheduled}}).
+		Where(squirrel.LtOrEq{"n.publish_at": now}).
+		OrderBy("n.publish_at DESC", "n.id DESC")
+
+	return r.queryNews(ctx, builder)
+}
+
+// GetPublicNewsPage seeks past the key of the previous page in the order of GetPublicNews.
+func (r *NewsRepo) GetPublicNewsPage(
+	ctx context.Context,
+	filter entity.NewsFilter,
+	now time.Time,
+	after *entity.NewsKey,
+	limit int,
+) ([]entity.News, *entity.NewsKey, error) {
+	filter.Status = ""
+	builder := r.selectNews(filter).
+		Where(squirrel.Eq{"n.status": []entity.NewsStatus{entity.NewsStatusPublished, entity.NewsStatusScheduled}}).
+		Where(squirrel.LtOrEq{"n.publish_at": now}).
+		OrderBy("n.publish_at DESC", "n.id DESC").
+		Limit(uint64(limit) + 1)
+	if after != nil {
+		builder = builder.Where("(n.publish_at, n.id) < (?, ?)", after.PublishAt, after.ID)
+	}
+
+	news, err := r.queryNews(ctx, builder)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the extra row only tells that the listing goes on
+	var next *entity.NewsKey
+	if len(news) > limit {
+		news = news[:limit]
+		last := news[limit-1]
+		next = &entity.NewsKey{PublishAt: *last.PublishAt, ID: last.ID}
+	}
+	return news, next, nil
+}
+
+func (r *NewsRepo) queryNews(ctx context.Context, builder squirrel.SelectBuilder) ([]entity.News, error) {
+	query, args, err := builder.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("can't create sql query: %w", err)
 	}
 
-	rows, err := r.Pool.Query(ctx, query)
+	rows, err := r.Pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("can't query request: %w", err)
 	}
@@ -41,33 +117,34 @@ func (r *NewsRepo) GetNews(ctx context.Context) ([]entity.News, error) {
 	news := make([]entity.News, 0, _defaultNewsListCap)
 	for rows.Next() {
 		var n entity.News
-		if err := rows.Scan(&n.ID, &n.Title, &n.Content, &n.CreatedAt); err != nil {
+		if err := scanNews(rows, &n); err != nil {
 			return nil, fmt.Errorf("can't scan row: %w", err)
 		}
 		news = append(news, n)
 	}
 
-	return news, nil
+	return news, rows.Err()
 }
 
 func (r *NewsRepo) GetNewsByID(ctx context.Context, id uint64) (*entity.News, error) {
-	query, args, err := r.Builder.
-		Select("id", "title", "content", "created_at").
-		From("news").
-		Where(squirrel.Eq{"id": id}).
+	return r.getNews(ctx, squirrel.Eq{"n.id": id})
+}
+
+// GetNewsBySlug finds news by its current or a previous slug.
+func (r *NewsRepo) GetNewsBySlug(ctx context.Context, slug string) (*entity.News, error) {
+	return r.getNews(ctx, squirrel.Expr(_newsSlugs.match("n.id", "n.slug"), slug, slug))
+}
+
+func (r *NewsRepo) getNews(ctx context.Context, where squirrel.Sqlizer) (*entity.News, error) {
+	query, args, err := r.selectNews(entity.NewsFilter{}).
+		Where(where).
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("can't create sql query: %w", err)
 	}
 
 	var news entity.News
-	err = r.Pool.QueryRow(ctx, query, args...).Scan(
-		&news.ID,
-		&news.Title,
-		&news.Content,
-		&news.CreatedAt,
-	)
-	if err != nil {
+	if err = scanNews(r.Pool.QueryRow(ctx, query, args...), &news); err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, entity.ErrNewsNotFound
 		}
@@ -77,21 +154,45 @@ func (r *NewsRepo) GetNewsByID(ctx context.Context, id uint64) (*entity.News, er
 	return &news, nil
 }
 
-func (r *NewsRepo) CreateNews(ctx context.Context, req entity.NewsCreateRequest) error {
+func (r *NewsRepo) CreateNews(ctx context.Context, req entity.NewsCreateRequest) (uint64, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("can't begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	slug, err := _newsSlugs.unique(ctx, tx, req.Title, 0)
+	if err != nil {
+		return 0, err
+	}
+	if err := _newsSlugs.claim(ctx, tx, slug); err != nil {
+		return 0, err
+	}
+
 	query, args, err := r.Builder.
 		Insert("news").
-		Columns("title", "content").
-		Values(req.Title, req.Content).
+		Columns("slug", "title", "content", "status", "publish_at").
+		Values(slug, req.Title, req.Content, req.Status, req.PublishAt).
+		Suffix("RETURNING id").
 		ToSql()
 	if err != nil {
-		return fmt.Errorf("can't create sql query: %w", err)
+		return 0, fmt.Errorf("can't create sql query: %w", err)
 	}
 
-	if _, err = r.Pool.Exec(ctx, query, args...); err != nil {
-		return fmt.Errorf("can't insert news: %w", err)
+	var id uint64
+	if err = tx.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		return 0, fmt.Errorf("can't insert news: %w", err)
 	}
 
-	return nil
+	if err := insertNewsLinks(ctx, tx, id, req.PictureIDs, req.AuthorIDs); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("can't commit news: %w", err)
+	}
+
+	return id, nil
 }
 
 func (r *NewsRepo) UpdateNews(ctx context.Context, id uint64, req entity.NewsUpdateRequest) error {
@@ -103,33 +204,165 @@ func (r *NewsRepo) UpdateNews(ctx context.Context, id uint64, req entity.NewsUpd
 	if req.Content != nil {
 		builder = builder.Set("content", *req.Content)
 	}
+	if req.Status != nil {
+		builder = builder.Set("status", *req.Status)
+	}
+	if req.PublishAt != nil {
+		builder = builder.Set("publish_at", *req.PublishAt)
+	}
 
-	builder = builder.Where(squirrel.Eq{"id": id})
+	builder = builder.
+		Set("updated_at", squirrel.Expr("NOW()")).
+		Where(squirrel.Eq{"id": id})
 
 	sql, args, err := builder.ToSql()
 	if err != nil {
 		return fmt.Errorf("can't build update query: %w", err)
 	}
 
-	if _, err = r.Pool.Exec(ctx, sql, args...); err != nil {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("can't begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, sql, args...)
+	if err != nil {
 		return fmt.Errorf("can't update news: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrNewsNotFound
+	}
+
+	if req.Title != nil {
+		if err := _newsSlugs.rename(ctx, tx, id, *req.Title); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("can't commit news: %w", err)
+	}
 
 	return nil
 }
 
-func (r *NewsRepo) DeleteNews(ctx context.Context, id uint64) error {
-	query, args, err := r.Builder.
-		Delete("news").
-		Where(squirrel.Eq{"id": id}).
-		ToSql()
+// PublishScheduledNews marks scheduled news whose time has come as published and returns their IDs.
+func (r *NewsRepo) PublishScheduledNews(ctx context.Context, now time.Time) ([]uint64, error) {
+	rows, err := r.Pool.Query(ctx, `
+		UPDATE news SET status = $1, updated_at = NOW()
+		WHERE status = $2 AND publish_at <= $3
+		RETURNING id`,
+		entity.NewsStatusPublished, entity.NewsStatusScheduled, now)
+	if err != nil {
+		return nil, fmt.Errorf("can't publish scheduled news: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]uint64, 0)
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("can't scan news id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// DeleteNews returns the cover URL of the deleted news.
+func (r *NewsRepo) DeleteNews(ctx context.Context, id uint64) (string, error) {
+	var cover string
+	if err := r.Pool.QueryRow(ctx, "DELETE FROM news WHERE id = $1 RETURNING cover_url", id).Scan(&cover); err != nil {
+		if err == pgx.ErrNoRows {
+			return "", entity.ErrNewsNotFound
+		}
+		return "", fmt.Errorf("can't delete news: %w", err)
+	}
+
+	return cover, nil
+}
+
+func (r *NewsRepo) SetNewsLinks(ctx context.Context, id uint64, pictureIDs, authorIDs []uint64) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("can't begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, "UPDATE news SET updated_at = NOW() WHERE id = $1", id)
 	if err != nil {
-		return fmt.Errorf("can't create sql query: %w", err)
+		return fmt.Errorf("can't update news: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrNewsNotFound
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM news_pictures WHERE news_id = $1", id); err != nil {
+		return fmt.Errorf("can't clear news pictures: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM news_authors WHERE news_id = $1", id); err != nil {
+		return fmt.Errorf("can't clear news authors: %w", err)
+	}
+	if err := insertNewsLinks(ctx, tx, id, pictureIDs, authorIDs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("can't commit news links: %w", err)
+	}
+
+	return nil
+}
+
+func insertNewsLinks(ctx context.Context, tx pgx.Tx, newsID uint64, pictureIDs, authorIDs []uint64) error {
+	if len(pictureIDs) > 0 {
+		sql := `
+		INSERT INTO news_pictures (news_id, picture_id, position)
+		SELECT $1, p.id, p.position
+		FROM unnest($2::integer[]) WITH ORDINALITY AS p(id, position)
+		`
+		if _, err := tx.Exec(ctx, sql, newsID, pictureIDs); err != nil {
+			if isForeignKeyViolation(err) {
+				return entity.ErrPictureNotFound
+			}
+			return fmt.Errorf("can't save news pictures: %w", err)
+		}
 	}
 
-	if _, err = r.Pool.Exec(ctx, query, args...); err != nil {
-		return fmt.Errorf("can't delete news: %w", err)
+	if len(authorIDs) > 0 {
+		sql := `
+		INSERT INTO news_authors (news_id, author_id)
+		SELECT $1, unnest($2::integer[])
+		`
+		if _, err := tx.Exec(ctx, sql, newsID, authorIDs); err != nil {
+			if isForeignKeyViolation(err) {
+				return entity.ErrAuthorNotFound
+			}
+			return fmt.Errorf("can't save news authors: %w", err)
+		}
 	}
 
 	return nil
 }
+
+// SetNewsCover stores the cover URL and returns the previous one so its file can be removed.
+func (r *NewsRepo) SetNewsCover(ctx context.Context, id uint64, url string) (string, error) {
+	sql := `
+	UPDATE news n SET cover_url = $2, updated_at = NOW()
+	FROM (SELECT cover_url FROM news WHERE id = $1 FOR UPDATE) old
+	WHERE n.id = $1
+	RETURNING old.cover_url
+	`
+
+	var old string
+	if err := r.Pool.QueryRow(ctx, sql, id, url).Scan(&old); err != nil {
+		if err == pgx.ErrNoRows {
+			return "", entity.ErrNewsNotFound
+		}
+		return "", fmt.Errorf("can't set news cover: %w", err)
+	}
+
+	return old, nil
+}
@@ -0,0 +1,201 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type OrdersRepo struct {
+	*postgres.Postgres
+}
+
+func NewOrdersRepo(pg *postgres.Postgres) *OrdersRepo {
+	return &OrdersRepo{pg}
+}
+
+func (r *OrdersRepo) CreateOrder(ctx context.Context, order entity.Order) (uint64, error) {
+	sql := `
+	INSERT INTO orders (picture_id, customer_id, customer_name, customer_email, customer_phone, amount, currency, promo_code, status)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	RETURNING id
+	`
+
+	var id uint64
+	err := r.Pool.QueryRow(ctx, sql,
+		order.PictureID,
+		order.CustomerID,
+		order.CustomerName,
+		order.CustomerEmail,
+		order.CustomerPhone,
+		order.Amount,
+		order.Currency,
+		order.PromoCode,
+		order.Status,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("can't create order: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *OrdersRepo) SetOrderPayment(ctx context.Context, id uint64, payment entity.Payment) error {
+	sql := "UPDATE orders SET payment_id = $1, payment_url = $2, updated_at = NOW() WHERE id = $3"
+
+	tag, err := r.Pool.Exec(ctx, sql, payment.ID, payment.ConfirmationURL, id)
+	if err != nil {
+		return fmt.Errorf("can't set order payment: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrOrderNotFound
+	}
+
+	return nil
+}
+
+func (r *OrdersRepo) selectOrders() squirrel.SelectBuilder {
+	return r.Builder.
+		Select(
+			"o.id", "o.picture_id", "p.title", "o.customer_id", "o.customer_name", "o.customer_email", "o.customer_phone",
+			"o.amount", "o.currency", "o.promo_code", "o.status", "o.payment_id", "o.payment_url", "o.paid_at", "o.created_at", "o.updated_at",
+		).
+		From("orders o").
+		Join("pictures p ON o.picture_id = p.id")
+}
+
+func scanOrder(row pgx.Row, o *entity.Order) error {
+	return row.Scan(
+		&o.ID, &o.PictureID, &o.PictureTitle, &o.CustomerID, &o.CustomerName, &o.CustomerEmail, &o.CustomerPhone,
+		&o.Amount, &o.Currency, &o.PromoCode, &o.Status, &o.PaymentID, &o.PaymentURL, &o.PaidAt, &o.CreatedAt, &o.UpdatedAt,
+	)
+}
+
+func (r *OrdersRepo) GetOrders(ctx context.Context, filter entity.OrderFilter) ([]entity.Order, error) {
+	builder := r.selectOrders().
+		OrderBy("o.created_at DESC", "o.id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset)
+
+	if filter.Status != "" {
+		builder = builder.Where(squirrel.Eq{"o.status": filter.Status})
+	}
+	if filter.PictureID != 0 {
+		builder = builder.Where(squirrel.Eq{"o.picture_id": filter.PictureID})
+	}
+	if filter.CustomerID != 0 {
+		builder = builder.Where(squirrel.Eq{"o.customer_id": filter.CustomerID})
+	}
+
+	return r.queryOrders(ctx, builder)
+}
+
+// GetExpiredOrders returns pending orders created before createdBefore, oldest first.
+func (r *OrdersRepo) GetExpiredOrders(ctx context.Context, createdBefore time.Time) ([]entity.Order, error) {
+	builder := r.selectOrders().
+		Where(squirrel.Eq{"o.status": entity.OrderStatusPending}).
+		Where(squirrel.Lt{"o.created_at": createdBefore}).
+		OrderBy("o.created_at", "o.id")
+
+	return r.queryOrders(ctx, builder)
+}
+
+func (r *OrdersRepo) CountPendingOrders(ctx context.Context, pictureID uint64) (int, error) {
+	sql := "SELECT COUNT(*) FROM orders WHERE picture_id = $1 AND status = 'pending'"
+
+	var count int
+	if err := r.Pool.QueryRow(ctx, sql, pictureID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("can't count pending orders: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *OrdersRepo) queryOrders(ctx context.Context, builder squirrel.SelectBuilder) ([]entity.Order, error) {
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can't create sql query: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("can't query orders: %w", err)
+	}
+	defer rows.Close()
+
+	orders := make([]entity.Order, 0, _defaultListCap)
+	for rows.Next() {
+		var o entity.Order
+		if err := scanOrder(rows, &o); err != nil {
+			return nil, fmt.Errorf("can't scan order: %w", err)
+		}
+		orders = append(orders, o)
+	}
+
+	return orders, nil
+}
+
+func (r *OrdersRepo) GetOrderByID(ctx context.Context, id uint64) (*entity.Order, error) {
+	return r.getOrder(ctx, squirrel.Eq{"o.id": id})
+}
+
+func (r *OrdersRepo) GetOrderByPaymentID(ctx context.Context, paymentID string) (*entity.Order, error) {
+	return r.getOrder(ctx, squirrel.Eq{"o.payment_id": paymentID})
+}
+
+func (r *OrdersRepo) getOrder(ctx context.Context, where squirrel.Eq) (*entity.Order, error) {
+	sql, args, err := r.selectOrders().Where(where).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can't create sql query: %w", err)
+	}
+
+	var o entity.Order
+	if err := scanOrder(r.Pool.QueryRow(ctx, sql, args...), &o); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, entity.ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("can't get order: %w", err)
+	}
+
+	return &o, nil
+}
+
+func (r *OrdersRepo) FinishOrder(ctx context.Context, id uint64, status entity.OrderStatus) error {
+	sql := `
+	UPDATE orders
+	SET status = $1,
+		paid_at = CASE WHEN $1 = 'paid' THEN NOW() ELSE paid_at END,
+		updated_at = NOW()
+	WHERE id = $2 AND status = 'pending'
+	`
+
+	tag, err := r.Pool.Exec(ctx, sql, status, id)
+	if err != nil {
+		return fmt.Errorf("can't finish order: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrOrderNotPending
+	}
+
+	return nil
+}
+
+func (r *OrdersRepo) MarkOrderPaidLate(ctx context.Context, id uint64) (bool, error) {
+	sql := `
+	UPDATE orders
+	SET status = 'paid_late', paid_at = NOW(), updated_at = NOW()
+	WHERE id = $1 AND status IN ('cancelled', 'failed')
+	`
+
+	tag, err := r.Pool.Exec(ctx, sql, id)
+	if err != nil {
+		return false, fmt.Errorf("can't mark order paid late: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
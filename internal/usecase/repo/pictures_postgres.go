@@ -18,24 +18,57 @@ func NewPicturesRepo(pg *postgres.Postgres) *PicturesRepo {
 	return &PicturesRepo{pg}
 }
 
-func (r *PicturesRepo) GetPictures(ctx context.Context) ([]entity.Picture, error) {
-	sql := `
-	SELECT 
-		p.id, p.title, p.price, p.created_at,
-		a.id, a.full_name,
-		d.id, d.width, d.height,
-		wt.id, wt.name,
-		g.id, g.name,
-		pp.id, pp.url, pp.mime
-	FROM pictures p
-	JOIN authors a ON p.author_id = a.id
-	JOIN dimensions d ON p.dimensions_id = d.id
-	JOIN work_techniques wt ON p.work_technique_id = wt.id
-	JOIN genres g ON p.genre_id = g.id
-	JOIN pictures_photos pp ON p.id = pp.picture_id AND pp.is_main = true
+const _pictureColumns = `
+	p.id, p.slug, p.title, p.description, p.price, p.currency, p.status, p.status_changed_at, p.created_at,
+	a.id, a.slug, a.full_name,
+	d.id, d.width, d.height,
+	wt.id, wt.name,
+	g.id, g.name,
+	COALESCE(pp.id, 0), COALESCE(pp.url, ''), COALESCE(pp.mime, '')
 	`
 
-	rows, err := r.Pool.Query(ctx, sql)
+func (r *PicturesRepo) selectPictures() squirrel.SelectBuilder {
+	return r.Builder.
+		Select(_pictureColumns).
+		From("pictures p").
+		Join("authors a ON p.author_id = a.id").
+		Join("dimensions d ON p.dimensions_id = d.id").
+		Join("work_techniques wt ON p.work_technique_id = wt.id").
+		Join("genres g ON p.genre_id = g.id").
+		LeftJoin("pictures_photos pp ON p.id = pp.picture_id AND pp.is_main = true")
+}
+
+func scanPicture(row pgx.Row, pic *entity.Picture) error {
+	return row.Scan(pictureFields(pic)...)
+}
+
+// pictureFields are the scan destinations for _pictureColumns.
+func pictureFields(pic *entity.Picture) []any {
+	return []any{
+		&pic.ID, &pic.Slug, &pic.Title, &pic.Description, &pic.Price, &pic.Currency, &pic.Status, &pic.StatusAt, &pic.CreatedAt,
+		&pic.Author.ID, &pic.Author.Slug, &pic.Author.FullName,
+		&pic.Dimensions.ID, &pic.Dimensions.Width, &pic.Dimensions.Height,
+		&pic.WorkTechnique.ID, &pic.WorkTechnique.Name,
+		&pic.Genre.ID, &pic.Genre.Name,
+		&pic.Photo.ID, &pic.Photo.URL, &pic.Photo.Mime,
+	}
+}
+
+func (r *PicturesRepo) GetPictures(ctx context.Context, filter entity.PictureFilter) ([]entity.Picture, error) {
+	builder := filterPictures(r.selectPictures(), filter)
+	if filter.Sort == "" {
+		builder = builder.OrderBy("p.id")
+	} else {
+		key, args, desc := pictureSortKey(filter.Sort, filter.Rates)
+		builder = builder.OrderByClause(fmt.Sprintf("%[1]s %[2]s, p.id %[2]s", key, direction(desc)), args...)
+	}
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can't create sql query: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
 	if err != nil {
 		return nil, fmt.Errorf("can't query pictures: %w", err)
 	}
@@ -44,28 +77,292 @@ func (r *PicturesRepo) GetPictures(ctx context.Context) ([]entity.Picture, error
 	var pictures []entity.Picture
 	for rows.Next() {
 		var pic entity.Picture
-		err := rows.Scan(
-			&pic.ID, &pic.Title, &pic.Price, &pic.CreatedAt,
-			&pic.Author.ID, &pic.Author.FullName,
-			&pic.Dimensions.ID, &pic.Dimensions.Width, &pic.Dimensions.Height,
-			&pic.WorkTechnique.ID, &pic.WorkTechnique.Name,
-			&pic.Genre.ID, &pic.Genre.Name,
-			&pic.Photo.ID, &pic.Photo.URL, &pic.Photo.Mime,
-		)
-		if err != nil {
+		if err := scanPicture(rows, &pic); err != nil {
 			return nil, fmt.Errorf("can't scan picture: %w", err)
 		}
+		pictures = append(pictures, pic)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't read pictures: %w", err)
+	}
 
-		gallery, err := r.getPictureGallery(ctx, pic.ID)
-		if err != nil {
-			return nil, err
+	if err := r.embedGalleries(ctx, pictures); err != nil {
+		return nil, err
+	}
+	return pictures, nil
+}
+
+// GetPicturesPage seeks past the key of the previous page instead of skipping
+// rows, so pictures added meanwhile don't shift the pages.
+func (r *PicturesRepo) GetPicturesPage(
+	ctx context.Context,
+	filter entity.PictureFilter,
+	after *entity.PictureKey,
+	limit int,
+) ([]entity.Picture, *entity.PictureKey, error) {
+	key, keyArgs, desc := pictureSortKey(filter.Sort, filter.Rates)
+	price, priceArgs := basePrice(filter.Rates)
+
+	builder := filterPictures(r.selectPictures().Column(squirrel.Expr(price+"::float8", priceArgs...)), filter).
+		OrderByClause(fmt.Sprintf("%[1]s %[2]s, p.id %[2]s", key, direction(desc)), keyArgs...).
+		Limit(uint64(limit) + 1)
+
+	if after != nil {
+		var value any = after.CreatedAt
+		if filter.Sort == entity.PictureSortPriceAsc || filter.Sort == entity.PictureSortPriceDesc {
+			value = after.Price
+		}
+
+		cmp := ">"
+		if desc {
+			cmp = "<"
 		}
-		pic.Gallery = gallery
+		builder = builder.Where(fmt.Sprintf("(%s, p.id) %s (?, ?)", key, cmp), append(keyArgs, value, after.ID)...)
+	}
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't create sql query: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't query pictures: %w", err)
+	}
+	defer rows.Close()
 
+	pictures := make([]entity.Picture, 0, limit+1)
+	prices := make([]float64, 0, limit+1)
+	for rows.Next() {
+		var pic entity.Picture
+		var price float64
+		if err := rows.Scan(append(pictureFields(&pic), &price)...); err != nil {
+			return nil, nil, fmt.Errorf("can't scan picture: %w", err)
+		}
 		pictures = append(pictures, pic)
+		prices = append(prices, price)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("can't read pictures: %w", err)
 	}
 
-	return pictures, nil
+	// the extra row only tells that the listing goes on
+	var next *entity.PictureKey
+	if len(pictures) > limit {
+		pictures = pictures[:limit]
+		last := pictures[limit-1]
+		next = &entity.PictureKey{CreatedAt: last.CreatedAt, Price: prices[limit-1], ID: last.ID}
+	}
+
+	if err := r.embedGalleries(ctx, pictures); err != nil {
+		return nil, nil, err
+	}
+	return pictures, next, nil
+}
+
+// pictureSortKey returns the expression pictures are sorted by besides the ID and whether the order is descending.
+func pictureSortKey(sort entity.PictureSort, rates map[string]float64) (string, []any, bool) {
+	switch sort {
+	case entity.PictureSortPriceAsc, entity.PictureSortPriceDesc:
+		price, args := basePrice(rates)
+		return price + "::float8", args, sort == entity.PictureSortPriceDesc
+	default:
+		return "p.created_at", nil, sort != entity.PictureSortDateAsc
+	}
+}
+
+func direction(desc bool) string {
+	if desc {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+func (r *PicturesRepo) embedGalleries(ctx context.Context, pictures []entity.Picture) error {
+	for i := range pictures {
+		gallery, err := r.getPictureGallery(ctx, pictures[i].ID)
+		if err != nil {
+			return err
+		}
+		pictures[i].Gallery = gallery
+	}
+	return nil
+}
+
+// filterPictures adds the conditions of the filter to a query over "pictures p".
+func filterPictures(builder squirrel.SelectBuilder, filter entity.PictureFilter) squirrel.SelectBuilder {
+	if len(filter.IDs) > 0 {
+		builder = builder.Where(squirrel.Eq{"p.id": filter.IDs})
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, 0, len(filter.Statuses))
+		for _, status := range filter.Statuses {
+			statuses = append(statuses, string(status))
+		}
+		builder = builder.Where(squirrel.Eq{"p.status": statuses})
+	}
+	if filter.Search != "" {
+		builder = builder.Where("p.search_vector @@ "+_searchQuery, filter.Search, filter.Search)
+	}
+	if len(filter.GenreIDs) > 0 {
+		builder = builder.Where(squirrel.Eq{"p.genre_id": filter.GenreIDs})
+	}
+	if len(filter.AuthorIDs) > 0 {
+		builder = builder.Where(squirrel.Eq{"p.author_id": filter.AuthorIDs})
+	}
+	if len(filter.TechniqueIDs) > 0 {
+		builder = builder.Where(squirrel.Eq{"p.work_technique_id": filter.TechniqueIDs})
+	}
+	if len(filter.DimensionIDs) > 0 {
+		builder = builder.Where(squirrel.Eq{"p.dimensions_id": filter.DimensionIDs})
+	}
+	if !filter.Price.Empty() {
+		price, args := basePrice(filter.Rates)
+		builder = builder.Where(price+" >= ?", append(args, filter.Price.Min)...)
+		if filter.Price.Max > 0 {
+			builder = builder.Where(price+" < ?", append(args, filter.Price.Max)...)
+		}
+	}
+	return builder
+}
+
+// basePrice returns an expression converting the picture price into the base currency
+// with the given rates; currencies missing from rates are taken as the base one.
+func basePrice(rates map[string]float64) (string, []any) {
+	currencies := make([]string, 0, len(rates))
+	values := make([]float64, 0, len(rates))
+	for currency, rate := range rates {
+		currencies = append(currencies, currency)
+		values = append(values, rate)
+	}
+
+	return `(p.price * COALESCE((SELECT r.rate FROM unnest(?::text[], ?::float8[]) AS r(currency, rate)
+		WHERE r.currency = p.currency), 1))`, []any{currencies, values}
+}
+
+// GetPictureFacets runs a grouped query per facet. Each one applies the whole
+// filter except the facet's own condition, see entity.FacetValue.
+func (r *PicturesRepo) GetPictureFacets(
+	ctx context.Context,
+	filter entity.PictureFilter,
+	facets []entity.PictureFacet,
+	priceBounds []int,
+) (map[entity.PictureFacet][]entity.FacetValue, error) {
+	values := make(map[entity.PictureFacet][]entity.FacetValue, len(facets))
+
+	for _, facet := range facets {
+		others := filter
+		var builder squirrel.SelectBuilder
+
+		switch facet {
+		case entity.PictureFacetGenre:
+			others.GenreIDs = nil
+			builder = r.Builder.Select("g.id", "g.name", "COUNT(*)").From("pictures p").
+				Join("genres g ON p.genre_id = g.id").GroupBy("g.id", "g.name")
+		case entity.PictureFacetAuthor:
+			others.AuthorIDs = nil
+			builder = r.Builder.Select("a.id", "a.full_name", "COUNT(*)").From("pictures p").
+				Join("authors a ON p.author_id = a.id").GroupBy("a.id", "a.full_name")
+		case entity.PictureFacetTechnique:
+			others.TechniqueIDs = nil
+			builder = r.Builder.Select("wt.id", "wt.name", "COUNT(*)").From("pictures p").
+				Join("work_techniques wt ON p.work_technique_id = wt.id").GroupBy("wt.id", "wt.name")
+		case entity.PictureFacetDimension:
+			others.DimensionIDs = nil
+			builder = r.Builder.Select("d.id", "d.width || '×' || d.height", "COUNT(*)").From("pictures p").
+				Join("dimensions d ON p.dimensions_id = d.id").GroupBy("d.id", "d.width", "d.height")
+		case entity.PictureFacetPrice:
+			others.Price = entity.PriceRange{}
+			facetValues, err := r.getPriceFacet(ctx, others, priceBounds)
+			if err != nil {
+				return nil, err
+			}
+			values[facet] = facetValues
+			continue
+		default:
+			return nil, entity.ErrUnknownPictureFacet
+		}
+
+		sql, args, err := filterPictures(builder, others).OrderBy("COUNT(*) DESC", "2").ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("can't create sql query: %w", err)
+		}
+
+		rows, err := r.Pool.Query(ctx, sql, args...)
+		if err != nil {
+			return nil, fmt.Errorf("can't query %s facet: %w", facet, err)
+		}
+
+		facetValues := []entity.FacetValue{}
+		for rows.Next() {
+			var value entity.FacetValue
+			if err := rows.Scan(&value.ID, &value.Name, &value.Count); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("can't scan %s facet: %w", facet, err)
+			}
+			facetValues = append(facetValues, value)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("can't read %s facet: %w", facet, err)
+		}
+
+		values[facet] = facetValues
+	}
+
+	return values, nil
+}
+
+// getPriceFacet counts pictures in the price ranges between consecutive bounds,
+// below the first one and from the last one on. Empty ranges are left out.
+func (r *PicturesRepo) getPriceFacet(ctx context.Context, filter entity.PictureFilter, bounds []int) ([]entity.FacetValue, error) {
+	thresholds := make([]float64, 0, len(bounds))
+	for _, bound := range bounds {
+		thresholds = append(thresholds, float64(bound))
+	}
+
+	price, args := basePrice(filter.Rates)
+	builder := r.Builder.
+		Select().
+		Column(squirrel.Expr("width_bucket("+price+", ?::float8[]) AS bucket", append(args, thresholds)...)).
+		Column("COUNT(*)").
+		From("pictures p").
+		GroupBy("bucket").
+		OrderBy("bucket")
+
+	sql, args, err := filterPictures(builder, filter).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can't create sql query: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("can't query price facet: %w", err)
+	}
+	defer rows.Close()
+
+	values := []entity.FacetValue{}
+	for rows.Next() {
+		var bucket, count int
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return nil, fmt.Errorf("can't scan price facet: %w", err)
+		}
+
+		// width_bucket returns 0 below the first bound and len(bounds) from the last one on
+		var priceRange entity.PriceRange
+		if bucket > 0 {
+			priceRange.Min = bounds[bucket-1]
+		}
+		if bucket < len(bounds) {
+			priceRange.Max = bounds[bucket]
+		}
+		values = append(values, entity.FacetValue{Price: &priceRange, Count: count})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't read price facet: %w", err)
+	}
+
+	return values, nil
 }
 
 func (r *PicturesRepo) getPictureGallery(ctx context.Context, pictureID uint64) ([]entity.Photo, error) {
@@ -94,40 +391,29 @@ func (r *PicturesRepo) getPictureGallery(ctx context.Context, pictureID uint64)
 }
 
 func (r *PicturesRepo) GetPictureByID(ctx context.Context, id uint64) (*entity.Picture, error) {
-	sql := `
-	SELECT 
-		p.id, p.title, p.price, p.created_at,
-		a.id, a.full_name,
-		d.id, d.width, d.height,
-		wt.id, wt.name,
-		g.id, g.name,
-		pp.id, pp.url, pp.mime
-	FROM pictures p
-	JOIN authors a ON p.author_id = a.id
-	JOIN dimensions d ON p.dimensions_id = d.id
-	JOIN work_techniques wt ON p.work_technique_id = wt.id
-	JOIN genres g ON p.genre_id = g.id
-	JOIN pictures_photos pp ON p.id = pp.picture_id AND pp.is_main = true
-	WHERE p.id = $1
-	`
+	return r.getPicture(ctx, squirrel.Eq{"p.id": id})
+}
 
-	var pic entity.Picture
-	err := r.Pool.QueryRow(ctx, sql, id).Scan(
-		&pic.ID, &pic.Title, &pic.Price, &pic.CreatedAt,
-		&pic.Author.ID, &pic.Author.FullName,
-		&pic.Dimensions.ID, &pic.Dimensions.Width, &pic.Dimensions.Height,
-		&pic.WorkTechnique.ID, &pic.WorkTechnique.Name,
-		&pic.Genre.ID, &pic.Genre.Name,
-		&pic.Photo.ID, &pic.Photo.URL, &pic.Photo.Mime,
-	)
+// GetPictureBySlug finds the picture by its current or a previous slug.
+func (r *PicturesRepo) GetPictureBySlug(ctx context.Context, slug string) (*entity.Picture, error) {
+	return r.getPicture(ctx, squirrel.Expr(_pictureSlugs.match("p.id", "p.slug"), slug, slug))
+}
+
+func (r *PicturesRepo) getPicture(ctx context.Context, where squirrel.Sqlizer) (*entity.Picture, error) {
+	sql, args, err := r.selectPictures().Where(where).ToSql()
 	if err != nil {
+		return nil, fmt.Errorf("can't create sql query: %w", err)
+	}
+
+	var pic entity.Picture
+	if err := scanPicture(r.Pool.QueryRow(ctx, sql, args...), &pic); err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, entity.ErrPictureNotFound
 		}
-		return nil, fmt.Errorf("can't get picture by id: %w", err)
+		return nil, fmt.Errorf("can't get picture: %w", err)
 	}
 
-	gallery, err := r.getPictureGallery(ctx, id)
+	gallery, err := r.getPictureGallery(ctx, pic.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -136,36 +422,81 @@ func (r *PicturesRepo) GetPictureByID(ctx context.Context, id uint64) (*entity.P
 	return &pic, nil
 }
 
-func (r *PicturesRepo) CreatePicture(ctx context.Context, req entity.PictureCreateRequest) error {
+func (r *PicturesRepo) CreatePicture(
+	ctx context.Context,
+	req entity.PictureCreateRequest,
+	events ...entity.WebhookEvent,
+) (uint64, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("can't begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	slug, err := _pictureSlugs.unique(ctx, tx, req.Title, 0)
+	if err != nil {
+		return 0, err
+	}
+	if err := _pictureSlugs.claim(ctx, tx, slug); err != nil {
+		return 0, err
+	}
+
 	sql := `
-	INSERT INTO pictures (title, price, author_id, dimensions_id, work_technique_id, genre_id)
-	VALUES ($1, $2, $3, $4, $5, $6)
+	INSERT INTO pictures (slug, title, description, price, currency, author_id, dimensions_id, work_technique_id, genre_id)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	RETURNING id
 	`
 
-	_, err := r.Pool.Exec(ctx, sql,
+	var id uint64
+	err = tx.QueryRow(ctx, sql,
+		slug,
 		req.Title,
+		req.Description,
 		req.Price,
+		req.Currency,
 		req.AuthorID,
 		req.DimensionsID,
 		req.WorkTechniqueID,
 		req.GenreID,
-	)
+	).Scan(&id)
 	if err != nil {
-		return fmt.Errorf("can't create picture: %w", err)
+		return 0, fmt.Errorf("can't create picture: %w", err)
 	}
 
-	return nil
+	if err := insertPriceChange(ctx, tx, id, nil, nil, req.Price, req.Currency); err != nil {
+		return 0, err
+	}
+	if err := insertOutboxEvents(ctx, tx, id, events); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("can't commit picture: %w", err)
+	}
+
+	return id, nil
 }
 
-func (r *PicturesRepo) UpdatePicture(ctx context.Context, id uint64, req entity.PictureUpdateRequest) error {
+func (r *PicturesRepo) UpdatePicture(
+	ctx context.Context,
+	id uint64,
+	req entity.PictureUpdateRequest,
+	events ...entity.WebhookEvent,
+) error {
 	builder := r.Builder.Update("pictures")
 
 	if req.Title != nil {
 		builder = builder.Set("title", *req.Title)
 	}
+	if req.Description != nil {
+		builder = builder.Set("description", *req.Description)
+	}
 	if req.Price != nil {
 		builder = builder.Set("price", *req.Price)
 	}
+	if req.Currency != nil {
+		builder = builder.Set("currency", *req.Currency)
+	}
 	if req.AuthorID != nil {
 		builder = builder.Set("author_id", *req.AuthorID)
 	}
@@ -179,39 +510,251 @@ func (r *PicturesRepo) UpdatePicture(ctx context.Context, id uint64, req entity.
 		builder = builder.Set("genre_id", *req.GenreID)
 	}
 
-	builder = builder.Where(squirrel.Eq{"id": id})
+	builder = builder.Where(squirrel.Eq{"id": id}).Suffix("RETURNING price, currency")
 
 	sql, args, err := builder.ToSql()
 	if err != nil {
 		return fmt.Errorf("can't build update query: %w", err)
 	}
 
-	_, err = r.Pool.Exec(ctx, sql, args...)
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("can't begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// lock the row so concurrent updates can't both log the same old price
+	var oldPrice int
+	var oldCurrency string
+	err = tx.QueryRow(ctx, "SELECT price, currency FROM pictures WHERE id = $1 FOR UPDATE", id).Scan(&oldPrice, &oldCurrency)
 	if err != nil {
+		if err == pgx.ErrNoRows {
+			return entity.ErrPictureNotFound
+		}
+		return fmt.Errorf("can't get picture price: %w", err)
+	}
+
+	var newPrice int
+	var newCurrency string
+	if err := tx.QueryRow(ctx, sql, args...).Scan(&newPrice, &newCurrency); err != nil {
 		return fmt.Errorf("can't update picture: %w", err)
 	}
 
+	if newPrice != oldPrice || newCurrency != oldCurrency {
+		if err := insertPriceChange(ctx, tx, id, &oldPrice, &oldCurrency, newPrice, newCurrency); err != nil {
+			return err
+		}
+	}
+	if req.Title != nil {
+		if err := _pictureSlugs.rename(ctx, tx, id, *req.Title); err != nil {
+			return err
+		}
+	}
+	if err := insertOutboxEvents(ctx, tx, id, events); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("can't commit picture: %w", err)
+	}
+
 	return nil
 }
 
-func (r *PicturesRepo) DeletePicture(ctx context.Context, id uint64) error {
-	sql := "DELETE FROM pictures WHERE id = $1"
+func insertPriceChange(
+	ctx context.Context,
+	tx pgx.Tx,
+	pictureID uint64,
+	oldPrice *int, oldCurrency *string,
+	newPrice int, newCurrency string,
+) error {
+	sql := `
+	INSERT INTO price_history (picture_id, old_price, old_currency, new_price, new_currency)
+	VALUES ($1, $2, $3, $4, $5)
+	`
+
+	if _, err := tx.Exec(ctx, sql, pictureID, oldPrice, oldCurrency, newPrice, newCurrency); err != nil {
+		return fmt.Errorf("can't save price change: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PicturesRepo) GetPriceHistory(ctx context.Context, id uint64) ([]entity.PriceChange, error) {
+	sql := `
+	SELECT id, picture_id, old_price, old_currency, new_price, new_currency, changed_at
+	FROM price_history
+	WHERE picture_id = $1
+	ORDER BY changed_at DESC, id DESC
+	`
+
+	rows, err := r.Pool.Query(ctx, sql, id)
+	if err != nil {
+		return nil, fmt.Errorf("can't query price history: %w", err)
+	}
+	defer rows.Close()
+
+	history := make([]entity.PriceChange, 0, _defaultListCap)
+	for rows.Next() {
+		var c entity.PriceChange
+		err := rows.Scan(&c.ID, &c.PictureID, &c.OldPrice, &c.OldCurrency, &c.NewPrice, &c.NewCurrency, &c.ChangedAt)
+		if err != nil {
+			return nil, fmt.Errorf("can't scan price change: %w", err)
+		}
+		history = append(history, c)
+	}
+
+	return history, nil
+}
 
-	_, err := r.Pool.Exec(ctx, sql, id)
+func (r *PicturesRepo) DeletePicture(ctx context.Context, id uint64, events ...entity.WebhookEvent) error {
+	tx, err := r.Pool.Begin(ctx)
 	if err != nil {
+		return fmt.Errorf("can't begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// the snapshot has to be taken while the row still exists
+	if err := insertOutboxEvents(ctx, tx, id, events); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM pictures WHERE id = $1", id); err != nil {
 		return fmt.Errorf("can't delete picture: %w", err)
 	}
 
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("can't commit picture deletion: %w", err)
+	}
+
+	return nil
+}
+
+// insertOutboxEvents queues catalog webhook events for the picture in the same
+// transaction as the change, each with a snapshot of the picture row.
+func insertOutboxEvents(ctx context.Context, tx pgx.Tx, pictureID uint64, events []entity.WebhookEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(events))
+	for i, event := range events {
+		names[i] = string(event)
+	}
+
+	sql := `
+	INSERT INTO webhook_outbox (event, picture_id, payload)
+	SELECT e.event, p.id, jsonb_build_object(
+		'id', p.id,
+		'title', p.title,
+		'price', p.price,
+		'currency', p.currency,
+		'status', p.status,
+		'author_id', p.author_id,
+		'genre_id', p.genre_id,
+		'work_technique_id', p.work_technique_id,
+		'dimensions_id', p.dimensions_id
+	)
+	FROM pictures p, unnest($2::text[]) WITH ORDINALITY AS e(event, n)
+	WHERE p.id = $1
+	ORDER BY e.n
+	`
+
+	if _, err := tx.Exec(ctx, sql, pictureID, names); err != nil {
+		return fmt.Errorf("can't save webhook events: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PicturesRepo) UpdatePictureStatus(
+	ctx context.Context,
+	id uint64,
+	from, to entity.PictureStatus,
+	override bool,
+	events ...entity.WebhookEvent,
+) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("can't begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+	UPDATE pictures SET status = $1, status_changed_at = NOW()
+	WHERE id = $2 AND status = $3
+	`, to, id, from)
+	if err != nil {
+		return fmt.Errorf("can't update picture status: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrPictureStatusConflict
+	}
+
+	_, err = tx.Exec(ctx, `
+	INSERT INTO picture_status_history (picture_id, from_status, to_status, override)
+	VALUES ($1, $2, $3, $4)
+	`, id, from, to, override)
+	if err != nil {
+		return fmt.Errorf("can't save picture status transition: %w", err)
+	}
+	if err := insertOutboxEvents(ctx, tx, id, events); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("can't commit picture status: %w", err)
+	}
+
 	return nil
 }
 
+func (r *PicturesRepo) GetPictureStatusHistory(ctx context.Context, id uint64) ([]entity.PictureStatusTransition, error) {
+	sql := `
+	SELECT id, picture_id, from_status, to_status, override, changed_at
+	FROM picture_status_history
+	WHERE picture_id = $1
+	ORDER BY changed_at DESC, id DESC
+	`
+
+	rows, err := r.Pool.Query(ctx, sql, id)
+	if err != nil {
+		return nil, fmt.Errorf("can't query picture status history: %w", err)
+	}
+	defer rows.Close()
+
+	history := make([]entity.PictureStatusTransition, 0, _defaultListCap)
+	for rows.Next() {
+		var t entity.PictureStatusTransition
+		if err := rows.Scan(&t.ID, &t.PictureID, &t.FromStatus, &t.ToStatus, &t.Override, &t.ChangedAt); err != nil {
+			return nil, fmt.Errorf("can't scan picture status transition: %w", err)
+		}
+		history = append(history, t)
+	}
+
+	return history, nil
+}
+
 func (r *PicturesRepo) SavePhoto(
 	ctx context.Context,
 	pictureID uint64,
 	url, mime string,
 	isMain bool,
 ) (uint64, error) {
-	fmt.Println(pictureID, url, mime, isMain)
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("can't begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// a new main photo moves the previous one to the gallery
+	if isMain {
+		_, err := tx.Exec(ctx, "UPDATE pictures_photos SET is_main = false WHERE picture_id = $1 AND is_main = true", pictureID)
+		if err != nil {
+			return 0, fmt.Errorf("can't reset main photo: %w", err)
+		}
+	}
+
 	sql := `
 	INSERT INTO pictures_photos (picture_id, url, mime, is_main)
 	VALUES ($1, $2, $3, $4)
@@ -219,11 +762,15 @@ func (r *PicturesRepo) SavePhoto(
 	`
 
 	var id uint64
-	err := r.Pool.QueryRow(ctx, sql, pictureID, url, mime, isMain).Scan(&id)
+	err = tx.QueryRow(ctx, sql, pictureID, url, mime, isMain).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("can't save photo: %w", err)
 	}
 
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("can't commit photo: %w", err)
+	}
+
 	return id, nil
 }
 
@@ -7,6 +7,7 @@ import (
 	"github.com/Masterminds/squirrel"
 	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
 	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/postgres"
+	"github.com/jackc/pgx/v5"
 )
 
 var (
@@ -45,21 +46,23 @@ func (r *ReferencesRepo) GetGenres(ctx context.Context) ([]entity.Genre, error)
 	return genres, nil
 }
 
-func (r *ReferencesRepo) CreateGenre(ctx context.Context, name string) error {
+func (r *ReferencesRepo) CreateGenre(ctx context.Context, name string) (uint64, error) {
 	query, args, err := r.Builder.
 		Insert("genres").
 		Columns("name").
 		Values(name).
+		Suffix("RETURNING id").
 		ToSql()
 	if err != nil {
-		return fmt.Errorf("can't create sql query: %w", err)
+		return 0, fmt.Errorf("can't create sql query: %w", err)
 	}
 
-	if _, err = r.Pool.Exec(ctx, query, args...); err != nil {
-		return fmt.Errorf("can't insert genre: %w", err)
+	var id uint64
+	if err = r.Pool.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		return 0, fmt.Errorf("can't insert genre: %w", err)
 	}
 
-	return nil
+	return id, nil
 }
 
 func (r *ReferencesRepo) DeleteGenre(ctx context.Context, id uint64) error {
@@ -79,7 +82,7 @@ func (r *ReferencesRepo) DeleteGenre(ctx context.Context, id uint64) error {
 }
 
 func (r *ReferencesRepo) GetAuthors(ctx context.Context) ([]entity.Author, error) {
-	query, _, err := r.Builder.Select("id", "full_name").From("authors").ToSql()
+	query, _, err := r.Builder.Select("id", "slug", "full_name").From("authors").ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("can't create sql query: %w", err)
 	}
@@ -93,7 +96,7 @@ func (r *ReferencesRepo) GetAuthors(ctx context.Context) ([]entity.Author, error
 	authors := make([]entity.Author, 0, _defaultListCap)
 	for rows.Next() {
 		var author entity.Author
-		if err := rows.Scan(&author.ID, &author.FullName); err != nil {
+		if err := rows.Scan(&author.ID, &author.Slug, &author.FullName); err != nil {
 			return nil, fmt.Errorf("can't scan row: %w", err)
 		}
 		authors = append(authors, author)
@@ -102,21 +105,67 @@ func (r *ReferencesRepo) GetAuthors(ctx context.Context) ([]entity.Author, error
 	return authors, nil
 }
 
-func (r *ReferencesRepo) CreateAuthor(ctx context.Context, fullName string) error {
+func (r *ReferencesRepo) GetAuthorByID(ctx context.Context, id uint64) (*entity.Author, error) {
+	return r.getAuthor(ctx, squirrel.Eq{"id": id})
+}
+
+// GetAuthorBySlug finds the author by the current or a previous slug.
+func (r *ReferencesRepo) GetAuthorBySlug(ctx context.Context, slug string) (*entity.Author, error) {
+	return r.getAuthor(ctx, squirrel.Expr(_authorSlugs.match("id", "slug"), slug, slug))
+}
+
+func (r *ReferencesRepo) getAuthor(ctx context.Context, where squirrel.Sqlizer) (*entity.Author, error) {
+	query, args, err := r.Builder.Select("id", "slug", "full_name").From("authors").Where(where).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can't create sql query: %w", err)
+	}
+
+	var author entity.Author
+	if err := r.Pool.QueryRow(ctx, query, args...).Scan(&author.ID, &author.Slug, &author.FullName); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, entity.ErrAuthorNotFound
+		}
+		return nil, fmt.Errorf("can't get author: %w", err)
+	}
+
+	return &author, nil
+}
+
+func (r *ReferencesRepo) CreateAuthor(ctx context.Context, fullName string) (uint64, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("can't begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	slug, err := _authorSlugs.unique(ctx, tx, fullName, 0)
+	if err != nil {
+		return 0, err
+	}
+	if err := _authorSlugs.claim(ctx, tx, slug); err != nil {
+		return 0, err
+	}
+
 	query, args, err := r.Builder.
 		Insert("authors").
-		Columns("full_name").
-		Values(fullName).
+		Columns("slug", "full_name").
+		Values(slug, fullName).
+		Suffix("RETURNING id").
 		ToSql()
 	if err != nil {
-		return fmt.Errorf("can't create sql query: %w", err)
+		return 0, fmt.Errorf("can't create sql query: %w", err)
 	}
 
-	if _, err = r.Pool.Exec(ctx, query, args...); err != nil {
-		return fmt.Errorf("can't insert author: %w", err)
+	var id uint64
+	if err = tx.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		return 0, fmt.Errorf("can't insert author: %w", err)
 	}
 
-	return nil
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("can't commit author: %w", err)
+	}
+
+	return id, nil
 }
 
 func (r *ReferencesRepo) DeleteAuthor(ctx context.Context, id uint64) error {
@@ -159,21 +208,23 @@ func (r *ReferencesRepo) GetDimensions(ctx context.Context) ([]entity.Dimension,
 	return dimensions, nil
 }
 
-func (r *ReferencesRepo) CreateDimension(ctx context.Context, width, height int) error {
+func (r *ReferencesRepo) CreateDimension(ctx context.Context, width, height int) (uint64, error) {
 	query, args, err := r.Builder.
 		Insert("dimensions").
 		Columns("width", "height").
 		Values(width, height).
+		Suffix("RETURNING id").
 		ToSql()
 	if err != nil {
-		return fmt.Errorf("can't create sql query: %w", err)
+		return 0, fmt.Errorf("can't create sql query: %w", err)
 	}
 
-	if _, err = r.Pool.Exec(ctx, query, args...); err != nil {
-		return fmt.Errorf("can't insert dimension: %w", err)
+	var id uint64
+	if err = r.Pool.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		return 0, fmt.Errorf("can't insert dimension: %w", err)
 	}
 
-	return nil
+	return id, nil
 }
 
 func (r *ReferencesRepo) DeleteDimension(ctx context.Context, id uint64) error {
@@ -216,21 +267,23 @@ func (r *ReferencesRepo) GetWorkTechniques(ctx context.Context) ([]entity.WorkTe
 	return techniques, nil
 }
 
-func (r *ReferencesRepo) CreateWorkTechnique(ctx context.Context, name string) error {
+func (r *ReferencesRepo) CreateWorkTechnique(ctx context.Context, name string) (uint64, error) {
 	query, args, err := r.Builder.
 		Insert("work_techniques").
 		Columns("name").
 		Values(name).
+		Suffix("RETURNING id").
 		ToSql()
 	if err != nil {
-		return fmt.Errorf("can't create sql query: %w", err)
+		return 0, fmt.Errorf("can't create sql query: %w", err)
 	}
 
-	if _, err = r.Pool.Exec(ctx, query, args...); err != nil {
-		return fmt.Errorf("can't insert work technique: %w", err)
+	var id uint64
+	if err = r.Pool.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		return 0, fmt.Errorf("can't insert work technique: %w", err)
 	}
 
-	return nil
+	return id, nil
 }
 
 func (r *ReferencesRepo) DeleteWorkTechnique(ctx context.Context, id uint64) error {
@@ -0,0 +1,108 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type ReservationsRepo struct {
+	*postgres.Postgres
+}
+
+func NewReservationsRepo(pg *postgres.Postgres) *ReservationsRepo {
+	return &ReservationsRepo{pg}
+}
+
+func (r *ReservationsRepo) CreateReservation(ctx context.Context, reservation entity.Reservation) (uint64, error) {
+	sql := `
+	INSERT INTO reservations (picture_id, hold_until, note)
+	VALUES ($1, $2, $3)
+	RETURNING id
+	`
+
+	var id uint64
+	err := r.Pool.QueryRow(ctx, sql, reservation.PictureID, reservation.HoldUntil, reservation.Note).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("can't create reservation: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *ReservationsRepo) GetReservations(ctx context.Context, pictureID uint64) ([]entity.Reservation, error) {
+	sql := `
+	SELECT id, picture_id, hold_until, note, released_at, created_at
+	FROM reservations
+	WHERE picture_id = $1
+	ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := r.Pool.Query(ctx, sql, pictureID)
+	if err != nil {
+		return nil, fmt.Errorf("can't query reservations: %w", err)
+	}
+
+	return scanReservations(rows)
+}
+
+func (r *ReservationsRepo) GetExpiredReservations(ctx context.Context, now time.Time) ([]entity.Reservation, error) {
+	sql := `
+	SELECT id, picture_id, hold_until, note, released_at, created_at
+	FROM reservations
+	WHERE released_at IS NULL AND hold_until <= $1
+	ORDER BY hold_until
+	`
+
+	rows, err := r.Pool.Query(ctx, sql, now)
+	if err != nil {
+		return nil, fmt.Errorf("can't query expired reservations: %w", err)
+	}
+
+	return scanReservations(rows)
+}
+
+func scanReservations(rows pgx.Rows) ([]entity.Reservation, error) {
+	defer rows.Close()
+
+	reservations := make([]entity.Reservation, 0, _defaultListCap)
+	for rows.Next() {
+		var res entity.Reservation
+		err := rows.Scan(&res.ID, &res.PictureID, &res.HoldUntil, &res.Note, &res.ReleasedAt, &res.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("can't scan reservation: %w", err)
+		}
+		reservations = append(reservations, res)
+	}
+
+	return reservations, nil
+}
+
+func (r *ReservationsRepo) ReleaseReservation(ctx context.Context, id uint64) error {
+	sql := "UPDATE reservations SET released_at = NOW() WHERE id = $1 AND released_at IS NULL"
+
+	tag, err := r.Pool.Exec(ctx, sql, id)
+	if err != nil {
+		return fmt.Errorf("can't release reservation: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrReservationNotFound
+	}
+
+	return nil
+}
+
+func (r *ReservationsRepo) CountActiveReservations(ctx context.Context, pictureID uint64) (int, error) {
+	sql := "SELECT COUNT(*) FROM reservations WHERE picture_id = $1 AND released_at IS NULL AND hold_until > NOW()"
+
+	var count int
+	if err := r.Pool.QueryRow(ctx, sql, pictureID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("can't count active reservations: %w", err)
+	}
+
+	return count, nil
+}
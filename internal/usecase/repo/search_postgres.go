@@ -0,0 +1,148 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/postgres"
+)
+
+// _searchQuery turns a query in either language into a tsquery matching the
+// search_vector columns, which hold both Russian and English lexemes.
+const _searchQuery = "(websearch_to_tsquery('russian', ?) || websearch_to_tsquery('english', ?))"
+
+// ts_headline doesn't escape the text, so matches are marked with private use
+// characters and turned into <mark> after escaping.
+const (
+	_markStart = "\ue000"
+	_markStop  = "\ue001"
+)
+
+type SearchRepo struct {
+	*postgres.Postgres
+}
+
+func NewSearchRepo(pg *postgres.Postgres) *SearchRepo {
+	return &SearchRepo{pg}
+}
+
+// Search finds pictures, authors, genres, techniques and news visible at the moment now, best first.
+func (r *SearchRepo) Search(ctx context.Context, q string, limit int, now time.Time) ([]entity.SearchHit, error) {
+	const query = `
+	WITH q AS (
+		SELECT websearch_to_tsquery('russian', $1) || websearch_to_tsquery('english', $1) AS query
+	)
+	SELECT type, id, slug, title, snippet, rank FROM (
+		SELECT 'picture' AS type, p.id, p.slug, p.title,
+			ts_headline('russian', CASE WHEN p.description <> '' THEN p.description ELSE p.title END, q.query, $3) AS snippet,
+			ts_rank_cd(p.search_vector, q.query)::float8 AS rank
+		FROM pictures p, q WHERE p.search_vector @@ q.query
+		UNION ALL
+		SELECT 'author', a.id, a.slug, a.full_name,
+			ts_headline('russian', a.full_name, q.query, $3),
+			ts_rank_cd(a.search_vector, q.query)::float8
+		FROM authors a, q WHERE a.search_vector @@ q.query
+		UNION ALL
+		SELECT 'genre', g.id, '', g.name,
+			ts_headline('russian', g.name, q.query, $3),
+			ts_rank_cd(g.search_vector, q.query)::float8
+		FROM genres g, q WHERE g.search_vector @@ q.query
+		UNION ALL
+		SELECT 'technique', wt.id, '', wt.name,
+			ts_headline('russian', wt.name, q.query, $3),
+			ts_rank_cd(wt.search_vector, q.query)::float8
+		FROM work_techniques wt, q WHERE wt.search_vector @@ q.query
+		UNION ALL
+		SELECT 'news', n.id, n.slug, n.title,
+			ts_headline('russian', n.content, q.query, $3),
+			ts_rank_cd(n.search_vector, q.query)::float8
+		FROM news n, q WHERE n.search_vector @@ q.query
+			AND n.status IN ($4, $5) AND n.publish_at <= $6
+	) hits
+	ORDER BY rank DESC, type, id
+	LIMIT $2
+	`
+
+	options := fmt.Sprintf("StartSel=%s, StopSel=%s, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=\" … \"",
+		_markStart, _markStop)
+
+	rows, err := r.Pool.Query(ctx, query, q, limit, options,
+		entity.NewsStatusPublished, entity.NewsStatusScheduled, now)
+	if err != nil {
+		return nil, fmt.Errorf("can't query search: %w", err)
+	}
+	defer rows.Close()
+
+	hits := make([]entity.SearchHit, 0, limit)
+	for rows.Next() {
+		var hit entity.SearchHit
+		if err := rows.Scan(&hit.Type, &hit.ID, &hit.Slug, &hit.Title, &hit.Snippet, &hit.Rank); err != nil {
+			return nil, fmt.Errorf("can't scan search hit: %w", err)
+		}
+		hit.Snippet = highlight(hit.Snippet)
+		hits = append(hits, hit)
+	}
+
+	return hits, rows.Err()
+}
+
+func highlight(snippet string) string {
+	return strings.NewReplacer(_markStart, "<mark>", _markStop, "</mark>").Replace(html.EscapeString(snippet))
+}
+
+// _suggestThreshold is lower than the pg_trgm default of 0.6, so that a couple
+// of typos in a short input still find the word.
+const _suggestThreshold = "0.4"
+
+// Suggest finds picture titles, author names and genres containing a word similar
+// to q, most similar first. The trigram indexes serve the <% operator.
+func (r *SearchRepo) Suggest(ctx context.Context, q string, limit int) ([]entity.SearchSuggestion, error) {
+	const query = `
+	SELECT type, id, slug, text, score FROM (
+		SELECT 'picture' AS type, p.id, p.slug, p.title AS text, word_similarity($1, p.title)::float8 AS score
+		FROM pictures p WHERE $1 <% p.title
+		UNION ALL
+		SELECT 'author', a.id, a.slug, a.full_name, word_similarity($1, a.full_name)::float8
+		FROM authors a WHERE $1 <% a.full_name
+		UNION ALL
+		SELECT 'genre', g.id, '', g.name, word_similarity($1, g.name)::float8
+		FROM genres g WHERE $1 <% g.name
+	) suggestions
+	ORDER BY score DESC, length(text), text
+	LIMIT $2
+	`
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)", _suggestThreshold); err != nil {
+		return nil, fmt.Errorf("can't set similarity threshold: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, query, q, limit)
+	if err != nil {
+		return nil, fmt.Errorf("can't query suggestions: %w", err)
+	}
+	defer rows.Close()
+
+	suggestions := make([]entity.SearchSuggestion, 0, limit)
+	for rows.Next() {
+		var s entity.SearchSuggestion
+		if err := rows.Scan(&s.Type, &s.ID, &s.Slug, &s.Text, &s.Score); err != nil {
+			return nil, fmt.Errorf("can't scan suggestion: %w", err)
+		}
+		suggestions = append(suggestions, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("can't read suggestions: %w", err)
+	}
+
+	return suggestions, tx.Commit(ctx)
+}
@@ -0,0 +1,73 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+type SessionsRepo struct {
+	*postgres.Postgres
+}
+
+func NewSessionsRepo(pg *postgres.Postgres) *SessionsRepo {
+	return &SessionsRepo{pg}
+}
+
+func (r *SessionsRepo) CreateSession(ctx context.Context, session entity.AdminSession, tokenHash string) (*entity.AdminSession, error) {
+	query, args, err := r.Builder.
+		Insert("admin_sessions").
+		Columns("token_hash", "csrf_token", "ip", "user_agent", "expires_at").
+		Values(tokenHash, session.CSRFToken, session.IP, session.UserAgent, session.ExpiresAt).
+		Suffix("RETURNING id, created_at").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can't create sql query: %w", err)
+	}
+
+	if err := r.Pool.QueryRow(ctx, query, args...).Scan(&session.ID, &session.CreatedAt); err != nil {
+		return nil, fmt.Errorf("can't insert session: %w", err)
+	}
+
+	return &session, nil
+}
+
+func (r *SessionsRepo) GetSessionByHash(ctx context.Context, tokenHash string) (*entity.AdminSession, error) {
+	query, args, err := r.Builder.
+		Select("id", "csrf_token", "ip", "user_agent", "expires_at", "created_at").
+		From("admin_sessions").
+		Where(squirrel.Eq{"token_hash": tokenHash}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can't create sql query: %w", err)
+	}
+
+	var s entity.AdminSession
+	err = r.Pool.QueryRow(ctx, query, args...).Scan(&s.ID, &s.CSRFToken, &s.IP, &s.UserAgent, &s.ExpiresAt, &s.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, entity.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("can't scan row: %w", err)
+	}
+
+	return &s, nil
+}
+
+func (r *SessionsRepo) DeleteSessionByHash(ctx context.Context, tokenHash string) error {
+	if _, err := r.Pool.Exec(ctx, "DELETE FROM admin_sessions WHERE token_hash = $1", tokenHash); err != nil {
+		return fmt.Errorf("can't delete session: %w", err)
+	}
+	return nil
+}
+
+func (r *SessionsRepo) DeleteExpiredSessions(ctx context.Context) error {
+	if _, err := r.Pool.Exec(ctx, "DELETE FROM admin_sessions WHERE expires_at < NOW()"); err != nil {
+		return fmt.Errorf("can't delete expired sessions: %w", err)
+	}
+	return nil
+}
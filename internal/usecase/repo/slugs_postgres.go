@@ -0,0 +1,124 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/slug"
+	"github.com/jackc/pgx/v5"
+)
+
+// slugScope is a table whose rows have slugs. Previous slugs are kept in
+// slug_history under the table name, so old URLs can be redirected.
+type slugScope struct {
+	table string
+	// kind replaces slugs that are empty or look like IDs
+	kind string
+}
+
+var (
+	_pictureSlugs = slugScope{table: "pictures", kind: "picture"}
+	_authorSlugs  = slugScope{table: "authors", kind: "author"}
+	_newsSlugs    = slugScope{table: "news", kind: "news"}
+)
+
+// unique makes a slug from title that no other row of the table has now or had
+// before. Clashes get a numeric suffix: "zakat", "zakat-2", "zakat-3"...
+// Pass id 0 for a row that doesn't exist yet.
+//
+// Slug allocation is serialized per table with a transaction-level advisory lock,
+// so concurrent creates and renames with the same title can't pick the same slug.
+func (s slugScope) unique(ctx context.Context, tx pgx.Tx, title string, id uint64) (string, error) {
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", "slugs:"+s.table); err != nil {
+		return "", fmt.Errorf("can't lock slugs: %w", err)
+	}
+
+	base := slug.Make(title)
+	switch {
+	case base == "":
+		base = s.kind
+	case slug.Numeric(base):
+		base = s.kind + "-" + base
+	}
+
+	// slugs have no LIKE wildcards, so base is safe to use as a prefix pattern
+	query := fmt.Sprintf(`
+	SELECT slug FROM %[1]s WHERE id <> $2 AND (slug = $1 OR slug LIKE $1 || '-%%')
+	UNION
+	SELECT h.slug FROM slug_history h JOIN %[1]s t ON t.id = h.entity_id
+	WHERE h.entity_type = $3 AND h.entity_id <> $2 AND (h.slug = $1 OR h.slug LIKE $1 || '-%%')
+	`, s.table)
+
+	rows, err := tx.Query(ctx, query, base, id, s.table)
+	if err != nil {
+		return "", fmt.Errorf("can't query taken slugs: %w", err)
+	}
+	defer rows.Close()
+
+	taken := make(map[string]bool)
+	for rows.Next() {
+		var existing string
+		if err := rows.Scan(&existing); err != nil {
+			return "", fmt.Errorf("can't scan slug: %w", err)
+		}
+		taken[existing] = true
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("can't read taken slugs: %w", err)
+	}
+
+	candidate := base
+	for n := 2; taken[candidate]; n++ {
+		candidate = fmt.Sprintf("%s-%d", base, n)
+	}
+	return candidate, nil
+}
+
+// claim drops a history entry left by a deleted row, so the slug points to its new owner only.
+func (s slugScope) claim(ctx context.Context, tx pgx.Tx, newSlug string) error {
+	if _, err := tx.Exec(ctx, "DELETE FROM slug_history WHERE entity_type = $1 AND slug = $2", s.table, newSlug); err != nil {
+		return fmt.Errorf("can't clear slug history: %w", err)
+	}
+	return nil
+}
+
+// rename gives the row a slug made from its new title and moves the current
+// one to the history. The row must be locked by the caller's transaction.
+func (s slugScope) rename(ctx context.Context, tx pgx.Tx, id uint64, title string) error {
+	var current string
+	if err := tx.QueryRow(ctx, fmt.Sprintf("SELECT slug FROM %s WHERE id = $1", s.table), id).Scan(&current); err != nil {
+		return fmt.Errorf("can't get slug: %w", err)
+	}
+
+	newSlug, err := s.unique(ctx, tx, title, id)
+	if err != nil {
+		return err
+	}
+	if newSlug == current {
+		return nil
+	}
+
+	if err := s.claim(ctx, tx, newSlug); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+	INSERT INTO slug_history (entity_type, slug, entity_id) VALUES ($1, $2, $3)
+	ON CONFLICT (entity_type, slug) DO UPDATE SET entity_id = EXCLUDED.entity_id, created_at = NOW()
+	`, s.table, current, id)
+	if err != nil {
+		return fmt.Errorf("can't save slug history: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("UPDATE %s SET slug = $1 WHERE id = $2", s.table), newSlug, id); err != nil {
+		return fmt.Errorf("can't update slug: %w", err)
+	}
+	return nil
+}
+
+// match returns a condition on the row ID column that selects the row having
+// the slug now or having had it before; args are the slug twice.
+func (s slugScope) match(idColumn, slugColumn string) string {
+	return fmt.Sprintf("(%s = ? OR %s IN (SELECT entity_id FROM slug_history WHERE entity_type = '%s' AND slug = ?))",
+		slugColumn, idColumn, s.table)
+}
@@ -0,0 +1,294 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/postgres"
+	"github.com/jackc/pgx/v5"
+)
+
+// _maxDeliveryErrorLength keeps the delivery log readable when a receiver answers with a long error.
+const _maxDeliveryErrorLength = 1000
+
+type WebhooksRepo struct {
+	*postgres.Postgres
+}
+
+func NewWebhooksRepo(pg *postgres.Postgres) *WebhooksRepo {
+	return &WebhooksRepo{pg}
+}
+
+func webhookEventNames(events []entity.WebhookEvent) []string {
+	names := make([]string, len(events))
+	for i, event := range events {
+		names[i] = string(event)
+	}
+	return names
+}
+
+func (r *WebhooksRepo) GetSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	sql := "SELECT id, url, secret, events, active, created_at FROM webhook_subscriptions ORDER BY id"
+
+	rows, err := r.Pool.Query(ctx, sql)
+	if err != nil {
+		return nil, fmt.Errorf("can't query webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]entity.WebhookSubscription, 0, _defaultListCap)
+	for rows.Next() {
+		var s entity.WebhookSubscription
+		var events []string
+		if err := rows.Scan(&s.ID, &s.URL, &s.Secret, &events, &s.Active, &s.CreatedAt); err != nil {
+			return nil, fmt.Errorf("can't scan webhook subscription: %w", err)
+		}
+		for _, event := range events {
+			s.Events = append(s.Events, entity.WebhookEvent(event))
+		}
+		subscriptions = append(subscriptions, s)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+func (r *WebhooksRepo) CreateSubscription(ctx context.Context, s entity.WebhookSubscription) (uint64, error) {
+	sql := `
+	INSERT INTO webhook_subscriptions (url, secret, events, active)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id
+	`
+
+	var id uint64
+	if err := r.Pool.QueryRow(ctx, sql, s.URL, s.Secret, webhookEventNames(s.Events), s.Active).Scan(&id); err != nil {
+		return 0, fmt.Errorf("can't create webhook subscription: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *WebhooksRepo) UpdateSubscription(ctx context.Context, id uint64, req entity.WebhookSubscriptionUpdateRequest) error {
+	builder := r.Builder.Update("webhook_subscriptions").Where(squirrel.Eq{"id": id})
+
+	if req.URL != nil {
+		builder = builder.Set("url", *req.URL)
+	}
+	if req.Secret != nil {
+		builder = builder.Set("secret", *req.Secret)
+	}
+	if req.Events != nil {
+		builder = builder.Set("events", webhookEventNames(req.Events))
+	}
+	if req.Active != nil {
+		builder = builder.Set("active", *req.Active)
+	}
+	// keeps the statement valid when nothing is set, and still detects a missing row
+	builder = builder.Set("id", squirrel.Expr("id"))
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("can't build update query: %w", err)
+	}
+
+	tag, err := r.Pool.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("can't update webhook subscription: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (r *WebhooksRepo) DeleteSubscription(ctx context.Context, id uint64) error {
+	tag, err := r.Pool.Exec(ctx, "DELETE FROM webhook_subscriptions WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("can't delete webhook subscription: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return entity.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (r *WebhooksRepo) GetDeliveries(ctx context.Context, filter entity.WebhookDeliveryFilter) ([]entity.WebhookDelivery, error) {
+	builder := r.Builder.
+		Select(
+			"d.id", "d.subscription_id", "d.outbox_id", "o.event", "o.picture_id", "d.status", "d.attempts",
+			"d.response_status", "d.error", "d.next_attempt_at", "d.delivered_at", "d.created_at",
+		).
+		From("webhook_deliveries d").
+		Join("webhook_outbox o ON o.id = d.outbox_id").
+		OrderBy("d.id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset)
+
+	if filter.SubscriptionID != 0 {
+		builder = builder.Where(squirrel.Eq{"d.subscription_id": filter.SubscriptionID})
+	}
+	if filter.Status != "" {
+		builder = builder.Where(squirrel.Eq{"d.status": filter.Status})
+	}
+	if filter.Event != "" {
+		builder = builder.Where(squirrel.Eq{"o.event": filter.Event})
+	}
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("can't create sql query: %w", err)
+	}
+
+	rows, err := r.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("can't query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]entity.WebhookDelivery, 0, _defaultListCap)
+	for rows.Next() {
+		var d entity.WebhookDelivery
+		err := rows.Scan(
+			&d.ID, &d.SubscriptionID, &d.EventID, &d.Event, &d.PictureID, &d.Status, &d.Attempts,
+			&d.ResponseStatus, &d.Error, &d.NextAttemptAt, &d.DeliveredAt, &d.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("can't scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+func (r *WebhooksRepo) FanOutOutbox(ctx context.Context, limit int) (int, error) {
+	// one statement, so an event is either fanned out and marked processed or left for the next run
+	sql := `
+	WITH batch AS (
+		SELECT id, event FROM webhook_outbox
+		WHERE processed_at IS NULL
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	), queued AS (
+		INSERT INTO webhook_deliveries (subscription_id, outbox_id)
+		SELECT s.id, b.id
+		FROM batch b
+		JOIN webhook_subscriptions s ON s.active AND b.event = ANY(s.events)
+		ON CONFLICT DO NOTHING
+	)
+	UPDATE webhook_outbox SET processed_at = NOW()
+	WHERE id IN (SELECT id FROM batch)
+	`
+
+	tag, err := r.Pool.Exec(ctx, sql, limit)
+	if err != nil {
+		return 0, fmt.Errorf("can't fan out webhook outbox: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+func (r *WebhooksRepo) ClaimDueDeliveries(
+	ctx context.Context,
+	now time.Time,
+	lease time.Duration,
+	limit int,
+) ([]entity.WebhookDispatch, error) {
+	sql := `
+	WITH due AS (
+		SELECT id FROM webhook_deliveries
+		WHERE status = 'pending' AND next_attempt_at <= $1
+		ORDER BY next_attempt_at
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
+	)
+	UPDATE webhook_deliveries d SET next_attempt_at = $2
+	FROM due, webhook_subscriptions s, webhook_outbox o
+	WHERE d.id = due.id AND s.id = d.subscription_id AND o.id = d.outbox_id
+	RETURNING d.id, d.attempts, s.url, s.secret, o.id, o.event, o.created_at, o.payload
+	`
+
+	rows, err := r.Pool.Query(ctx, sql, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("can't claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	dispatches := make([]entity.WebhookDispatch, 0, limit)
+	for rows.Next() {
+		var d entity.WebhookDispatch
+		err := rows.Scan(
+			&d.DeliveryID, &d.Attempts, &d.URL, &d.Secret,
+			&d.Payload.EventID, &d.Payload.Event, &d.Payload.OccurredAt, &d.Payload.Data,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("can't scan webhook delivery: %w", err)
+		}
+		dispatches = append(dispatches, d)
+	}
+
+	return dispatches, rows.Err()
+}
+
+func (r *WebhooksRepo) RecordDeliveryAttempt(
+	ctx context.Context,
+	id uint64,
+	result entity.WebhookResult,
+	nextAttemptAt *time.Time,
+) error {
+	status := entity.WebhookDeliveryDelivered
+	var deliveredAt *time.Time
+	var message string
+
+	switch {
+	case result.Err == nil:
+		now := time.Now()
+		deliveredAt = &now
+		nextAttemptAt = nil
+	case nextAttemptAt == nil:
+		status = entity.WebhookDeliveryFailed
+	default:
+		status = entity.WebhookDeliveryPending
+	}
+	if result.Err != nil {
+		message = result.Err.Error()
+		if len(message) > _maxDeliveryErrorLength {
+			message = message[:_maxDeliveryErrorLength]
+		}
+	}
+
+	sql := `
+	UPDATE webhook_deliveries
+	SET status = $2, attempts = attempts + 1, response_status = $3, error = $4,
+		next_attempt_at = $5, delivered_at = $6
+	WHERE id = $1
+	`
+
+	if _, err := r.Pool.Exec(ctx, sql, id, status, result.ResponseStatus, message, nextAttemptAt, deliveredAt); err != nil {
+		return fmt.Errorf("can't record webhook delivery attempt: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WebhooksRepo) RetryDelivery(ctx context.Context, id uint64) error {
+	sql := `
+	UPDATE webhook_deliveries SET status = 'pending', next_attempt_at = NOW()
+	WHERE id = $1 AND status = 'failed'
+	RETURNING id
+	`
+
+	if err := r.Pool.QueryRow(ctx, sql, id).Scan(&id); err != nil {
+		if err == pgx.ErrNoRows {
+			return entity.ErrWebhookDeliveryNotFound
+		}
+		return fmt.Errorf("can't retry webhook delivery: %w", err)
+	}
+
+	return nil
+}
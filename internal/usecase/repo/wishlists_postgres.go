@@ -0,0 +1,131 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/postgres"
+)
+
+const _defaultFavouritesLimit = 20
+
+type WishlistsRepo struct {
+	*postgres.Postgres
+}
+
+func NewWishlistsRepo(pg *postgres.Postgres) *WishlistsRepo {
+	return &WishlistsRepo{pg}
+}
+
+// wishlistOwner returns the column and value identifying the owner's items.
+func wishlistOwner(owner entity.WishlistOwner) (string, any) {
+	if owner.CustomerID != 0 {
+		return "customer_id", owner.CustomerID
+	}
+	return "visitor_id", owner.VisitorID
+}
+
+func (r *WishlistsRepo) GetWishlistPictureIDs(ctx context.Context, owner entity.WishlistOwner) ([]uint64, error) {
+	column, value := wishlistOwner(owner)
+	sql := "SELECT picture_id FROM wishlist_items WHERE " + column + " = $1 ORDER BY created_at DESC, id DESC"
+
+	rows, err := r.Pool.Query(ctx, sql, value)
+	if err != nil {
+		return nil, fmt.Errorf("can't query wishlist: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]uint64, 0, _defaultListCap)
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("can't scan wishlist item: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (r *WishlistsRepo) AddWishlistItem(ctx context.Context, owner entity.WishlistOwner, pictureID uint64) error {
+	column, value := wishlistOwner(owner)
+	sql := "INSERT INTO wishlist_items (" + column + ", picture_id) VALUES ($1, $2) ON CONFLICT DO NOTHING"
+
+	if _, err := r.Pool.Exec(ctx, sql, value, pictureID); err != nil {
+		return fmt.Errorf("can't add wishlist item: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WishlistsRepo) RemoveWishlistItem(ctx context.Context, owner entity.WishlistOwner, pictureID uint64) error {
+	column, value := wishlistOwner(owner)
+	sql := "DELETE FROM wishlist_items WHERE " + column + " = $1 AND picture_id = $2"
+
+	if _, err := r.Pool.Exec(ctx, sql, value, pictureID); err != nil {
+		return fmt.Errorf("can't remove wishlist item: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WishlistsRepo) MergeWishlist(ctx context.Context, visitorID string, customerID uint64) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("can't begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `
+	INSERT INTO wishlist_items (customer_id, picture_id, created_at)
+	SELECT $2, picture_id, created_at FROM wishlist_items WHERE visitor_id = $1
+	ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.Exec(ctx, sql, visitorID, customerID); err != nil {
+		return fmt.Errorf("can't copy wishlist items: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM wishlist_items WHERE visitor_id = $1", visitorID); err != nil {
+		return fmt.Errorf("can't delete visitor wishlist: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("can't commit wishlist merge: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WishlistsRepo) GetFavourites(ctx context.Context, filter entity.FavouritesFilter) ([]entity.FavouritePicture, error) {
+	limit := filter.Limit
+	if limit == 0 {
+		limit = _defaultFavouritesLimit
+	}
+
+	sql := `
+	SELECT p.id, p.title, p.status, COUNT(*) AS cnt
+	FROM wishlist_items w
+	JOIN pictures p ON w.picture_id = p.id
+	GROUP BY p.id, p.title, p.status
+	ORDER BY cnt DESC, p.id
+	LIMIT $1
+	`
+
+	rows, err := r.Pool.Query(ctx, sql, limit)
+	if err != nil {
+		return nil, fmt.Errorf("can't query favourites: %w", err)
+	}
+	defer rows.Close()
+
+	favourites := make([]entity.FavouritePicture, 0, _defaultListCap)
+	for rows.Next() {
+		var f entity.FavouritePicture
+		if err := rows.Scan(&f.PictureID, &f.Title, &f.Status, &f.Count); err != nil {
+			return nil, fmt.Errorf("can't scan favourite: %w", err)
+		}
+		favourites = append(favourites, f)
+	}
+
+	return favourites, rows.Err()
+}
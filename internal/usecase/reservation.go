@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/notify"
+)
+
+type ReservationsUseCase struct {
+	repo     ReservationsRepo
+	pictures Pictures
+	orders   OrdersRepo
+	notifier notify.Notifier
+}
+
+var _ Reservations = (*ReservationsUseCase)(nil)
+
+// NewReservationsUseCase takes the pictures usecase rather than the repo so status
+// changes go through the same transition rules and history as manual ones. orders
+// tells whether a checkout still holds the picture when a hold runs out.
+func NewReservationsUseCase(repo ReservationsRepo, pictures Pictures, orders OrdersRepo, notifier notify.Notifier) *ReservationsUseCase {
+	return &ReservationsUseCase{repo: repo, pictures: pictures, orders: orders, notifier: notifier}
+}
+
+// CreateReservation holds an available picture until req.HoldUntil and marks it reserved.
+func (uc *ReservationsUseCase) CreateReservation(
+	ctx context.Context,
+	pictureID uint64,
+	req entity.ReservationCreateRequest,
+) (uint64, error) {
+	if !req.HoldUntil.After(time.Now()) {
+		return 0, entity.ErrHoldUntilInPast
+	}
+
+	picture, err := uc.pictures.GetPictureByID(ctx, pictureID, entity.PriceQuery{})
+	if err != nil {
+		return 0, fmt.Errorf("can't get picture by id: %w", err)
+	}
+
+	if err := uc.pictures.ReservePicture(ctx, pictureID); err != nil {
+		return 0, fmt.Errorf("can't reserve picture: %w", err)
+	}
+
+	id, err := uc.repo.CreateReservation(ctx, entity.Reservation{
+		PictureID: pictureID,
+		HoldUntil: req.HoldUntil,
+		Note:      strings.TrimSpace(req.Note),
+	})
+	if err != nil {
+		// put the picture back on sale, otherwise nothing would ever release it
+		_ = uc.pictures.ChangePictureStatus(ctx, pictureID, entity.PictureStatusRequest{Status: entity.PictureStatusAvailable})
+		return 0, fmt.Errorf("can't create reservation: %w", err)
+	}
+
+	_ = uc.notifier.Notify(ctx, notify.Message{
+		Event: "reservation.created",
+		Title: fmt.Sprintf("Картина зарезервирована: %s", picture.Title),
+		Text:  fmt.Sprintf("Резерв №%d до %s\n%s", id, req.HoldUntil.Format("02.01.2006 15:04"), strings.TrimSpace(req.Note)),
+		Data:  map[string]any{"reservation_id": id, "picture_id": pictureID, "hold_until": req.HoldUntil},
+	})
+
+	return id, nil
+}
+
+func (uc *ReservationsUseCase) GetReservations(ctx context.Context, pictureID uint64) ([]entity.Reservation, error) {
+	if _, err := uc.pictures.GetPictureByID(ctx, pictureID, entity.PriceQuery{}); err != nil {
+		return nil, fmt.Errorf("can't get picture by id: %w", err)
+	}
+
+	reservations, err := uc.repo.GetReservations(ctx, pictureID)
+	if err != nil {
+		return nil, fmt.Errorf("can't get reservations: %w", err)
+	}
+	return reservations, nil
+}
+
+// ReleaseExpiredReservations closes holds that ran out and returns the released ones.
+// The picture goes back on sale only if it is still reserved and no other hold or pending
+// order has it, so a picture sold, re-reserved or mid-checkout keeps its status.
+func (uc *ReservationsUseCase) ReleaseExpiredReservations(ctx context.Context) ([]entity.Reservation, error) {
+	expired, err := uc.repo.GetExpiredReservations(ctx, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("can't get expired reservations: %w", err)
+	}
+
+	released := make([]entity.Reservation, 0, len(expired))
+	for _, reservation := range expired {
+		if err := uc.release(ctx, reservation); err != nil {
+			if errors.Is(err, entity.ErrReservationNotFound) {
+				// already released by a concurrent run
+				continue
+			}
+			return released, fmt.Errorf("can't release reservation %d: %w", reservation.ID, err)
+		}
+		released = append(released, reservation)
+	}
+	return released, nil
+}
+
+// release puts the picture back on sale first and closes the hold afterwards,
+// so a failure in between is retried on the next run.
+func (uc *ReservationsUseCase) release(ctx context.Context, reservation entity.Reservation) error {
+	active, err := uc.repo.CountActiveReservations(ctx, reservation.PictureID)
+	if err != nil {
+		return fmt.Errorf("can't count active reservations: %w", err)
+	}
+	pending, err := uc.orders.CountPendingOrders(ctx, reservation.PictureID)
+	if err != nil {
+		return fmt.Errorf("can't count pending orders: %w", err)
+	}
+
+	if active == 0 && pending == 0 {
+		picture, err := uc.pictures.GetPictureByID(ctx, reservation.PictureID, entity.PriceQuery{})
+		if err != nil {
+			return fmt.Errorf("can't get picture by id: %w", err)
+		}
+
+		if picture.Status == entity.PictureStatusReserved {
+			err := uc.pictures.ChangePictureStatus(ctx, reservation.PictureID, entity.PictureStatusRequest{
+				Status: entity.PictureStatusAvailable,
+			})
+			if err != nil && !errors.Is(err, entity.ErrPictureStatusConflict) {
+				return fmt.Errorf("can't release picture: %w", err)
+			}
+		}
+	}
+
+	if err := uc.repo.ReleaseReservation(ctx, reservation.ID); err != nil {
+		return fmt.Errorf("can't mark reservation released: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/ttlcache"
+)
+
+const (
+	_defaultSearchLimit = 20
+	_maxSearchLimit     = 50
+	_maxSearchQueryLen  = 200
+
+	_defaultSuggestLimit = 8
+	_maxSuggestLimit     = 20
+	_maxSuggestQueryLen  = 100
+	_suggestCacheSize    = 1000
+)
+
+// SearchUseCase keeps suggestions in memory for a short time: they are requested
+// on every keystroke, and many visitors type the same beginnings.
+type SearchUseCase struct {
+	repo     SearchRepo
+	suggests *ttlcache.Cache[[]entity.SearchSuggestion]
+}
+
+var _ Search = (*SearchUseCase)(nil)
+
+func NewSearchUseCase(repo SearchRepo, suggestCacheTTL time.Duration) *SearchUseCase {
+	return &SearchUseCase{
+		repo:     repo,
+		suggests: ttlcache.New[[]entity.SearchSuggestion](suggestCacheTTL, _suggestCacheSize),
+	}
+}
+
+func (uc *SearchUseCase) Search(ctx context.Context, query entity.SearchQuery) ([]entity.SearchHit, error) {
+	q := strings.TrimSpace(query.Q)
+	if q == "" {
+		return nil, entity.ErrSearchQueryEmpty
+	}
+	if utf8.RuneCountInString(q) > _maxSearchQueryLen {
+		q = string([]rune(q)[:_maxSearchQueryLen])
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = _defaultSearchLimit
+	}
+	limit = min(limit, _maxSearchLimit)
+
+	hits, err := uc.repo.Search(ctx, q, limit, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("can't search: %w", err)
+	}
+
+	for i := range hits {
+		hits[i].URL = searchHitURL(hits[i])
+	}
+	return hits, nil
+}
+
+// Suggest returns cached suggestions for the same input and limit if there are any.
+// The result is shared with other callers and must not be modified.
+func (uc *SearchUseCase) Suggest(ctx context.Context, query entity.SearchQuery) ([]entity.SearchSuggestion, error) {
+	q := strings.ToLower(strings.Join(strings.Fields(query.Q), " "))
+	if q == "" {
+		return nil, entity.ErrSearchQueryEmpty
+	}
+	if utf8.RuneCountInString(q) > _maxSuggestQueryLen {
+		q = string([]rune(q)[:_maxSuggestQueryLen])
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = _defaultSuggestLimit
+	}
+	limit = min(limit, _maxSuggestLimit)
+
+	key := strconv.Itoa(limit) + ":" + q
+	if suggestions, ok := uc.suggests.Get(key); ok {
+		return suggestions, nil
+	}
+
+	suggestions, err := uc.repo.Suggest(ctx, q, limit)
+	if err != nil {
+		return nil, fmt.Errorf("can't suggest: %w", err)
+	}
+
+	for i := range suggestions {
+		suggestions[i].URL = suggestionURL(suggestions[i])
+	}
+	uc.suggests.Set(key, suggestions)
+	return suggestions, nil
+}
+
+// suggestionURL leads to the picture page or to the gallery filtered by the author or genre.
+func suggestionURL(s entity.SearchSuggestion) string {
+	return searchHitURL(entity.SearchHit{Type: s.Type, ID: s.ID, Slug: s.Slug})
+}
+
+// searchHitURL leads to the page of a picture or news; authors, genres and
+// techniques lead to the gallery filtered by them.
+func searchHitURL(hit entity.SearchHit) string {
+	switch hit.Type {
+	case entity.SearchHitPicture:
+		return "/pictures/" + hit.Slug
+	case entity.SearchHitNews:
+		return "/news/" + hit.Slug
+	case entity.SearchHitAuthor:
+		return "/pictures?author=" + strconv.FormatUint(hit.ID, 10)
+	case entity.SearchHitGenre:
+		return "/pictures?genre=" + strconv.FormatUint(hit.ID, 10)
+	case entity.SearchHitTechnique:
+		return "/pictures?technique=" + strconv.FormatUint(hit.ID, 10)
+	}
+	return ""
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+)
+
+const _sessionTokenSize = 32
+
+type SessionsUseCase struct {
+	repo SessionsRepo
+	ttl  time.Duration
+}
+
+var _ Sessions = (*SessionsUseCase)(nil)
+
+func NewSessionsUseCase(repo SessionsRepo, ttl time.Duration) *SessionsUseCase {
+	return &SessionsUseCase{repo: repo, ttl: ttl}
+}
+
+// CreateSession starts an admin panel session and returns the raw token for the cookie.
+func (uc *SessionsUseCase) CreateSession(ctx context.Context, ip, userAgent string) (string, *entity.AdminSession, error) {
+	if err := uc.repo.DeleteExpiredSessions(ctx); err != nil {
+		return "", nil, fmt.Errorf("can't delete expired sessions: %w", err)
+	}
+
+	token, err := randomToken(_sessionTokenSize)
+	if err != nil {
+		return "", nil, fmt.Errorf("can't generate session token: %w", err)
+	}
+
+	csrfToken, err := randomToken(_sessionTokenSize)
+	if err != nil {
+		return "", nil, fmt.Errorf("can't generate csrf token: %w", err)
+	}
+
+	session, err := uc.repo.CreateSession(ctx, entity.AdminSession{
+		CSRFToken: csrfToken,
+		IP:        ip,
+		UserAgent: userAgent,
+		ExpiresAt: time.Now().Add(uc.ttl),
+	}, hashToken(token))
+	if err != nil {
+		return "", nil, fmt.Errorf("can't create session: %w", err)
+	}
+
+	return token, session, nil
+}
+
+func (uc *SessionsUseCase) GetSession(ctx context.Context, token string) (*entity.AdminSession, error) {
+	session, err := uc.repo.GetSessionByHash(ctx, hashToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("can't get session: %w", err)
+	}
+
+	if session.ExpiresAt.Before(time.Now()) {
+		return nil, entity.ErrSessionNotFound
+	}
+
+	return session, nil
+}
+
+func (uc *SessionsUseCase) DeleteSession(ctx context.Context, token string) error {
+	if err := uc.repo.DeleteSessionByHash(ctx, hashToken(token)); err != nil {
+		return fmt.Errorf("can't delete session: %w", err)
+	}
+	return nil
+}
+
+func randomToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
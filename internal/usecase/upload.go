@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const _uploadsDir = "uploads"
+
+// saveUpload stores an uploaded file under a unique name and returns its public URL.
+func saveUpload(fileHeader *multipart.FileHeader) (string, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", fmt.Errorf("can't open uploaded file: %w", err)
+	}
+	defer file.Close()
+
+	ext := filepath.Ext(fileHeader.Filename)
+	filename := fmt.Sprintf("%d_%s%s", time.Now().UnixNano(), uuid.New().String(), ext)
+	filePath := filepath.Join(_uploadsDir, filename)
+
+	if err := os.MkdirAll(_uploadsDir, 0755); err != nil {
+		return "", fmt.Errorf("can't create uploads directory: %w", err)
+	}
+
+	dst, err := os.Create(filePath)
+	if err != nil {
+		return "", fmt.Errorf("can't create file: %w", err)
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, file); err != nil {
+		os.Remove(filePath)
+		return "", fmt.Errorf("can't save file: %w", err)
+	}
+
+	return "/" + filePath, nil
+}
+
+// removeUpload deletes the file behind a URL returned by saveUpload. Missing files are ignored.
+func removeUpload(url string) error {
+	if err := os.Remove(strings.TrimPrefix(url, "/")); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package webapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+)
+
+// FileMailer writes every message as an .eml file into a directory instead of
+// sending it, so local development works without a mail server.
+type FileMailer struct {
+	from string
+	dir  string
+}
+
+func NewFileMailer(from, dir string) *FileMailer {
+	return &FileMailer{from: from, dir: dir}
+}
+
+func (m *FileMailer) Send(_ context.Context, email entity.Email) error {
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("webapi - FileMailer - Send - os.MkdirAll: %w", err)
+	}
+
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Errorf("webapi - FileMailer - Send - rand.Read: %w", err)
+	}
+
+	now := time.Now()
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102-150405"), hex.EncodeToString(buf))
+
+	if err := os.WriteFile(filepath.Join(m.dir, name), buildMessage(m.from, email, now), 0o644); err != nil {
+		return fmt.Errorf("webapi - FileMailer - Send - os.WriteFile: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package webapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+)
+
+// SMTPMailer sends messages through an SMTP server with PLAIN auth over STARTTLS.
+type SMTPMailer struct {
+	from   string
+	addr   string
+	auth   smtp.Auth
+	sender string
+}
+
+func NewSMTPMailer(from, host string, port int, user, password string) (*SMTPMailer, error) {
+	address, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("webapi - NewSMTPMailer - mail.ParseAddress: %w", err)
+	}
+
+	var auth smtp.Auth
+	if user != "" {
+		auth = smtp.PlainAuth("", user, password, host)
+	}
+
+	return &SMTPMailer{
+		from:   from,
+		addr:   net.JoinHostPort(host, strconv.Itoa(port)),
+		auth:   auth,
+		sender: address.Address,
+	}, nil
+}
+
+func (m *SMTPMailer) Send(_ context.Context, email entity.Email) error {
+	msg := buildMessage(m.from, email, time.Now())
+
+	if err := smtp.SendMail(m.addr, m.auth, m.sender, []string{email.To}, msg); err != nil {
+		return fmt.Errorf("webapi - SMTPMailer - Send - smtp.SendMail: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package webapi
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+)
+
+// FakePaymentProvider is an in-process payment gateway for tests and local development.
+// Payments are confirmed by calling SimulatePayment, which produces a notification
+// signed the same way as a real provider webhook.
+type FakePaymentProvider struct {
+	secret  []byte
+	baseURL string
+
+	mu       sync.Mutex
+	payments map[string]entity.PaymentRequest
+}
+
+func NewFakePaymentProvider(secret, baseURL string) *FakePaymentProvider {
+	return &FakePaymentProvider{
+		secret:   []byte(secret),
+		baseURL:  baseURL,
+		payments: make(map[string]entity.PaymentRequest),
+	}
+}
+
+func (p *FakePaymentProvider) CreatePayment(_ context.Context, req entity.PaymentRequest) (*entity.Payment, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("webapi - FakePaymentProvider - CreatePayment - rand.Read: %w", err)
+	}
+	id := "fake_" + hex.EncodeToString(buf)
+
+	p.mu.Lock()
+	p.payments[id] = req
+	p.mu.Unlock()
+
+	return &entity.Payment{
+		ID:              id,
+		ConfirmationURL: p.baseURL + "/api/payments/fake/" + id,
+	}, nil
+}
+
+func (p *FakePaymentProvider) ParseWebhook(body []byte, signature string) (*entity.PaymentEvent, error) {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, p.sign(body)) {
+		return nil, entity.ErrInvalidSignature
+	}
+
+	var event entity.PaymentEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("webapi - FakePaymentProvider - ParseWebhook - json.Unmarshal: %w", err)
+	}
+
+	return &event, nil
+}
+
+// SimulatePayment finishes a payment created by this provider and returns the signed notification.
+func (p *FakePaymentProvider) SimulatePayment(paymentID string, status entity.PaymentEventStatus) ([]byte, string, error) {
+	p.mu.Lock()
+	_, ok := p.payments[paymentID]
+	delete(p.payments, paymentID)
+	p.mu.Unlock()
+
+	if !ok {
+		return nil, "", fmt.Errorf("webapi - FakePaymentProvider - SimulatePayment: unknown payment %s", paymentID)
+	}
+
+	body, err := json.Marshal(entity.PaymentEvent{PaymentID: paymentID, Status: status})
+	if err != nil {
+		return nil, "", fmt.Errorf("webapi - FakePaymentProvider - SimulatePayment - json.Marshal: %w", err)
+	}
+
+	return body, hex.EncodeToString(p.sign(body)), nil
+}
+
+func (p *FakePaymentProvider) sign(body []byte) []byte {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
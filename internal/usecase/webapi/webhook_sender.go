@@ -0,0 +1,60 @@
+package webapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/webhooksig"
+)
+
+// HTTPWebhookSender posts catalog events to subscriber URLs. Every request is signed
+// with the subscription secret as described in package webhooksig.
+type HTTPWebhookSender struct {
+	client *http.Client
+}
+
+func NewHTTPWebhookSender(timeout time.Duration) *HTTPWebhookSender {
+	return &HTTPWebhookSender{client: &http.Client{Timeout: timeout}}
+}
+
+func (s *HTTPWebhookSender) Send(ctx context.Context, dispatch entity.WebhookDispatch) entity.WebhookResult {
+	body, err := json.Marshal(dispatch.Payload)
+	if err != nil {
+		return entity.WebhookResult{Err: fmt.Errorf("webapi - HTTPWebhookSender - Send - json.Marshal: %w", err)}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dispatch.URL, bytes.NewReader(body))
+	if err != nil {
+		return entity.WebhookResult{Err: fmt.Errorf("webapi - HTTPWebhookSender - Send - http.NewRequest: %w", err)}
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", string(dispatch.Payload.Event))
+	req.Header.Set("X-Webhook-Event-ID", strconv.FormatUint(dispatch.Payload.EventID, 10))
+	webhooksig.SignRequest(req, dispatch.Secret, body, time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return entity.WebhookResult{Err: fmt.Errorf("webapi - HTTPWebhookSender - Send - client.Do: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	status := resp.StatusCode
+	if status < 200 || status >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return entity.WebhookResult{
+			ResponseStatus: &status,
+			Err:            fmt.Errorf("unexpected status %d: %s", status, bytes.TrimSpace(snippet)),
+		}
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	return entity.WebhookResult{ResponseStatus: &status}
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/config"
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+)
+
+const (
+	_defaultDeliveriesLimit = 50
+	_maxDeliveriesLimit     = 500
+	// _webhookBatchSize bounds the work of one dispatch run; the rest waits for the next tick.
+	_webhookBatchSize = 100
+)
+
+type WebhooksUseCase struct {
+	cfg    config.Webhook
+	repo   WebhooksRepo
+	sender WebhookSender
+}
+
+var _ Webhooks = (*WebhooksUseCase)(nil)
+
+func NewWebhooksUseCase(cfg config.Webhook, repo WebhooksRepo, sender WebhookSender) *WebhooksUseCase {
+	return &WebhooksUseCase{cfg: cfg, repo: repo, sender: sender}
+}
+
+func validateWebhookEvents(events []entity.WebhookEvent) error {
+	for _, event := range events {
+		if !event.Valid() {
+			return fmt.Errorf("%w: %s", entity.ErrUnknownWebhookEvent, event)
+		}
+	}
+	return nil
+}
+
+func (uc *WebhooksUseCase) GetSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	subscriptions, err := uc.repo.GetSubscriptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't get webhook subscriptions: %w", err)
+	}
+	return subscriptions, nil
+}
+
+func (uc *WebhooksUseCase) CreateSubscription(ctx context.Context, req entity.WebhookSubscriptionCreateRequest) (uint64, error) {
+	if err := validateWebhookEvents(req.Events); err != nil {
+		return 0, err
+	}
+
+	id, err := uc.repo.CreateSubscription(ctx, entity.WebhookSubscription{
+		URL:    strings.TrimSpace(req.URL),
+		Secret: req.Secret,
+		Events: req.Events,
+		Active: true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("can't create webhook subscription: %w", err)
+	}
+	return id, nil
+}
+
+func (uc *WebhooksUseCase) UpdateSubscription(ctx context.Context, id uint64, req entity.WebhookSubscriptionUpdateRequest) error {
+	if err := validateWebhookEvents(req.Events); err != nil {
+		return err
+	}
+
+	if err := uc.repo.UpdateSubscription(ctx, id, req); err != nil {
+		return fmt.Errorf("can't update webhook subscription: %w", err)
+	}
+	return nil
+}
+
+func (uc *WebhooksUseCase) DeleteSubscription(ctx context.Context, id uint64) error {
+	if err := uc.repo.DeleteSubscription(ctx, id); err != nil {
+		return fmt.Errorf("can't delete webhook subscription: %w", err)
+	}
+	return nil
+}
+
+func (uc *WebhooksUseCase) GetDeliveries(ctx context.Context, filter entity.WebhookDeliveryFilter) ([]entity.WebhookDelivery, error) {
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, entity.ErrUnknownWebhookStatus
+	}
+	if filter.Event != "" && !filter.Event.Valid() {
+		return nil, entity.ErrUnknownWebhookEvent
+	}
+	if filter.Limit == 0 {
+		filter.Limit = _defaultDeliveriesLimit
+	}
+	if filter.Limit > _maxDeliveriesLimit {
+		filter.Limit = _maxDeliveriesLimit
+	}
+
+	deliveries, err := uc.repo.GetDeliveries(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("can't get webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (uc *WebhooksUseCase) RetryDelivery(ctx context.Context, id uint64) error {
+	if err := uc.repo.RetryDelivery(ctx, id); err != nil {
+		return fmt.Errorf("can't retry webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (uc *WebhooksUseCase) DispatchWebhooks(ctx context.Context) error {
+	for {
+		n, err := uc.repo.FanOutOutbox(ctx, _webhookBatchSize)
+		if err != nil {
+			return fmt.Errorf("can't fan out webhook events: %w", err)
+		}
+		if n < _webhookBatchSize {
+			break
+		}
+	}
+
+	// the lease outlives one request so a delivery can't be claimed again while it is being sent
+	dispatches, err := uc.repo.ClaimDueDeliveries(ctx, time.Now(), 2*uc.cfg.Timeout, _webhookBatchSize)
+	if err != nil {
+		return fmt.Errorf("can't claim webhook deliveries: %w", err)
+	}
+
+	for _, dispatch := range dispatches {
+		result := uc.sender.Send(ctx, dispatch)
+
+		var next *time.Time
+		if result.Err != nil && dispatch.Attempts+1 < uc.cfg.MaxAttempts {
+			at := time.Now().Add(uc.backoff(dispatch.Attempts))
+			next = &at
+		}
+
+		if err := uc.repo.RecordDeliveryAttempt(ctx, dispatch.DeliveryID, result, next); err != nil {
+			return fmt.Errorf("can't record webhook delivery: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// backoff returns the pause after the given number of previous attempts.
+func (uc *WebhooksUseCase) backoff(attempts int) time.Duration {
+	delay := uc.cfg.Backoff
+	for i := 0; i < attempts && delay < uc.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, uc.cfg.MaxBackoff)
+}
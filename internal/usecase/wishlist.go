@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/internal/entity"
+)
+
+const _visitorIDSize = 24
+
+type WishlistsUseCase struct {
+	repo     WishlistsRepo
+	pictures Pictures
+}
+
+var _ Wishlists = (*WishlistsUseCase)(nil)
+
+func NewWishlistsUseCase(repo WishlistsRepo, pictures Pictures) *WishlistsUseCase {
+	return &WishlistsUseCase{repo: repo, pictures: pictures}
+}
+
+// NewVisitorID generates an id for an anonymous visitor's wishlist cookie.
+func (uc *WishlistsUseCase) NewVisitorID() (string, error) {
+	id, err := randomToken(_visitorIDSize)
+	if err != nil {
+		return "", fmt.Errorf("can't generate visitor id: %w", err)
+	}
+	return id, nil
+}
+
+// GetWishlist returns the saved pictures with prices evaluated as for the catalogue.
+func (uc *WishlistsUseCase) GetWishlist(
+	ctx context.Context,
+	owner entity.WishlistOwner,
+	query entity.PriceQuery,
+) ([]entity.Picture, error) {
+	if owner.Empty() {
+		return []entity.Picture{}, nil
+	}
+
+	ids, err := uc.repo.GetWishlistPictureIDs(ctx, owner)
+	if err != nil {
+		return nil, fmt.Errorf("can't get wishlist: %w", err)
+	}
+	if len(ids) == 0 {
+		return []entity.Picture{}, nil
+	}
+
+	pictures, err := uc.pictures.GetPictures(ctx, entity.PictureFilter{IDs: ids, PriceQuery: query})
+	if err != nil {
+		return nil, fmt.Errorf("can't get wishlist pictures: %w", err)
+	}
+	return pictures, nil
+}
+
+func (uc *WishlistsUseCase) AddToWishlist(ctx context.Context, owner entity.WishlistOwner, pictureID uint64) error {
+	if owner.Empty() {
+		return entity.ErrWishlistOwnerRequired
+	}
+
+	if _, err := uc.pictures.GetPictureByID(ctx, pictureID, entity.PriceQuery{}); err != nil {
+		return fmt.Errorf("can't get picture by id: %w", err)
+	}
+
+	if err := uc.repo.AddWishlistItem(ctx, owner, pictureID); err != nil {
+		return fmt.Errorf("can't add to wishlist: %w", err)
+	}
+	return nil
+}
+
+func (uc *WishlistsUseCase) RemoveFromWishlist(ctx context.Context, owner entity.WishlistOwner, pictureID uint64) error {
+	if owner.Empty() {
+		return nil
+	}
+
+	if err := uc.repo.RemoveWishlistItem(ctx, owner, pictureID); err != nil {
+		return fmt.Errorf("can't remove from wishlist: %w", err)
+	}
+	return nil
+}
+
+func (uc *WishlistsUseCase) GetFavourites(ctx context.Context, filter entity.FavouritesFilter) ([]entity.FavouritePicture, error) {
+	favourites, err := uc.repo.GetFavourites(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("can't get favourites: %w", err)
+	}
+	return favourites, nil
+}
+
+func (uc *WishlistsUseCase) MergeWishlist(ctx context.Context, visitorID string, customerID uint64) error {
+	if visitorID == "" {
+		return nil
+	}
+
+	if err := uc.repo.MergeWishlist(ctx, visitorID, customerID); err != nil {
+		return fmt.Errorf("can't merge wishlist: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS admin_totp (
+    login VARCHAR(255) PRIMARY KEY,
+    secret VARCHAR(64) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    confirmed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS admin_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    login VARCHAR(255) NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS admin_recovery_codes_login_idx ON admin_recovery_codes (login);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS picture_status_history;
+
+DROP INDEX IF EXISTS pictures_status_idx;
+
+ALTER TABLE pictures
+    DROP CONSTRAINT IF EXISTS pictures_status_check,
+    DROP COLUMN IF EXISTS status_changed_at,
+    DROP COLUMN IF EXISTS status;
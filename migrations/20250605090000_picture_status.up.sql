@@ -0,0 +1,19 @@
+ALTER TABLE pictures
+    ADD COLUMN IF NOT EXISTS status VARCHAR(32) NOT NULL DEFAULT 'available',
+    ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP WITH TIME ZONE DEFAULT NOW();
+
+ALTER TABLE pictures
+    ADD CONSTRAINT pictures_status_check CHECK (status IN ('available', 'reserved', 'sold', 'not_for_sale'));
+
+CREATE INDEX IF NOT EXISTS pictures_status_idx ON pictures (status);
+
+CREATE TABLE IF NOT EXISTS picture_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    picture_id INTEGER NOT NULL REFERENCES pictures(id) ON DELETE CASCADE,
+    from_status VARCHAR(32) NOT NULL,
+    to_status VARCHAR(32) NOT NULL,
+    override BOOLEAN NOT NULL DEFAULT FALSE,
+    changed_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS picture_status_history_picture_idx ON picture_status_history (picture_id, changed_at DESC);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS reservations (
+    id SERIAL PRIMARY KEY,
+    picture_id INTEGER NOT NULL REFERENCES pictures(id) ON DELETE CASCADE,
+    hold_until TIMESTAMP WITH TIME ZONE NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    released_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS reservations_picture_idx ON reservations (picture_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS reservations_active_idx ON reservations (hold_until) WHERE released_at IS NULL;
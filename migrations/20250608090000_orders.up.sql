@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS orders (
+    id SERIAL PRIMARY KEY,
+    picture_id INTEGER NOT NULL REFERENCES pictures(id),
+    customer_name VARCHAR(255) NOT NULL,
+    customer_email VARCHAR(255) NOT NULL,
+    customer_phone VARCHAR(64) NOT NULL DEFAULT '',
+    amount INTEGER NOT NULL,
+    status VARCHAR(32) NOT NULL DEFAULT 'pending',
+    payment_id VARCHAR(255) NOT NULL DEFAULT '',
+    payment_url TEXT NOT NULL DEFAULT '',
+    paid_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS orders_status_idx ON orders (status, created_at DESC);
+CREATE INDEX IF NOT EXISTS orders_picture_idx ON orders (picture_id);
+CREATE UNIQUE INDEX IF NOT EXISTS orders_payment_id_idx ON orders (payment_id) WHERE payment_id <> '';
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS price_history;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS currency;
+ALTER TABLE pictures DROP COLUMN IF EXISTS currency;
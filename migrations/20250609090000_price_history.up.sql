@@ -0,0 +1,17 @@
+ALTER TABLE pictures ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'RUB';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'RUB';
+
+CREATE TABLE IF NOT EXISTS price_history (
+    id BIGSERIAL PRIMARY KEY,
+    picture_id INTEGER NOT NULL REFERENCES pictures(id) ON DELETE CASCADE,
+    old_price INTEGER,
+    old_currency VARCHAR(3),
+    new_price INTEGER NOT NULL,
+    new_currency VARCHAR(3) NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS price_history_picture_idx ON price_history (picture_id, changed_at DESC);
+
+INSERT INTO price_history (picture_id, new_price, new_currency, changed_at)
+SELECT id, price, currency, created_at FROM pictures;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS discount_rules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('percent', 'fixed')),
+    value INTEGER NOT NULL CHECK (value > 0),
+    currency VARCHAR(3) NOT NULL DEFAULT 'RUB',
+    genre_id INTEGER REFERENCES genres(id) ON DELETE CASCADE,
+    author_id INTEGER REFERENCES authors(id) ON DELETE CASCADE,
+    work_technique_id INTEGER REFERENCES work_techniques(id) ON DELETE CASCADE,
+    picture_ids INTEGER[] NOT NULL DEFAULT '{}',
+    promo_code VARCHAR(64),
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS discount_rules_promo_code_idx ON discount_rules (promo_code) WHERE promo_code IS NOT NULL;
+CREATE INDEX IF NOT EXISTS discount_rules_period_idx ON discount_rules (starts_at, ends_at);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS promo_code VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS wishlist_items (
+    id SERIAL PRIMARY KEY,
+    visitor_id VARCHAR(64) NOT NULL,
+    picture_id INTEGER NOT NULL REFERENCES pictures(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (visitor_id, picture_id)
+);
+
+CREATE INDEX IF NOT EXISTS wishlist_items_picture_idx ON wishlist_items (picture_id);
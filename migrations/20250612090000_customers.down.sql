@@ -0,0 +1,12 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS customer_id;
+ALTER TABLE inquiries DROP COLUMN IF EXISTS customer_id;
+
+DELETE FROM wishlist_items WHERE visitor_id IS NULL;
+DROP INDEX IF EXISTS wishlist_items_customer_picture_idx;
+ALTER TABLE wishlist_items DROP CONSTRAINT IF EXISTS wishlist_items_owner_check;
+ALTER TABLE wishlist_items DROP COLUMN IF EXISTS customer_id;
+ALTER TABLE wishlist_items ALTER COLUMN visitor_id SET NOT NULL;
+
+DROP TABLE IF EXISTS customer_sessions;
+DROP TABLE IF EXISTS customer_tokens;
+DROP TABLE IF EXISTS customers;
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS customers (
+    id SERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    verified_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS customer_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    customer_id INTEGER NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    purpose VARCHAR(32) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS customer_tokens_customer_idx ON customer_tokens (customer_id, purpose);
+
+CREATE TABLE IF NOT EXISTS customer_sessions (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    customer_id INTEGER NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS customer_sessions_customer_idx ON customer_sessions (customer_id);
+
+ALTER TABLE wishlist_items ALTER COLUMN visitor_id DROP NOT NULL;
+ALTER TABLE wishlist_items ADD COLUMN IF NOT EXISTS customer_id INTEGER REFERENCES customers(id) ON DELETE CASCADE;
+ALTER TABLE wishlist_items ADD CONSTRAINT wishlist_items_owner_check CHECK ((visitor_id IS NULL) <> (customer_id IS NULL));
+CREATE UNIQUE INDEX IF NOT EXISTS wishlist_items_customer_picture_idx ON wishlist_items (customer_id, picture_id) WHERE customer_id IS NOT NULL;
+
+ALTER TABLE inquiries ADD COLUMN IF NOT EXISTS customer_id INTEGER REFERENCES customers(id) ON DELETE SET NULL;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS customer_id INTEGER REFERENCES customers(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS inquiries_customer_idx ON inquiries (customer_id) WHERE customer_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS orders_customer_idx ON orders (customer_id) WHERE customer_id IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS exhibition_pictures;
+DROP TABLE IF EXISTS exhibitions;
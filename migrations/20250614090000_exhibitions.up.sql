@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS exhibitions (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    venue VARCHAR(255) NOT NULL DEFAULT '',
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    cover_url VARCHAR(512) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS exhibitions_period_idx ON exhibitions (starts_at, ends_at);
+
+CREATE TABLE IF NOT EXISTS exhibition_pictures (
+    exhibition_id INTEGER NOT NULL REFERENCES exhibitions(id) ON DELETE CASCADE,
+    picture_id INTEGER NOT NULL REFERENCES pictures(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (exhibition_id, picture_id)
+);
+
+CREATE INDEX IF NOT EXISTS exhibition_pictures_picture_idx ON exhibition_pictures (picture_id);
@@ -0,0 +1,4 @@
+ALTER TABLE news ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE;
+UPDATE news SET updated_at = COALESCE(created_at, NOW()) WHERE updated_at IS NULL;
+ALTER TABLE news ALTER COLUMN updated_at SET DEFAULT NOW();
+ALTER TABLE news ALTER COLUMN updated_at SET NOT NULL;
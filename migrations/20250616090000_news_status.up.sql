@@ -0,0 +1,7 @@
+ALTER TABLE news ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'draft';
+ALTER TABLE news ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP WITH TIME ZONE;
+
+-- everything created before statuses existed was already public
+UPDATE news SET status = 'published', publish_at = created_at;
+
+CREATE INDEX IF NOT EXISTS news_status_publish_at_idx ON news (status, publish_at);
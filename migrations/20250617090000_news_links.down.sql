@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS news_authors;
+DROP TABLE IF EXISTS news_pictures;
+ALTER TABLE news DROP COLUMN IF EXISTS cover_url;
@@ -0,0 +1,18 @@
+ALTER TABLE news ADD COLUMN IF NOT EXISTS cover_url VARCHAR(512) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS news_pictures (
+    news_id INTEGER NOT NULL REFERENCES news(id) ON DELETE CASCADE,
+    picture_id INTEGER NOT NULL REFERENCES pictures(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (news_id, picture_id)
+);
+
+CREATE INDEX IF NOT EXISTS news_pictures_picture_idx ON news_pictures (picture_id);
+
+CREATE TABLE IF NOT EXISTS news_authors (
+    news_id INTEGER NOT NULL REFERENCES news(id) ON DELETE CASCADE,
+    author_id INTEGER NOT NULL REFERENCES authors(id) ON DELETE CASCADE,
+    PRIMARY KEY (news_id, author_id)
+);
+
+CREATE INDEX IF NOT EXISTS news_authors_author_idx ON news_authors (author_id);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS news_slug_idx;
+DROP INDEX IF EXISTS authors_slug_idx;
+DROP INDEX IF EXISTS pictures_slug_idx;
+
+ALTER TABLE news DROP COLUMN IF EXISTS slug;
+ALTER TABLE authors DROP COLUMN IF EXISTS slug;
+ALTER TABLE pictures DROP COLUMN IF EXISTS slug;
+
+DROP TABLE IF EXISTS slug_history;
@@ -0,0 +1,90 @@
+CREATE TABLE IF NOT EXISTS slug_history (
+    entity_type VARCHAR(32) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    entity_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (entity_type, slug)
+);
+
+CREATE INDEX IF NOT EXISTS slug_history_entity_idx ON slug_history (entity_type, entity_id);
+
+-- Mirrors pkg/slug.Make for rows created before slugs existed.
+CREATE FUNCTION pg_temp.slugify(title TEXT, kind TEXT) RETURNS TEXT AS $$
+DECLARE
+    s TEXT := lower(title);
+BEGIN
+    s := replace(s, 'щ', 'shch');
+    s := replace(s, 'ё', 'yo');
+    s := replace(s, 'ж', 'zh');
+    s := replace(s, 'х', 'kh');
+    s := replace(s, 'ц', 'ts');
+    s := replace(s, 'ч', 'ch');
+    s := replace(s, 'ш', 'sh');
+    s := replace(s, 'ю', 'yu');
+    s := replace(s, 'я', 'ya');
+    s := replace(s, 'ї', 'yi');
+    s := replace(s, 'є', 'ye');
+    s := replace(s, 'ъ', '');
+    s := replace(s, 'ь', '');
+    s := translate(s, 'абвгдезийклмнопрстуфыэіґў', 'abvgdeziyklmnoprstufyeigu');
+    s := trim(both '-' from regexp_replace(s, '[^a-z0-9]+', '-', 'g'));
+    IF length(s) > 80 THEN
+        s := left(s, 80);
+        IF position('-' in s) > 0 THEN
+            s := regexp_replace(s, '-[^-]*$', '');
+        END IF;
+    END IF;
+    IF s = '' THEN
+        RETURN kind;
+    END IF;
+    IF s ~ '^[0-9]+$' THEN
+        RETURN kind || '-' || s;
+    END IF;
+    RETURN s;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+ALTER TABLE pictures ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+ALTER TABLE authors ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+ALTER TABLE news ADD COLUMN IF NOT EXISTS slug VARCHAR(255);
+
+-- The unique indexes come first: NULLs don't conflict, and the lookups below use them.
+CREATE UNIQUE INDEX IF NOT EXISTS pictures_slug_idx ON pictures (slug);
+CREATE UNIQUE INDEX IF NOT EXISTS authors_slug_idx ON authors (slug);
+CREATE UNIQUE INDEX IF NOT EXISTS news_slug_idx ON news (slug);
+
+-- Mirrors slugScope.unique: the oldest row keeps the plain slug, the next ones get
+-- the first free -2, -3, ... suffix, checked against every slug assigned so far.
+CREATE FUNCTION pg_temp.backfill_slugs(tbl TEXT, title_column TEXT, kind TEXT) RETURNS VOID AS $$
+DECLARE
+    r RECORD;
+    base TEXT;
+    candidate TEXT;
+    n INTEGER;
+    taken BOOLEAN;
+BEGIN
+    FOR r IN EXECUTE format('SELECT id, %I AS title FROM %I ORDER BY id', title_column, tbl) LOOP
+        base := pg_temp.slugify(r.title, kind);
+        candidate := base;
+        n := 2;
+        LOOP
+            EXECUTE format('SELECT EXISTS (SELECT 1 FROM %I WHERE slug = $1)', tbl) INTO taken USING candidate;
+            EXIT WHEN NOT taken;
+            candidate := base || '-' || n;
+            n := n + 1;
+        END LOOP;
+        EXECUTE format('UPDATE %I SET slug = $1 WHERE id = $2', tbl) USING candidate, r.id;
+    END LOOP;
+END;
+$$ LANGUAGE plpgsql;
+
+SELECT pg_temp.backfill_slugs('pictures', 'title', 'picture');
+SELECT pg_temp.backfill_slugs('authors', 'full_name', 'author');
+SELECT pg_temp.backfill_slugs('news', 'title', 'news');
+
+DROP FUNCTION pg_temp.backfill_slugs(TEXT, TEXT, TEXT);
+DROP FUNCTION pg_temp.slugify(TEXT, TEXT);
+
+ALTER TABLE pictures ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE authors ALTER COLUMN slug SET NOT NULL;
+ALTER TABLE news ALTER COLUMN slug SET NOT NULL;
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS news_search_vector_idx;
+DROP INDEX IF EXISTS work_techniques_search_vector_idx;
+DROP INDEX IF EXISTS genres_search_vector_idx;
+DROP INDEX IF EXISTS authors_search_vector_idx;
+DROP INDEX IF EXISTS pictures_search_vector_idx;
+
+ALTER TABLE news DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE work_techniques DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE genres DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE authors DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE pictures DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE pictures DROP COLUMN IF EXISTS description;
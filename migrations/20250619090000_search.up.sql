@@ -0,0 +1,32 @@
+ALTER TABLE pictures ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '';
+
+-- Every text is indexed with both the Russian and the English configuration,
+-- so a query in either language matches word forms of both. Titles and names
+-- weigh more than descriptions and content.
+ALTER TABLE pictures ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian', title), 'A') || setweight(to_tsvector('english', title), 'A') ||
+    setweight(to_tsvector('russian', description), 'B') || setweight(to_tsvector('english', description), 'B')
+) STORED;
+
+ALTER TABLE authors ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian', full_name), 'A') || setweight(to_tsvector('english', full_name), 'A')
+) STORED;
+
+ALTER TABLE genres ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian', name), 'A') || setweight(to_tsvector('english', name), 'A')
+) STORED;
+
+ALTER TABLE work_techniques ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian', name), 'A') || setweight(to_tsvector('english', name), 'A')
+) STORED;
+
+ALTER TABLE news ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian', title), 'A') || setweight(to_tsvector('english', title), 'A') ||
+    setweight(to_tsvector('russian', content), 'B') || setweight(to_tsvector('english', content), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS pictures_search_vector_idx ON pictures USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS authors_search_vector_idx ON authors USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS genres_search_vector_idx ON genres USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS work_techniques_search_vector_idx ON work_techniques USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS news_search_vector_idx ON news USING GIN (search_vector);
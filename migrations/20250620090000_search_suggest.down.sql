@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS genres_name_trgm_idx;
+DROP INDEX IF EXISTS authors_full_name_trgm_idx;
+DROP INDEX IF EXISTS pictures_title_trgm_idx;
+
+DROP EXTENSION IF EXISTS pg_trgm;
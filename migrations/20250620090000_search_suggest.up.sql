@@ -0,0 +1,6 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Trigram indexes serve the typo tolerant suggestions while typing.
+CREATE INDEX IF NOT EXISTS pictures_title_trgm_idx ON pictures USING GIN (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS authors_full_name_trgm_idx ON authors USING GIN (full_name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS genres_name_trgm_idx ON genres USING GIN (name gin_trgm_ops);
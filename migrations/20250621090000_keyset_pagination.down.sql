@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS news_publish_at_id_idx;
+DROP INDEX IF EXISTS pictures_created_at_id_idx;
@@ -0,0 +1,3 @@
+-- Cursor pagination seeks by (sort key, id) in both directions.
+CREATE INDEX IF NOT EXISTS pictures_created_at_id_idx ON pictures (created_at, id);
+CREATE INDEX IF NOT EXISTS news_publish_at_id_idx ON news (publish_at, id);
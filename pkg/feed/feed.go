@@ -0,0 +1,155 @@
+// Package feed writes RSS 2.0 and Atom (RFC 4287) syndication feeds.
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// Feed is the format-independent description of a feed.
+type Feed struct {
+	Title       string
+	Description string
+	// Link is the site page the feed belongs to; SelfLink is the URL of the feed itself.
+	Link     string
+	SelfLink string
+	Author   string
+	Updated  time.Time
+	Items    []Item
+}
+
+// Item is a feed entry. ID must be a stable absolute URI, Content is HTML.
+type Item struct {
+	ID        string
+	Title     string
+	Link      string
+	Content   string
+	Published time.Time
+	Updated   time.Time
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSS encodes the feed as RSS 2.0.
+func (f Feed) RSS() ([]byte, error) {
+	doc := rss{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Description,
+			AtomLink:      atomLink{Href: f.SelfLink, Rel: "self", Type: "application/rss+xml"},
+			LastBuildDate: f.Updated.UTC().Format(time.RFC1123Z),
+		},
+	}
+
+	for _, item := range f.Items {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{Value: item.ID},
+			PubDate:     item.Published.UTC().Format(time.RFC1123Z),
+			Description: item.Content,
+		})
+	}
+
+	return marshal(doc)
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Link      atomLink    `xml:"link"`
+	Published string      `xml:"published"`
+	Updated   string      `xml:"updated"`
+	Content   atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Atom encodes the feed as Atom 1.0.
+func (f Feed) Atom() ([]byte, error) {
+	doc := atomFeed{
+		ID:      f.SelfLink,
+		Title:   f.Title,
+		Updated: f.Updated.UTC().Format(time.RFC3339),
+		Author:  atomAuthor{Name: f.Author},
+		Links: []atomLink{
+			{Href: f.SelfLink, Rel: "self", Type: "application/atom+xml"},
+			{Href: f.Link, Rel: "alternate", Type: "text/html"},
+		},
+	}
+
+	for _, item := range f.Items {
+		doc.Entries = append(doc.Entries, atomEntry{
+			ID:        item.ID,
+			Title:     item.Title,
+			Link:      atomLink{Href: item.Link, Rel: "alternate", Type: "text/html"},
+			Published: item.Published.UTC().Format(time.RFC3339),
+			Updated:   item.Updated.UTC().Format(time.RFC3339),
+			Content:   atomContent{Type: "html", Value: item.Content},
+		})
+	}
+
+	return marshal(doc)
+}
+
+func marshal(doc any) ([]byte, error) {
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("feed - marshal - xml.MarshalIndent: %w", err)
+	}
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,123 @@
+// Package ical writes iCalendar data (RFC 5545).
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	_dateTimeUTC   = "20060102T150405Z"
+	_maxLineOctets = 75
+)
+
+// Calendar is a VCALENDAR object with its events.
+type Calendar struct {
+	ProdID string
+	// Name is shown by clients that support the X-WR-CALNAME extension.
+	Name   string
+	Events []Event
+}
+
+// Event is a VEVENT. Times are written in UTC, so clients convert them to
+// the local time zone of the user and no VTIMEZONE component is needed.
+type Event struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	URL          string
+	Start        time.Time
+	End          time.Time
+	Stamp        time.Time
+	LastModified time.Time
+}
+
+// Marshal encodes the calendar with CRLF line endings and folded long lines.
+func (c Calendar) Marshal() []byte {
+	var buf bytes.Buffer
+
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:"+c.ProdID)
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+	writeLine(&buf, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&buf, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+
+	for _, e := range c.Events {
+		e.marshal(&buf)
+	}
+
+	writeLine(&buf, "END:VCALENDAR")
+
+	return buf.Bytes()
+}
+
+func (e Event) marshal(buf *bytes.Buffer) {
+	stamp := e.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	writeLine(buf, "BEGIN:VEVENT")
+	writeLine(buf, "UID:"+e.UID)
+	writeLine(buf, "DTSTAMP:"+formatTime(stamp))
+	writeLine(buf, "DTSTART:"+formatTime(e.Start))
+	if !e.End.IsZero() {
+		writeLine(buf, "DTEND:"+formatTime(e.End))
+	}
+	if !e.LastModified.IsZero() {
+		writeLine(buf, "LAST-MODIFIED:"+formatTime(e.LastModified))
+	}
+	writeLine(buf, "SUMMARY:"+escapeText(e.Summary))
+	if e.Description != "" {
+		writeLine(buf, "DESCRIPTION:"+escapeText(e.Description))
+	}
+	if e.Location != "" {
+		writeLine(buf, "LOCATION:"+escapeText(e.Location))
+	}
+	if e.URL != "" {
+		writeLine(buf, "URL:"+e.URL)
+	}
+	writeLine(buf, "END:VEVENT")
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(_dateTimeUTC)
+}
+
+var _textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+func escapeText(s string) string {
+	return _textEscaper.Replace(s)
+}
+
+// writeLine folds content lines longer than 75 octets without splitting
+// multi-byte characters: continuation lines start with a single space.
+func writeLine(buf *bytes.Buffer, line string) {
+	limit := _maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space counts towards the limit of continuation lines.
+		limit = _maxLineOctets - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
@@ -0,0 +1,69 @@
+// Package markdown renders Markdown to HTML that is safe to embed in pages.
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// Renderer converts Markdown (CommonMark with GitHub tables, strikethrough and
+// autolinks) to HTML and passes the result through an allow-list sanitizer.
+// Raw HTML in the source is dropped, so scripts, styles and event handlers never
+// reach the output.
+type Renderer struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+}
+
+func New() *Renderer {
+	return &Renderer{
+		md: goldmark.New(
+			goldmark.WithExtensions(extension.Table, extension.Strikethrough, extension.Linkify),
+			goldmark.WithRendererOptions(html.WithHardWraps()),
+		),
+		policy: newPolicy(),
+	}
+}
+
+// Render returns the sanitized HTML for src.
+func (r *Renderer) Render(src string) (string, error) {
+	var buf bytes.Buffer
+	if err := r.md.Convert([]byte(src), &buf); err != nil {
+		return "", fmt.Errorf("markdown - Render - md.Convert: %w", err)
+	}
+
+	return r.policy.Sanitize(buf.String()), nil
+}
+
+// _imageSrc allows uploaded images and images hosted elsewhere over https.
+var _imageSrc = regexp.MustCompile(`^(/uploads/[^/]+|https://\S+)$`)
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+
+	p.AllowElements(
+		"p", "br", "hr", "h1", "h2", "h3", "h4", "h5", "h6",
+		"strong", "em", "del", "code", "pre", "blockquote",
+		"ul", "ol", "li",
+		"table", "thead", "tbody", "tr", "th", "td",
+	)
+	p.AllowAttrs("start").Matching(bluemonday.Integer).OnElements("ol")
+	p.AllowAttrs("align").Matching(regexp.MustCompile(`^(left|center|right)$`)).OnElements("th", "td")
+
+	p.AllowStandardURLs()
+	p.AllowURLSchemes("http", "https", "mailto")
+	p.AllowAttrs("href", "title").OnElements("a")
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+
+	p.AllowAttrs("src").Matching(_imageSrc).OnElements("img")
+	p.AllowAttrs("alt", "title").OnElements("img")
+
+	return p
+}
@@ -1,7 +1,9 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/logger"
@@ -9,8 +11,36 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware(l logger.Interface, secret string) gin.HandlerFunc {
+const (
+	APIKeyHeader = "X-API-Key"
+
+	actorKey  = "auth_actor"
+	scopesKey = "auth_scopes"
+
+	AdminActor = "admin"
+)
+
+// APIKeyChecker resolves a raw API key into the key name and granted scopes.
+type APIKeyChecker interface {
+	CheckAPIKey(ctx context.Context, key string) (string, []string, error)
+}
+
+func AuthMiddleware(l logger.Interface, secret string, keys APIKeyChecker) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
+		if apiKey := ctx.GetHeader(APIKeyHeader); apiKey != "" {
+			name, scopes, err := keys.CheckAPIKey(ctx.Request.Context(), apiKey)
+			if err != nil {
+				l.Error(err, "http - middleware - AuthMiddleware: invalid api key")
+				ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid api key"})
+				return
+			}
+
+			ctx.Set(actorKey, "api_key:"+name)
+			ctx.Set(scopesKey, scopes)
+			ctx.Next()
+			return
+		}
+
 		authHeader := ctx.GetHeader("Authorization")
 		if authHeader == "" {
 			l.Error(nil, "http - middleware - AuthMiddleware: empty auth header")
@@ -27,13 +57,63 @@ func AuthMiddleware(l logger.Interface, secret string) gin.HandlerFunc {
 
 		token, err := jwt.Parse(headerParts[1], func(token *jwt.Token) (interface{}, error) {
 			return []byte(secret), nil
-		})
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 		if err != nil || !token.Valid {
 			l.Error(err, "http - middleware - AuthMiddleware: invalid token")
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
 			return
 		}
 
+		// two-factor challenge tokens are signed with the same secret but must not grant access
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if admin, _ := claims["admin"].(bool); !ok || !admin {
+			l.Error(nil, "http - middleware - AuthMiddleware: token is not an admin access token")
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			return
+		}
+
+		ctx.Set(actorKey, AdminActor)
 		ctx.Next()
 	}
 }
+
+// RequireScope rejects API keys without the scope. Admin tokens are allowed everything.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		value, exists := ctx.Get(scopesKey)
+		if !exists {
+			ctx.Next()
+			return
+		}
+
+		scopes, _ := value.([]string)
+		if !slices.Contains(scopes, scope) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "api key lacks scope " + scope})
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// RequireAdmin rejects API keys, leaving the route to admin tokens only.
+func RequireAdmin() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if _, exists := ctx.Get(scopesKey); exists {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin token required"})
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// Actor returns who is authenticated for the request: "admin" or "api_key:<name>".
+func Actor(ctx *gin.Context) string {
+	return ctx.GetString(actorKey)
+}
+
+// SetActor marks the request as authenticated by other means, e.g. an admin panel session.
+func SetActor(ctx *gin.Context, actor string) {
+	ctx.Set(actorKey, actor)
+}
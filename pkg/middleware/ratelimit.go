@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit rejects requests from a client IP that exceeded the limiter with 429.
+func RateLimit(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !limiter.Allow(ctx.ClientIP()) {
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "too many requests"})
+			return
+		}
+		ctx.Next()
+	}
+}
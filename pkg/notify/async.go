@@ -0,0 +1,161 @@
+package notify
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	_defaultAttempts  = 5
+	_defaultBackoff   = 2 * time.Second
+	_defaultTimeout   = 15 * time.Second
+	_defaultQueueSize = 100
+)
+
+// Channel is a named notifier; the name shows up in delivery errors.
+type Channel struct {
+	Name     string
+	Notifier Notifier
+}
+
+type delivery struct {
+	channel Channel
+	msg     Message
+}
+
+// Async fans messages out to its channels in a background goroutine, so Notify
+// never blocks the caller. A failed delivery is retried with exponential backoff,
+// separately for every channel.
+type Async struct {
+	channels []Channel
+	queue    chan delivery
+	attempts int
+	backoff  time.Duration
+	timeout  time.Duration
+	onError  func(channel string, msg Message, err error)
+	stop     chan struct{}
+	wg       sync.WaitGroup
+}
+
+type AsyncOption func(*Async)
+
+// Attempts sets how many times a delivery is tried before it is dropped.
+func Attempts(attempts int) AsyncOption {
+	return func(a *Async) {
+		a.attempts = attempts
+	}
+}
+
+// Backoff sets the pause before the first retry; it doubles after every failure.
+func Backoff(backoff time.Duration) AsyncOption {
+	return func(a *Async) {
+		a.backoff = backoff
+	}
+}
+
+// Timeout limits a single delivery attempt.
+func Timeout(timeout time.Duration) AsyncOption {
+	return func(a *Async) {
+		a.timeout = timeout
+	}
+}
+
+func QueueSize(size int) AsyncOption {
+	return func(a *Async) {
+		a.queue = make(chan delivery, size)
+	}
+}
+
+// OnError is called for failed attempts and for messages dropped because the queue is full.
+func OnError(fn func(channel string, msg Message, err error)) AsyncOption {
+	return func(a *Async) {
+		a.onError = fn
+	}
+}
+
+func NewAsync(channels []Channel, opts ...AsyncOption) *Async {
+	a := &Async{
+		channels: channels,
+		queue:    make(chan delivery, _defaultQueueSize),
+		attempts: _defaultAttempts,
+		backoff:  _defaultBackoff,
+		timeout:  _defaultTimeout,
+		onError:  func(string, Message, error) {},
+		stop:     make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	a.start()
+
+	return a
+}
+
+// Notify queues msg for every channel and returns right away. The caller's
+// context is not used for delivery, it usually ends with the request.
+func (a *Async) Notify(_ context.Context, msg Message) error {
+	for _, channel := range a.channels {
+		select {
+		case a.queue <- delivery{channel: channel, msg: msg}:
+		default:
+			a.onError(channel.Name, msg, ErrQueueFull)
+		}
+	}
+	return nil
+}
+
+func (a *Async) start() {
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+
+		for {
+			select {
+			case <-a.stop:
+				return
+			case d := <-a.queue:
+				// retries wait in their own goroutine so one slow channel doesn't hold up the rest
+				a.wg.Add(1)
+				go func() {
+					defer a.wg.Done()
+					a.deliver(d)
+				}()
+			}
+		}
+	}()
+}
+
+func (a *Async) deliver(d delivery) {
+	backoff := a.backoff
+
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+		err := d.channel.Notifier.Notify(ctx, d.msg)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		a.onError(d.channel.Name, d.msg, err)
+		if attempt >= a.attempts {
+			return
+		}
+
+		select {
+		case <-a.stop:
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// Stop abandons pending retries and waits for running deliveries to finish.
+// Messages still in the queue are dropped.
+func (a *Async) Stop() {
+	close(a.stop)
+	a.wg.Wait()
+}
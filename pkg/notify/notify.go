@@ -0,0 +1,31 @@
+// Package notify delivers short messages about events to staff channels.
+// It ships Telegram and generic webhook channels; any Notifier can be added as a channel.
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const _defaultHTTPTimeout = 10 * time.Second
+
+// Message is one notification. Channels render Title and Text; webhooks also get Event and Data.
+type Message struct {
+	Event string         `json:"event"`
+	Title string         `json:"title"`
+	Text  string         `json:"text"`
+	Data  map[string]any `json:"data,omitempty"`
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+func defaultHTTPClient() *http.Client {
+	return &http.Client{Timeout: _defaultHTTPTimeout}
+}
+
+// ErrQueueFull is reported through OnError when Async can't take more messages.
+var ErrQueueFull = errors.New("notification queue is full")
@@ -0,0 +1,97 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const _telegramAPIURL = "https://api.telegram.org"
+
+// Telegram posts messages to a chat through the Bot API sendMessage method.
+type Telegram struct {
+	apiURL string
+	token  string
+	chatID string
+	client *http.Client
+}
+
+type TelegramOption func(*Telegram)
+
+// TelegramAPIURL points the notifier at another Bot API server, e.g. a local stand-in.
+func TelegramAPIURL(url string) TelegramOption {
+	return func(t *Telegram) {
+		t.apiURL = strings.TrimRight(url, "/")
+	}
+}
+
+func TelegramClient(client *http.Client) TelegramOption {
+	return func(t *Telegram) {
+		t.client = client
+	}
+}
+
+func NewTelegram(token, chatID string, opts ...TelegramOption) *Telegram {
+	t := &Telegram{
+		apiURL: _telegramAPIURL,
+		token:  token,
+		chatID: chatID,
+		client: defaultHTTPClient(),
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+func (t *Telegram) Notify(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(map[string]any{
+		"chat_id":                  t.chatID,
+		"text":                     msg.Title + "\n\n" + msg.Text,
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return fmt.Errorf("notify - Telegram - json.Marshal: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/bot%s/sendMessage", t.apiURL, t.token)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("notify - Telegram - http.NewRequest: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		// the error text contains the url and with it the bot token
+		return fmt.Errorf("notify - Telegram - sendMessage: %w", redact(err, t.token))
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || !result.OK {
+		return fmt.Errorf("notify - Telegram - sendMessage: status %d: %s", resp.StatusCode, result.Description)
+	}
+
+	return nil
+}
+
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e redactedError) Error() string { return e.msg }
+func (e redactedError) Unwrap() error { return e.err }
+
+func redact(err error, secret string) error {
+	return redactedError{msg: strings.ReplaceAll(err.Error(), secret, "***"), err: err}
+}
@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/alexKudryavtsev-web/beyond-limits-app/pkg/webhooksig"
+)
+
+// Webhook posts the message as JSON to a URL.
+type Webhook struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+type WebhookOption func(*Webhook)
+
+// WebhookSecret makes the notifier sign every request as described in package webhooksig.
+func WebhookSecret(secret string) WebhookOption {
+	return func(w *Webhook) {
+		w.secret = secret
+	}
+}
+
+func WebhookClient(client *http.Client) WebhookOption {
+	return func(w *Webhook) {
+		w.client = client
+	}
+}
+
+func NewWebhook(url string, opts ...WebhookOption) *Webhook {
+	w := &Webhook{
+		url:    url,
+		client: defaultHTTPClient(),
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+func (w *Webhook) Notify(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("notify - Webhook - json.Marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("notify - Webhook - http.NewRequest: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.secret != "" {
+		webhooksig.SignRequest(req, w.secret, body, time.Now())
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notify - Webhook - client.Do: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notify - Webhook - unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
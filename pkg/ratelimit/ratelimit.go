@@ -0,0 +1,66 @@
+// Package ratelimit implements an in-memory fixed window rate limiter keyed by string.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type window struct {
+	start time.Time
+	count int
+}
+
+// Limiter allows up to limit events per key within each window.
+type Limiter struct {
+	mu      sync.Mutex
+	limit   int
+	period  time.Duration
+	windows map[string]*window
+	now     func() time.Time
+	swept   time.Time
+}
+
+func New(limit int, period time.Duration) *Limiter {
+	return &Limiter{
+		limit:   limit,
+		period:  period,
+		windows: make(map[string]*window),
+		now:     time.Now,
+	}
+}
+
+// Allow registers an event for key and reports whether it fits into the limit.
+func (l *Limiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.period {
+		l.windows[key] = &window{start: now, count: 1}
+		return true
+	}
+
+	if w.count >= l.limit {
+		return false
+	}
+	w.count++
+	return true
+}
+
+// sweep drops expired windows at most once per period so the map doesn't grow unbounded.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < l.period {
+		return
+	}
+	l.swept = now
+
+	for key, w := range l.windows {
+		if now.Sub(w.start) >= l.period {
+			delete(l.windows, key)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Package scheduler runs a job periodically in a background goroutine.
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// Job is a unit of periodic work. The context is cancelled on Stop.
+type Job func(ctx context.Context)
+
+type Scheduler struct {
+	interval time.Duration
+	job      Job
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// New starts running job right away and then every interval until Stop is called.
+func New(interval time.Duration, job Job) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &Scheduler{
+		interval: interval,
+		job:      job,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+
+	s.start(ctx)
+
+	return s
+}
+
+func (s *Scheduler) start(ctx context.Context) {
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			s.job(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop cancels the running job and waits for the goroutine to exit.
+func (s *Scheduler) Stop() {
+	s.cancel()
+	<-s.done
+}
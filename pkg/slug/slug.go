@@ -0,0 +1,79 @@
+// Package slug makes URL slugs from titles: Cyrillic is transliterated to Latin,
+// everything except letters and digits becomes a single hyphen.
+package slug
+
+import "strings"
+
+// MaxLength is the longest slug Make returns, in bytes.
+const MaxLength = 80
+
+var _cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+	// Ukrainian and Belarusian letters
+	'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", 'ў': "u",
+}
+
+// Make returns the slug for s, e.g. "Закат над Невой" becomes "zakat-nad-nevoy".
+// The result may be empty if s has no letters or digits.
+func Make(s string) string {
+	var b strings.Builder
+	hyphen := false
+
+	write := func(part string) {
+		if hyphen && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		hyphen = false
+		b.WriteString(part)
+	}
+
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			write(string(r))
+		default:
+			latin, ok := _cyrillic[r]
+			if !ok {
+				hyphen = true
+				continue
+			}
+			// hard and soft signs vanish without splitting the word
+			if latin != "" {
+				write(latin)
+			}
+		}
+	}
+
+	return truncate(b.String())
+}
+
+// truncate cuts a long slug at the last hyphen that fits, so words stay whole.
+func truncate(s string) string {
+	if len(s) <= MaxLength {
+		return s
+	}
+
+	s = s[:MaxLength]
+	if i := strings.LastIndexByte(s, '-'); i > 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// Numeric reports whether s consists of digits only. Such slugs would be
+// indistinguishable from IDs in URLs.
+func Numeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
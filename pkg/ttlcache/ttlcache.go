@@ -0,0 +1,77 @@
+// Package ttlcache implements a small in-memory cache whose entries expire after a fixed time.
+package ttlcache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// Cache keeps up to size values keyed by string, each for ttl after it was set.
+type Cache[V any] struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	size    int
+	entries map[string]entry[V]
+	now     func() time.Time
+	swept   time.Time
+}
+
+func New[V any](ttl time.Duration, size int) *Cache[V] {
+	return &Cache[V]{
+		ttl:     ttl,
+		size:    size,
+		entries: make(map[string]entry[V]),
+		now:     time.Now,
+	}
+}
+
+// Get returns the value stored for key unless it has expired.
+func (c *Cache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || !c.now().Before(e.expires) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set stores value for key. When the cache is full and nothing has expired,
+// the value isn't stored: a miss is cheaper than unbounded growth.
+func (c *Cache[V]) Set(key string, value V) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.sweep(now)
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.size {
+		return
+	}
+	c.entries[key] = entry[V]{value: value, expires: now.Add(c.ttl)}
+}
+
+// sweep drops expired entries at most once per ttl, or whenever the cache is full.
+func (c *Cache[V]) sweep(now time.Time) {
+	if now.Sub(c.swept) < c.ttl && len(c.entries) < c.size {
+		return
+	}
+	c.swept = now
+
+	for key, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, key)
+		}
+	}
+}